@@ -0,0 +1,86 @@
+/*
+Copyright 2025 The bpfman Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// BpfPriorityClassSpec defines the range of priorities of a BpfPriorityClass
+// and the namespaces allowed to use it.
+// +kubebuilder:validation:XValidation:rule="self.minPriority <= self.maxPriority",message="minPriority must not be greater than maxPriority"
+// +kubebuilder:validation:XValidation:rule="!has(self.defaultPriority) || (self.defaultPriority >= self.minPriority && self.defaultPriority <= self.maxPriority)",message="defaultPriority must be between minPriority and maxPriority"
+type BpfPriorityClassSpec struct {
+	// minPriority is the lowest priority value, i.e., the highest precedence,
+	// that links of the class can use.
+	// +required
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=1000
+	MinPriority int32 `json:"minPriority"`
+
+	// maxPriority is the highest priority value, i.e., the lowest precedence,
+	// that links of the class can use.
+	// +required
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=1000
+	MaxPriority int32 `json:"maxPriority"`
+
+	// defaultPriority is an optional field and is the priority of the links of
+	// the class that don't set a priority. If not provided, it defaults to
+	// minPriority.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=1000
+	DefaultPriority *int32 `json:"defaultPriority,omitempty"`
+
+	// allowedNamespaces is an optional list of the namespaces whose
+	// BpfApplications can use the class. ClusterBpfApplications can always
+	// use it. If not provided, only ClusterBpfApplications can use the class.
+	// +optional
+	// +listType=set
+	AllowedNamespaces []string `json:"allowedNamespaces,omitempty"`
+}
+
+// +genclient
+// +genclient:nonNamespaced
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster
+
+// BpfPriorityClass maps a name to a range of XDP, TC and TCX priorities. The
+// links of a ClusterBpfApplication or BpfApplication reference it with
+// priorityClassName instead of choosing a raw priority, so that the
+// applications of different teams are kept apart on the shared dispatchers.
+// The links of a BpfApplication can't choose a raw priority in the range of a
+// class its namespace is not allowed to use, nor leave the priority unset when
+// the default priority is in that range.
+// +kubebuilder:printcolumn:name="MinPriority",type=integer,JSONPath=".spec.minPriority"
+// +kubebuilder:printcolumn:name="MaxPriority",type=integer,JSONPath=".spec.maxPriority"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+type BpfPriorityClass struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec BpfPriorityClassSpec `json:"spec"`
+}
+
+// +kubebuilder:object:root=true
+// BpfPriorityClassList contains a list of BpfPriorityClass objects
+type BpfPriorityClassList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []BpfPriorityClass `json:"items"`
+}
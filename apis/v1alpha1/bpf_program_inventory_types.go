@@ -0,0 +1,189 @@
+/*
+Copyright 2025 The bpfman Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// BpfProgramInventoryStatus lists the unmanaged eBPF programs and the
+// dispatcher chains of a node.
+type BpfProgramInventoryStatus struct {
+	// node is the name of the Kubernetes node.
+	Node string `json:"node"`
+	// programs are the eBPF programs loaded on the node that don't belong to
+	// a ClusterBpfApplication or BpfApplication, sorted by ID. They were
+	// loaded directly through bpfman or by other tools.
+	// +listType=atomic
+	// +optional
+	Programs []UnmanagedProgram `json:"programs,omitempty"`
+	// dispatchers are the XDP and TC dispatcher chains of the node, with the
+	// programs of the ClusterBpfApplications and BpfApplications attached to
+	// them, sorted by network namespace, interface and hook. They are built
+	// from the links recorded in the application states, not read from
+	// bpfman, so the order of their programs is inferred.
+	// +listType=atomic
+	// +optional
+	Dispatchers []DispatcherChain `json:"dispatchers,omitempty"`
+	// conditions reports the PriorityConflict condition, which is True when
+	// programs of different applications are attached to a dispatcher with
+	// the same priority, in which case their order is decided by bpfman.
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+}
+
+// DispatcherHook is the hook of an XDP or TC dispatcher.
+// +kubebuilder:validation:Enum=xdp;tc-ingress;tc-egress
+type DispatcherHook string
+
+const (
+	DispatcherHookXDP       DispatcherHook = "xdp"
+	DispatcherHookTCIngress DispatcherHook = "tc-ingress"
+	DispatcherHookTCEgress  DispatcherHook = "tc-egress"
+)
+
+// DispatcherChain is the list of the programs attached to the dispatcher of
+// a hook of an interface, in their inferred order.
+type DispatcherChain struct {
+	// interfaceName is the name of the interface.
+	InterfaceName string `json:"interfaceName"`
+	// netnsPath is the network namespace of the interface. It is empty for
+	// the interfaces of the host network namespace.
+	// +optional
+	NetnsPath string `json:"netnsPath,omitempty"`
+	// hook is the hook of the dispatcher.
+	Hook DispatcherHook `json:"hook"`
+	// programs are the programs attached to the dispatcher, in the order
+	// they are expected to run, as inferred from their priorities. bpfman
+	// decides the order of programs with the same priority, which may differ
+	// from the inferred one.
+	// +listType=atomic
+	Programs []DispatcherProgram `json:"programs"`
+}
+
+// DispatcherProgram is a program attached to a dispatcher.
+type DispatcherProgram struct {
+	// position is the inferred index of the program in the dispatcher chain,
+	// starting at 0. Programs are ordered by priority, and programs with the
+	// same priority by link ID, which is not necessarily the order bpfman
+	// runs them in.
+	Position int32 `json:"position"`
+	// applicationKind is ClusterBpfApplication or BpfApplication.
+	ApplicationKind string `json:"applicationKind"`
+	// applicationNamespace is the namespace of a BpfApplication.
+	// +optional
+	ApplicationNamespace string `json:"applicationNamespace,omitempty"`
+	// applicationName is the name of the application.
+	ApplicationName string `json:"applicationName"`
+	// program is the name of the program.
+	Program string `json:"program"`
+	// linkId is the bpfman link ID of the attachment.
+	LinkId uint32 `json:"linkId"`
+	// priority is the priority of the attachment.
+	Priority int32 `json:"priority"`
+	// proceedOn are the return values of the program on which the next
+	// program of the chain runs.
+	// +listType=atomic
+	// +optional
+	ProceedOn []string `json:"proceedOn,omitempty"`
+}
+
+// BpfProgramInventoryConditionType is the type of a BpfProgramInventory
+// condition.
+type BpfProgramInventoryConditionType string
+
+const (
+	// BpfProgramInventoryConditionPriorityConflict is True when programs of
+	// different applications share a priority on a dispatcher.
+	BpfProgramInventoryConditionPriorityConflict BpfProgramInventoryConditionType = "PriorityConflict"
+)
+
+// UnmanagedProgram is an eBPF program loaded on a node outside of the
+// bpfman-operator.
+type UnmanagedProgram struct {
+	// id is the kernel ID of the program.
+	ID uint32 `json:"id"`
+	// name is the name of the program as seen by the kernel, which may be
+	// truncated.
+	// +optional
+	Name string `json:"name,omitempty"`
+	// type is the kernel program type, e.g., xdp or tracepoint.
+	// +optional
+	Type string `json:"type,omitempty"`
+	// tag is the hash of the program instructions.
+	// +optional
+	Tag string `json:"tag,omitempty"`
+	// loadedAt is when the program was loaded.
+	// +optional
+	LoadedAt string `json:"loadedAt,omitempty"`
+	// mapIDs are the kernel IDs of the maps used by the program.
+	// +listType=atomic
+	// +optional
+	MapIDs []uint32 `json:"mapIDs,omitempty"`
+	// loadedByBpfman is true when the program was loaded through bpfman,
+	// which is required to adopt it.
+	// +optional
+	LoadedByBpfman bool `json:"loadedByBpfman,omitempty"`
+	// uuid is the bpfman.io/uuid metadata of a program loaded through
+	// bpfman. A ClusterBpfApplication adopts the programs with this UUID by
+	// setting adoptUUID.
+	// +optional
+	UUID string `json:"uuid,omitempty"`
+	// metadata is the metadata a program loaded through bpfman was loaded
+	// with, which usually identifies its owner.
+	// +optional
+	Metadata map[string]string `json:"metadata,omitempty"`
+	// linkIDs are the IDs of the bpfman links attaching a program loaded
+	// through bpfman.
+	// +listType=atomic
+	// +optional
+	LinkIDs []uint32 `json:"linkIDs,omitempty"`
+}
+
+// +genclient
+// +genclient:nonNamespaced
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster
+
+// BpfProgramInventory lists the eBPF programs loaded on a Kubernetes node that
+// are not managed by the bpfman-operator, and the XDP and TC dispatcher chains
+// of the node. The bpfman agent of each node
+// periodically refreshes the BpfProgramInventory named after its node.
+// +kubebuilder:printcolumn:name="Node",type=string,JSONPath=".status.node"
+// +kubebuilder:printcolumn:name="PriorityConflict",type=string,JSONPath=".status.conditions[?(@.type=='PriorityConflict')].status"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+type BpfProgramInventory struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// status lists the unmanaged eBPF programs and the dispatcher chains of the
+	// node.
+	Status BpfProgramInventoryStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// BpfProgramInventoryList contains a list of BpfProgramInventory objects
+type BpfProgramInventoryList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []BpfProgramInventory `json:"items"`
+}
@@ -294,6 +294,18 @@ type ClBpfApplicationSpec struct {
 	// +required
 	// +kubebuilder:validation:MinItems:=1
 	Programs []ClBpfApplicationProgram `json:"programs"`
+
+	// adoptUUID is an optional field used to adopt programs that were loaded
+	// directly through bpfman, as listed in the BpfProgramInventory of their
+	// node, instead of loading the bytecode. On each selected node, the
+	// programs loaded with the bpfman.io/uuid metadata set to adoptUUID become
+	// the programs of this instance: they must match its programs by name, and
+	// they are unloaded when this instance is deleted. On nodes where no
+	// program has this UUID, the bytecode is loaded. The programs of a
+	// ClusterBpfApplicationState or BpfApplicationState, whose UID is their
+	// UUID, can't be adopted.
+	// +optional
+	AdoptUUID string `json:"adoptUUID,omitempty"`
 }
 
 // +genclient
@@ -73,6 +73,13 @@ type ClTcxAttachInfo struct {
 	// +kubebuilder:validation:Minimum=0
 	// +kubebuilder:validation:Maximum=1000
 	Priority *int32 `json:"priority,omitempty"`
+
+	// priorityClassName is an optional field and is the name of the
+	// BpfPriorityClass that determines the priority of the TCX program. When it
+	// is set, priority must be within the range of the class, and if priority is
+	// not provided, the defaultPriority of the class is used.
+	// +optional
+	PriorityClassName string `json:"priorityClassName,omitempty"`
 }
 
 type ClTcxProgramInfoState struct {
@@ -97,6 +104,13 @@ type ClTcxAttachInfoState struct {
 	// +optional
 	NetnsPath string `json:"netnsPath,omitempty"`
 
+	// pod is the pod in whose network namespace the link is attached, when the
+	// link was created for a pod selected by networkNamespaces. The link is
+	// attached again when the sandbox of the pod is recreated, and detached
+	// once the pod is gone.
+	// +optional
+	Pod *AttachPodState `json:"pod,omitempty"`
+
 	// direction is the provisioned direction of traffic, Ingress or Egress, the TC
 	// program should be attached for a given network device.
 	// +required
@@ -110,4 +110,11 @@ type ClUprobeAttachInfoState struct {
 	// attachment point is attached.
 	// +optional
 	ContainerPid *int32 `json:"containerPid,omitempty"`
+
+	// pod is the pod of the container the link is attached in, when the link
+	// was created for a container selected by containers. The link is attached
+	// again when the sandbox of the pod is recreated, and detached once the pod
+	// is gone.
+	// +optional
+	Pod *AttachPodState `json:"pod,omitempty"`
 }
@@ -20,6 +20,22 @@ package v1alpha1
 // +kubebuilder:validation:Enum:=Aborted;Drop;Pass;TX;ReDirect;DispatcherReturn;
 type XdpProceedOnValue string
 
+// XdpAttachMode is the mode in which the XDP programs of an interface run.
+type XdpAttachMode string
+
+const (
+	// XdpAttachModeNative runs the programs in the driver of the interface.
+	XdpAttachModeNative XdpAttachMode = "Native"
+	// XdpAttachModeGeneric runs the programs in the generic network path of the
+	// kernel, which works on any interface but is slower.
+	XdpAttachModeGeneric XdpAttachMode = "Generic"
+	// XdpAttachModeOffloadIfSupported runs the programs on the NIC when it
+	// supports XDP offload, and in the driver otherwise.
+	XdpAttachModeOffloadIfSupported XdpAttachMode = "OffloadIfSupported"
+	// XdpAttachModeOffload is only reported: the programs run on the NIC.
+	XdpAttachModeOffload XdpAttachMode = "Offload"
+)
+
 type ClXdpProgramInfo struct {
 	// links is an optional field and is the list of attachment points to which the
 	// XDP program should be attached. The XDP program is loaded in kernel memory
@@ -63,6 +79,38 @@ type ClXdpAttachInfo struct {
 	// +kubebuilder:validation:Maximum=1000
 	Priority *int32 `json:"priority,omitempty"`
 
+	// priorityClassName is an optional field and is the name of the
+	// BpfPriorityClass that determines the priority of the XDP program. When it
+	// is set, priority must be within the range of the class, and if priority is
+	// not provided, the defaultPriority of the class is used.
+	// +optional
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+
+	// attachMode is an optional field and is the mode in which the XDP program
+	// must run on the interface. Allowed values are:
+	//   Native, Generic, OffloadIfSupported
+	//
+	// Native runs the program in the driver of the interface, Generic in the
+	// generic network path of the kernel, and OffloadIfSupported on the NIC when
+	// it supports XDP offload and in the driver otherwise. All the XDP programs
+	// of an interface share the mode of its dispatcher, which is set by the
+	// xdp_mode of the interface in the bpfman configuration, so the link is
+	// detached and reported with an AttachModeError status when the mode
+	// obtained on the interface is not the requested one. If not provided, any
+	// mode is accepted.
+	// +optional
+	// +kubebuilder:validation:Enum=Native;Generic;OffloadIfSupported
+	AttachMode XdpAttachMode `json:"attachMode,omitempty"`
+
+	// fragsAware is an optional field and declares that the XDP program handles
+	// packets spanning multiple buffers, as received on interfaces whose MTU
+	// doesn't fit in a single page. When false, the link is detached and
+	// reported with an AttachModeError status if the program runs in native or
+	// offload mode on such an interface. Generic mode always provides
+	// single-buffer packets. Default is false.
+	// +optional
+	FragsAware bool `json:"fragsAware,omitempty"`
+
 	// proceedOn is an optional field and allows the user to call other XDP
 	// programs in a chain, or not call the next program in a chain based on the
 	// exit code of an XDP program. Allowed values, which are the possible exit
@@ -100,6 +148,13 @@ type ClXdpAttachInfoState struct {
 	// +optional
 	NetnsPath string `json:"netnsPath,omitempty"`
 
+	// pod is the pod in whose network namespace the link is attached, when the
+	// link was created for a pod selected by networkNamespaces. The link is
+	// attached again when the sandbox of the pod is recreated, and detached
+	// once the pod is gone.
+	// +optional
+	Pod *AttachPodState `json:"pod,omitempty"`
+
 	// priority is the provisioned priority of the XDP program in relation to other
 	// programs of the same type with the same attach point. It is a value from 0
 	// to 1000, where lower values have higher precedence.
@@ -113,4 +168,19 @@ type ClXdpAttachInfoState struct {
 	// chain based on the exit code of a TC program .Multiple values are supported.
 	// +required
 	ProceedOn []XdpProceedOnValue `json:"proceedOn"`
+
+	// attachMode is the provisioned attachMode.
+	// +optional
+	AttachMode XdpAttachMode `json:"attachMode,omitempty"`
+
+	// fragsAware is the provisioned fragsAware.
+	// +optional
+	FragsAware bool `json:"fragsAware,omitempty"`
+
+	// obtainedAttachMode is the mode in which the XDP programs run on the
+	// interface, i.e., Native, Generic or Offload, as last reported by the
+	// kernel. It is kept when the link has an AttachModeError status, to show
+	// the mode that was obtained instead of the requested one.
+	// +optional
+	ObtainedAttachMode XdpAttachMode `json:"obtainedAttachMode,omitempty"`
 }
@@ -17,6 +17,7 @@ limitations under the License.
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -27,6 +28,9 @@ import (
 // +kubebuilder:resource:scope=Cluster
 
 // Config holds the configuration for bpfman-operator.
+// +kubebuilder:printcolumn:name="Available",type="string",JSONPath=".status.conditions[?(@.type=='Available')].status"
+// +kubebuilder:printcolumn:name="Progressing",type="string",JSONPath=".status.conditions[?(@.type=='Progressing')].status"
+// +kubebuilder:printcolumn:name="Degraded",type="string",JSONPath=".status.conditions[?(@.type=='Degraded')].status"
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 type Config struct {
 	metav1.TypeMeta   `json:",inline"`
@@ -41,11 +45,11 @@ type ConfigSpec struct {
 	// Agent holds the configuration for the bpfman agent.
 	// +required
 	Agent AgentSpec `json:"agent,omitempty"`
-	// Configuration holds the content of bpfman.toml.
-	// +required
-	// +kubebuilder:validation:Required
-	// +kubebuilder:validation:MinLength=1
-	Configuration string `json:"configuration"`
+	// Configuration holds the content of bpfman.toml. It is an escape hatch
+	// for settings that have no typed field in daemon: it is parsed and
+	// validated, and the typed daemon settings are applied on top of it.
+	// +optional
+	Configuration string `json:"configuration,omitempty"`
 	// Daemon holds the configuration for the bpfman daemon.
 	// +required
 	Daemon DaemonSpec `json:"daemon,omitempty"`
@@ -53,6 +57,25 @@ type ConfigSpec struct {
 	// deployed.
 	Namespace string `json:"namespace,omitempty"`
 
+	// nodePools overrides the daemon configuration on sets of nodes. The
+	// operator deploys one bpfman DaemonSet per pool, and the default
+	// DaemonSet runs on the nodes that don't belong to any pool. Pools must
+	// not overlap: the node selectors of any two pools must set a common
+	// label to different values.
+	// +listType=map
+	// +listMapKey=name
+	// +kubebuilder:validation:MaxItems=16
+	// +optional
+	NodePools []NodePool `json:"nodePools,omitempty"`
+	// metricsProxy holds the configuration for the metrics-proxy
+	// DaemonSet, deployed when the cluster supports monitoring.
+	// +optional
+	MetricsProxy MetricsProxySpec `json:"metricsProxy,omitempty"`
+	// uninstall configures how bpfman is removed from the nodes when the
+	// Config is deleted.
+	// +optional
+	Uninstall UninstallSpec `json:"uninstall,omitempty"`
+
 	// overrides is a list of overrides for components that are managed by
 	// the operator. Marking a component as unmanaged will prevent
 	// the operator from creating or updating the object. This is intended
@@ -81,6 +104,216 @@ type DaemonSpec struct {
 	// LogLevel holds the log level for the bpfman daemon.
 	// +optional
 	LogLevel string `json:"logLevel,omitempty"`
+	// logTargets overrides logLevel for individual bpfman modules.
+	// +listType=map
+	// +listMapKey=target
+	// +optional
+	LogTargets []LogTarget `json:"logTargets,omitempty"`
+	// database configures how bpfman opens its database.
+	// +optional
+	Database *DatabaseConfig `json:"database,omitempty"`
+	// signing configures the verification of bytecode image signatures.
+	// +optional
+	Signing *SigningConfig `json:"signing,omitempty"`
+	// registry configures the images bpfman pulls from container
+	// registries.
+	// +optional
+	Registry *RegistryConfig `json:"registry,omitempty"`
+	// resources are the compute resources of the bpfman daemon container.
+	// +optional
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+	// tolerations are added to the tolerations of the bpfman DaemonSet,
+	// which runs the bpfman daemon and agent.
+	// +listType=atomic
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+	// nodeSelector restricts the nodes the bpfman DaemonSet runs on.
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	// priorityClassName is the priority class of the bpfman DaemonSet pods.
+	// +optional
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+	// upgrade configures how the bpfman DaemonSet pods are replaced when
+	// the daemon configuration changes.
+	// +optional
+	Upgrade DaemonUpgradeSpec `json:"upgrade,omitempty"`
+}
+
+// DaemonUpgradeStrategy is the strategy used to replace the bpfman daemon
+// pods.
+// +kubebuilder:validation:Enum=RollingUpdate;Controlled
+type DaemonUpgradeStrategy string
+
+const (
+	// DaemonUpgradeRollingUpdate lets the DaemonSet controller replace the
+	// pods.
+	DaemonUpgradeRollingUpdate DaemonUpgradeStrategy = "RollingUpdate"
+	// DaemonUpgradeControlled lets the operator replace the pods node by
+	// node, verifying that the programs loaded on each node survive the
+	// upgrade before moving on to the next nodes.
+	DaemonUpgradeControlled DaemonUpgradeStrategy = "Controlled"
+)
+
+// DaemonUpgradeSpec configures how the bpfman DaemonSet pods are replaced.
+type DaemonUpgradeSpec struct {
+	// strategy is the strategy used to replace the pods. With Controlled,
+	// the operator snapshots the program IDs of the BpfApplicationStates of
+	// a node before replacing its pod, and once the new pod is ready checks
+	// that every BpfApplicationState is back to Success with the same
+	// program IDs. If not, the upgrade is paused and reported by the
+	// UpgradePaused condition of the Config: it resumes once the
+	// applications recover, or can be completed without verification by
+	// switching to RollingUpdate.
+	// +kubebuilder:default=RollingUpdate
+	// +optional
+	Strategy DaemonUpgradeStrategy `json:"strategy,omitempty"`
+	// maxUnavailable is the maximum number of nodes upgraded at the same
+	// time with the Controlled strategy.
+	// +kubebuilder:default=1
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxUnavailable int32 `json:"maxUnavailable,omitempty"`
+}
+
+// UninstallApplicationPolicy decides what happens to the BpfApplications and
+// ClusterBpfApplications when the Config is deleted.
+// +kubebuilder:validation:Enum=Refuse;Drain
+type UninstallApplicationPolicy string
+
+const (
+	// UninstallRefuse keeps the Config, and bpfman, until every
+	// application is deleted.
+	UninstallRefuse UninstallApplicationPolicy = "Refuse"
+	// UninstallDrain deletes every application, and waits for bpfman to
+	// unload their programs.
+	UninstallDrain UninstallApplicationPolicy = "Drain"
+)
+
+// UninstallSpec configures how bpfman is removed from the nodes when the
+// Config is deleted.
+type UninstallSpec struct {
+	// applicationPolicy decides what happens to the BpfApplications and
+	// ClusterBpfApplications when the Config is deleted. With Refuse, the
+	// deletion waits, and the Uninstalling condition lists the remaining
+	// applications. With Drain, they are deleted.
+	// +kubebuilder:default=Refuse
+	// +optional
+	ApplicationPolicy UninstallApplicationPolicy `json:"applicationPolicy,omitempty"`
+	// cleanupNodes runs a Job on every node running bpfman once the
+	// applications are gone. The Job unloads, through bpfman, the programs
+	// it still holds, recording them in the audit log of the agent, and
+	// removes the pins bpfman created under /sys/fs/bpf, before the bpfman
+	// DaemonSets are removed. Once bpfman is stopped, another Job removes
+	// its database from the node.
+	// +kubebuilder:default=true
+	// +optional
+	CleanupNodes *bool `json:"cleanupNodes,omitempty"`
+}
+
+// MetricsProxySpec defines the desired state of the metrics-proxy.
+type MetricsProxySpec struct {
+	// resources are the compute resources of the metrics-proxy container.
+	// +optional
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+	// tolerations are added to the tolerations of the metrics-proxy
+	// DaemonSet.
+	// +listType=atomic
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+	// nodeSelector restricts the nodes the metrics-proxy DaemonSet runs on.
+	// It should select the nodes running the bpfman DaemonSet.
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	// priorityClassName is the priority class of the metrics-proxy pods.
+	// +optional
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+}
+
+// NodePool overrides the daemon configuration on the nodes selected by
+// nodeSelector. Unset fields default to the values of the Config.
+type NodePool struct {
+	// name identifies the pool. The DaemonSet and ConfigMap of the pool are
+	// named after the default ones, suffixed with the pool name.
+	// +required
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=40
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	Name string `json:"name"`
+	// nodeSelector selects the nodes of the pool.
+	// +required
+	// +kubebuilder:validation:MinProperties=1
+	// +kubebuilder:validation:MaxProperties=4
+	NodeSelector map[string]string `json:"nodeSelector"`
+	// image is the image of the bpfman daemon on the nodes of the pool.
+	// +optional
+	Image string `json:"image,omitempty"`
+	// logLevel is the log level of the bpfman daemon on the nodes of the
+	// pool. The logTargets of the daemon still apply.
+	// +optional
+	LogLevel string `json:"logLevel,omitempty"`
+	// configuration holds bpfman.toml settings applied on top of the
+	// configuration of the Config on the nodes of the pool.
+	// +optional
+	Configuration string `json:"configuration,omitempty"`
+	// resources are the compute resources of the bpfman daemon container
+	// on the nodes of the pool.
+	// +optional
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+	// tolerations are added to the tolerations of the bpfman DaemonSet of
+	// the pool, e.g., to run on tainted nodes.
+	// +listType=atomic
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+}
+
+// LogTarget sets the log level of a bpfman module.
+type LogTarget struct {
+	// target is the Rust module path of the module, e.g.,
+	// "bpfman::oci_utils".
+	// +required
+	// +kubebuilder:validation:Pattern=`^[A-Za-z_][A-Za-z0-9_]*(::[A-Za-z_][A-Za-z0-9_]*)*$`
+	Target string `json:"target"`
+	// level is the log level of the module.
+	// +required
+	// +kubebuilder:validation:Enum=off;error;warn;info;debug;trace
+	Level string `json:"level"`
+}
+
+// DatabaseConfig configures how bpfman opens its database. It is rendered
+// into the [database] table of bpfman.toml.
+type DatabaseConfig struct {
+	// maxRetries is the number of attempts made to open the database when
+	// it is locked.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	MaxRetries *int32 `json:"maxRetries,omitempty"`
+	// millisecDelay is the delay, in milliseconds, between attempts to open
+	// the database.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	MillisecDelay *int64 `json:"millisecDelay,omitempty"`
+}
+
+// SigningConfig configures the verification of bytecode image signatures.
+// It is rendered into the [signing] table of bpfman.toml.
+type SigningConfig struct {
+	// allowUnsigned allows loading bytecode images that are not signed.
+	// +optional
+	AllowUnsigned *bool `json:"allowUnsigned,omitempty"`
+	// verifyEnabled enables the verification of bytecode image signatures.
+	// +optional
+	VerifyEnabled *bool `json:"verifyEnabled,omitempty"`
+}
+
+// RegistryConfig configures the images bpfman pulls from container
+// registries. It is rendered into the [registry] table of bpfman.toml.
+type RegistryConfig struct {
+	// xdpDispatcherImage is the image of the XDP dispatcher program.
+	// +optional
+	XdpDispatcherImage string `json:"xdpDispatcherImage,omitempty"`
+	// tcDispatcherImage is the image of the TC dispatcher program.
+	// +optional
+	TcDispatcherImage string `json:"tcDispatcherImage,omitempty"`
 }
 
 // AgentSpec defines the desired state of the bpfman agent.
@@ -99,6 +332,47 @@ type AgentSpec struct {
 	// LogLevel holds the log level for the bpfman agent.
 	// +optional
 	LogLevel string `json:"logLevel,omitempty"`
+	// resources are the compute resources of the bpfman agent container.
+	// The agent runs in the pods of the bpfman DaemonSet, which are
+	// scheduled according to the daemon settings.
+	// +optional
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+	// linkDrift configures the periodic verification that the links of the
+	// applications recorded in the BpfApplicationStates still exist on the
+	// nodes, e.g., after a link was detached by another tool or an interface
+	// was recreated.
+	// +optional
+	LinkDrift LinkDriftSpec `json:"linkDrift,omitempty"`
+}
+
+// LinkDriftMode decides what the bpfman agent does with the links that are
+// missing on its node.
+// +kubebuilder:validation:Enum=Repair;Report
+type LinkDriftMode string
+
+const (
+	// LinkDriftRepair re-attaches the missing links.
+	LinkDriftRepair LinkDriftMode = "Repair"
+	// LinkDriftReport only reports the missing links.
+	LinkDriftReport LinkDriftMode = "Report"
+)
+
+// LinkDriftSpec configures the detection of the links that are missing on a
+// node. Every missing link is counted once in the
+// bpfman_agent_link_drift_total metric of the agent and reported with a
+// DriftDetected event on the BpfApplicationState. A DriftCorrected event
+// follows once it is re-attached.
+type LinkDriftSpec struct {
+	// interval is how often the bpfman agent verifies the links of its node.
+	// Set to 0s to disable the verification.
+	// +kubebuilder:default="5m"
+	// +optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
+	// mode decides whether the missing links are re-attached, with Repair,
+	// or only reported, with Report.
+	// +kubebuilder:default=Repair
+	// +optional
+	Mode LinkDriftMode `json:"mode,omitempty"`
 }
 
 // status reflects the status of the bpfman-operator configuration.
@@ -109,8 +383,125 @@ type ConfigStatus struct {
 	// +listType=map
 	// +listMapKey=type
 	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,1,rep,name=conditions"`
+	// observedGeneration is the generation of the Config that was last
+	// reconciled.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// components reports the readiness of each component deployed by the
+	// operator.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Components []ComponentStatus `json:"components,omitempty"`
+	// daemonUpgrades tracks the controlled upgrades of the bpfman
+	// DaemonSets in progress.
+	// +listType=map
+	// +listMapKey=daemonSet
+	// +optional
+	DaemonUpgrades []DaemonUpgradeStatus `json:"daemonUpgrades,omitempty"`
+}
+
+// DaemonUpgradeStatus tracks the controlled upgrade of a bpfman DaemonSet.
+type DaemonUpgradeStatus struct {
+	// daemonSet is the name of the DaemonSet being upgraded.
+	// +required
+	DaemonSet string `json:"daemonSet"`
+	// revision is the controller revision hash the pods are upgraded to.
+	// +required
+	Revision string `json:"revision"`
+	// nodes are the nodes whose pod has been replaced and whose programs
+	// are being verified.
+	// +listType=map
+	// +listMapKey=node
+	// +optional
+	Nodes []DaemonUpgradeNode `json:"nodes,omitempty"`
 }
 
+// DaemonUpgradeNode holds the programs loaded on a node before its bpfman
+// pod was replaced.
+type DaemonUpgradeNode struct {
+	// node is the name of the node.
+	// +required
+	Node string `json:"node"`
+	// applications are the BpfApplicationStates of the node that were
+	// loaded successfully before the upgrade.
+	// +listType=atomic
+	// +optional
+	Applications []ApplicationStateSnapshot `json:"applications,omitempty"`
+}
+
+// ApplicationStateSnapshot holds the kernel program IDs of a
+// BpfApplicationState or ClusterBpfApplicationState.
+type ApplicationStateSnapshot struct {
+	// kind is either BpfApplicationState or ClusterBpfApplicationState.
+	// +required
+	Kind string `json:"kind"`
+	// namespace is the namespace of a BpfApplicationState.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	// name is the name of the application state.
+	// +required
+	Name string `json:"name"`
+	// programIds are the kernel IDs of the programs of the application.
+	// +listType=atomic
+	// +optional
+	ProgramIDs []uint32 `json:"programIds,omitempty"`
+}
+
+// ComponentStatus reports the readiness of a component deployed by the
+// operator.
+type ComponentStatus struct {
+	// name is the name of the component, e.g., "bpfman-daemon".
+	// +required
+	Name string `json:"name"`
+	// kind is the kind of the objects backing the component, e.g.,
+	// "DaemonSet".
+	// +required
+	Kind string `json:"kind"`
+	// ready is true when the component is ready on every node it is
+	// desired on.
+	// +required
+	Ready bool `json:"ready"`
+	// desired is the number of nodes, or objects for components that are
+	// not deployed per node, the component should be ready on.
+	// +optional
+	Desired int32 `json:"desired"`
+	// readyCount is the number of nodes, or objects, the component is ready
+	// on.
+	// +optional
+	ReadyCount int32 `json:"readyCount"`
+	// updated is the number of nodes running the latest revision of a
+	// DaemonSet component.
+	// +optional
+	Updated int32 `json:"updated,omitempty"`
+	// message explains why the component is not ready.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// ConfigConditionType is the type of a Config status condition.
+type ConfigConditionType string
+
+const (
+	// ConfigConditionAvailable indicates that every component deployed by
+	// the operator is ready.
+	ConfigConditionAvailable ConfigConditionType = "Available"
+	// ConfigConditionProgressing indicates that a component is being
+	// rolled out.
+	ConfigConditionProgressing ConfigConditionType = "Progressing"
+	// ConfigConditionDegraded indicates that the operator failed to
+	// reconcile a component, or that a component is not ready although
+	// no rollout is in progress.
+	ConfigConditionDegraded ConfigConditionType = "Degraded"
+	// ConfigConditionUpgradePaused indicates that a controlled upgrade of
+	// the bpfman daemon is paused because programs did not survive it on a
+	// node.
+	ConfigConditionUpgradePaused ConfigConditionType = "UpgradePaused"
+	// ConfigConditionUninstalling reports the progress of the removal of
+	// bpfman from the nodes once the Config is deleted.
+	ConfigConditionUninstalling ConfigConditionType = "Uninstalling"
+)
+
 // +kubebuilder:object:root=true
 // ConfigList contains a list of Configs.
 type ConfigList struct {
@@ -122,8 +513,10 @@ type ConfigList struct {
 // ComponentOverride allows overriding the operator's behaviour for a component.
 // This is a debug/escape hatch feature. When a component is marked as unmanaged,
 // the operator will completely stop reconciling it: no creates, updates, or
-// recreates if deleted.
+// recreates if deleted. When a component is patched, the operator keeps
+// reconciling it and applies the patch on top of the object it renders.
 // +k8s:deepcopy-gen=true
+// +kubebuilder:validation:XValidation:rule="!(has(self.unmanaged) && self.unmanaged && has(self.patch))",message="an unmanaged component can't be patched"
 type ComponentOverride struct {
 	// apiVersion identifies the API group and version of the resource,
 	// e.g., "apps/v1" for DaemonSets or "v1" for core resources like ConfigMaps.
@@ -147,6 +540,34 @@ type ComponentOverride struct {
 	// unmanaged controls whether the operator should stop managing this
 	// resource. When true, the operator will not create, update, or recreate
 	// this resource.
-	// +required
+	// +optional
 	Unmanaged bool `json:"unmanaged"`
+
+	// patch is applied on top of the object rendered by the operator
+	// before it is created or updated.
+	// +optional
+	Patch *ComponentPatch `json:"patch,omitempty"`
+}
+
+// ComponentPatchType is the type of a ComponentPatch.
+// +kubebuilder:validation:Enum=JSON;StrategicMerge
+type ComponentPatchType string
+
+const (
+	// ComponentPatchJSON is a JSON patch, as defined by RFC 6902.
+	ComponentPatchJSON ComponentPatchType = "JSON"
+	// ComponentPatchStrategicMerge is a Kubernetes strategic merge patch.
+	ComponentPatchStrategicMerge ComponentPatchType = "StrategicMerge"
+)
+
+// ComponentPatch is a patch applied to a component rendered by the operator.
+type ComponentPatch struct {
+	// type is the type of the patch.
+	// +required
+	Type ComponentPatchType `json:"type"`
+
+	// patch is the content of the patch, in JSON or YAML.
+	// +required
+	// +kubebuilder:validation:MinLength=1
+	Patch string `json:"patch"`
 }
@@ -56,6 +56,92 @@ type InterfaceDiscovery struct {
 	// to true.
 	// +optional
 	AllowedInterfaces []string `json:"allowedInterfaces,omitempty"`
+
+	// linkSelector is an optional field that restricts discovery to the
+	// interfaces whose link properties match. This field is only taken into
+	// consideration if interfaceAutoDiscovery is set to true.
+	// +optional
+	LinkSelector *InterfaceLinkSelector `json:"linkSelector,omitempty"`
+}
+
+// InterfaceLinkType is the type of a network interface.
+// +kubebuilder:validation:Enum=veth;physical;bond;vlan
+type InterfaceLinkType string
+
+const (
+	// InterfaceLinkTypeVeth is a virtual ethernet pair interface.
+	InterfaceLinkTypeVeth InterfaceLinkType = "veth"
+	// InterfaceLinkTypePhysical is an interface backed by a network device.
+	InterfaceLinkTypePhysical InterfaceLinkType = "physical"
+	// InterfaceLinkTypeBond is a bonding interface.
+	InterfaceLinkTypeBond InterfaceLinkType = "bond"
+	// InterfaceLinkTypeVlan is a VLAN interface.
+	InterfaceLinkTypeVlan InterfaceLinkType = "vlan"
+)
+
+// SriovRole is the SR-IOV role of a network interface.
+// +kubebuilder:validation:Enum=PF;VF
+type SriovRole string
+
+const (
+	// SriovRolePF is an SR-IOV physical function.
+	SriovRolePF SriovRole = "PF"
+	// SriovRoleVF is an SR-IOV virtual function.
+	SriovRoleVF SriovRole = "VF"
+)
+
+// InterfaceLinkSelector selects network interfaces by their link properties.
+// An interface is selected when it matches every field that is set, and it
+// matches a list when it matches any of its entries.
+// +kubebuilder:validation:MinProperties=1
+type InterfaceLinkSelector struct {
+	// drivers is an optional list of the kernel drivers of the interfaces,
+	// such as ice, mlx5_core or veth, as reported by ethtool.
+	// +optional
+	// +listType=set
+	Drivers []string `json:"drivers,omitempty"`
+
+	// types is an optional list of the types of the interfaces. Allowed values
+	// are:
+	//   veth, physical, bond, vlan
+	// +optional
+	// +listType=set
+	Types []InterfaceLinkType `json:"types,omitempty"`
+
+	// macOUIs is an optional list of the organizationally unique identifiers,
+	// i.e., the first three bytes, of the MAC addresses of the interfaces, such
+	// as 0c:42:a1.
+	// +optional
+	// +listType=set
+	// +kubebuilder:validation:items:Pattern=`^[0-9a-fA-F]{2}:[0-9a-fA-F]{2}:[0-9a-fA-F]{2}$`
+	MacOUIs []string `json:"macOUIs,omitempty"`
+
+	// minMTU is an optional field and is the smallest MTU of the interfaces.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	MinMTU *int32 `json:"minMTU,omitempty"`
+
+	// maxMTU is an optional field and is the largest MTU of the interfaces.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	MaxMTU *int32 `json:"maxMTU,omitempty"`
+
+	// sriovRole is an optional field and is the SR-IOV role of the interfaces.
+	// Allowed values are:
+	//   PF, VF
+	//
+	// When set to PF, only the physical functions of SR-IOV capable devices
+	// are selected. When set to VF, only their virtual functions are selected.
+	// +optional
+	SriovRole *SriovRole `json:"sriovRole,omitempty"`
+
+	// altNames is an optional list that is matched against the alternative
+	// names and the alias of the interfaces. If an entry is enclosed by
+	// slashes, such as `/^uplink/`, it is considered as a regular expression.
+	// Otherwise, the names in the list are case-sensitive.
+	// +optional
+	// +listType=set
+	AltNames []string `json:"altNames,omitempty"`
 }
 
 // InterfaceSelector describes the set of interfaces to attach a program to.
@@ -66,7 +152,9 @@ type InterfaceSelector struct {
 	// and how to automatically discover interfaces. If the agent should
 	// automatically discover and attach eBPF programs to interfaces, use the
 	// fields under interfacesDiscoveryConfig to control what is allow and excluded
-	// from discovery.
+	// from discovery. When networkNamespaces is also set, only the interfaces
+	// discovered in the network namespaces of the selected pods are attached,
+	// including the ones added after the pods started.
 	// +optional
 	InterfacesDiscoveryConfig *InterfaceDiscovery `json:"interfacesDiscoveryConfig,omitempty"`
 
@@ -80,6 +168,12 @@ type InterfaceSelector struct {
 	// accepted.
 	// +optional
 	PrimaryNodeInterface *bool `json:"primaryNodeInterface,omitempty"`
+
+	// linkSelector is an optional field and selects the interfaces of the
+	// Kubernetes node, or of the selected network namespaces, by their link
+	// properties, such as the driver, the type or the MTU.
+	// +optional
+	LinkSelector *InterfaceLinkSelector `json:"linkSelector,omitempty"`
 }
 
 // ClContainerSelector identifies a set of containers.
@@ -129,15 +223,78 @@ type ClNetworkNamespaceSelector struct {
 	// use the standard metav1.LabelSelector semantics and make it empty.
 	// +required
 	Pods metav1.LabelSelector `json:"pods"`
+
+	// networkAttachment is an optional field and selects, in the network
+	// namespace of each selected pod, the interfaces of a Multus network
+	// attachment instead of the interfaces selected by interfaceSelector. The
+	// interfaces are resolved from the k8s.v1.cni.cncf.io/network-status
+	// annotation of the pods.
+	// +optional
+	NetworkAttachment *NetworkAttachmentReference `json:"networkAttachment,omitempty"`
+
+	// hostNetwork is an optional field and determines how the selected pods
+	// that use the network namespace of the node are handled. When set to
+	// Skip, the pods are ignored. When set to RootNetns, the program is
+	// attached once to the selected interfaces of the root network namespace
+	// while any of the pods exist. If not provided, it defaults to Skip.
+	// +optional
+	// +kubebuilder:default:=Skip
+	HostNetwork HostNetworkPodPolicy `json:"hostNetwork,omitempty"`
 }
 
+// HostNetworkPodPolicy determines how the pods that use the network namespace
+// of the node are handled.
+// +kubebuilder:validation:Enum=Skip;RootNetns
+type HostNetworkPodPolicy string
+
+const (
+	// HostNetworkPodSkip ignores the pods that use the network namespace of
+	// the node.
+	HostNetworkPodSkip HostNetworkPodPolicy = "Skip"
+	// HostNetworkPodRootNetns attaches to the root network namespace for the
+	// pods that use the network namespace of the node.
+	HostNetworkPodRootNetns HostNetworkPodPolicy = "RootNetns"
+)
+
 // NetworkNamespaceSelector identifies a network namespace for network-related
 // program types in the namespace-scoped BpfApplication object.
 type NetworkNamespaceSelector struct {
 	// pods is a required field and indicates the target pods. To select all pods
-	// use the standard metav1.LabelSelector semantics and make it empty.
+	// use the standard metav1.LabelSelector semantics and make it empty. Pods
+	// that use the network namespace of the node are ignored.
 	// +required
 	Pods metav1.LabelSelector `json:"pods"`
+
+	// networkAttachment is an optional field and selects, in the network
+	// namespace of each selected pod, the interfaces of a Multus network
+	// attachment instead of the interfaces selected by interfaceSelector. The
+	// interfaces are resolved from the k8s.v1.cni.cncf.io/network-status
+	// annotation of the pods.
+	// +optional
+	NetworkAttachment *NetworkAttachmentReference `json:"networkAttachment,omitempty"`
+}
+
+// NetworkAttachmentReference identifies a network attachment of a pod,
+// i.e., the interfaces a NetworkAttachmentDefinition added to it.
+type NetworkAttachmentReference struct {
+	// name is a required field and is the name of the
+	// NetworkAttachmentDefinition.
+	// +required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// namespace is an optional field and is the namespace of the
+	// NetworkAttachmentDefinition. If not provided, the namespace of each pod
+	// is used.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// interface is an optional field and is the name of the interface of the
+	// attachment inside the pod, such as net1. It is only needed when a pod is
+	// attached several times to the same NetworkAttachmentDefinition. If not
+	// provided, every interface of the attachment is selected.
+	// +optional
+	Interface string `json:"interface,omitempty"`
 }
 
 // BpfAppCommon defines the common attributes for all BpfApp programs
@@ -206,6 +363,32 @@ type AttachInfoStateCommon struct {
 	LinkStatus LinkStatus `json:"linkStatus"`
 }
 
+// AttachPodState identifies the pod a link was attached for.
+type AttachPodState struct {
+	// name is the name of the pod.
+	// +required
+	Name string `json:"name"`
+
+	// namespace is the namespace of the pod.
+	// +required
+	Namespace string `json:"namespace"`
+
+	// uid is the UID of the pod.
+	// +required
+	UID string `json:"uid"`
+
+	// sandboxID is the ID of the sandbox of the pod in the container runtime
+	// when the link was attached.
+	// +optional
+	SandboxID string `json:"sandboxID,omitempty"`
+
+	// containerName is the name of the container the link is attached in, for
+	// the links attached in a container rather than in the network namespace
+	// of the pod.
+	// +optional
+	ContainerName string `json:"containerName,omitempty"`
+}
+
 type BpfProgramStateCommon struct {
 	// name is the name of the function that is the entry point for the eBPF
 	// program
@@ -219,6 +402,34 @@ type BpfProgramStateCommon struct {
 	// program is loaded.
 	// +optional
 	ProgramId *uint32 `json:"programId,omitempty"`
+	// verifierLog is the end of the eBPF verifier output reported when the
+	// kernel rejected the program. It is truncated to 4096 characters, in
+	// which case, for cluster scoped applications, the full log is stored in
+	// the ConfigMap referenced by verifierLogRef. Cleared once the program is
+	// loaded successfully.
+	// +optional
+	// +kubebuilder:validation:MaxLength=4096
+	VerifierLog string `json:"verifierLog,omitempty"`
+	// verifierLogRef references the ConfigMap holding the full verifier
+	// output when it is too long to be stored in verifierLog. It is only set
+	// for cluster scoped applications.
+	// +optional
+	VerifierLogRef *VerifierLogReference `json:"verifierLogRef,omitempty"`
+}
+
+// VerifierLogReference identifies the ConfigMap key holding the verifier
+// output of a rejected program.
+type VerifierLogReference struct {
+	// namespace is the namespace of the ConfigMap.
+	// +required
+	Namespace string `json:"namespace"`
+	// name is the name of the ConfigMap.
+	// +required
+	Name string `json:"name"`
+	// key is the ConfigMap data key holding the verifier output of the
+	// program.
+	// +required
+	Key string `json:"key"`
 }
 
 // PullPolicy describes a policy for if/when to pull a container image
@@ -321,6 +532,11 @@ const (
 	// BpfAppCondDeleteError indicates that the BPF Application was marked for
 	// deletion, but deletion was unsuccessful on one or more nodes.
 	BpfAppCondDeleteError BpfApplicationConditionType = "DeleteError"
+
+	// BpfAppCondPriorityClassRejected indicates that links of the BPF
+	// Application reference a BpfPriorityClass that the application is not
+	// allowed to use, or a priority outside of its range.
+	BpfAppCondPriorityClassRejected BpfApplicationConditionType = "PriorityClassRejected"
 )
 
 // Condition is a helper method to promote any given BpfApplicationConditionType
@@ -379,6 +595,17 @@ func (b BpfApplicationConditionType) Condition(message string) metav1.Condition
 			Reason:  "DeleteError",
 			Message: message,
 		}
+	case BpfAppCondPriorityClassRejected:
+		if len(message) == 0 {
+			message = "The application is not allowed to use a BpfPriorityClass it references"
+		}
+		condType := string(BpfAppCondPriorityClassRejected)
+		cond = metav1.Condition{
+			Type:    condType,
+			Status:  metav1.ConditionTrue,
+			Reason:  "PriorityClassRejected",
+			Message: message,
+		}
 	}
 
 	return cond
@@ -405,6 +632,11 @@ const (
 	// node while attempting to apply the configuration described in the CRD.
 	BpfAppStateCondProgramListChangedError BpfApplicationStateConditionType = "ProgramListChangedError"
 
+	// BpfAppStateCondVerifierRejected indicates that the kernel verifier
+	// rejected one or more programs of the BPF Application on the given node.
+	// The verifier output is reported per program in the status.
+	BpfAppStateCondVerifierRejected BpfApplicationStateConditionType = "VerifierRejected"
+
 	// BpfAppStateCondUnloadError indicates that the BPF Application was marked
 	// for deletion, but unloading one or more programs was unsuccessful on the
 	// given node.
@@ -446,6 +678,14 @@ func (b BpfApplicationStateConditionType) Condition() metav1.Condition {
 			Reason:  "Error",
 			Message: "An error has occurred",
 		}
+	case BpfAppStateCondVerifierRejected:
+		condType := string(BpfAppStateCondVerifierRejected)
+		cond = metav1.Condition{
+			Type:    condType,
+			Status:  metav1.ConditionTrue,
+			Reason:  "VerifierRejected",
+			Message: "The kernel verifier rejected one or more programs, see the verifierLog of each program",
+		}
 	case BpfAppStateCondUnloadError:
 		condType := string(BpfAppStateCondUnloadError)
 		cond = metav1.Condition{
@@ -509,4 +749,7 @@ const (
 	ApAttachError LinkStatus = "AttachError"
 	// A detach was attempted, but there was an error
 	ApDetachError LinkStatus = "DetachError"
+	// The attach point doesn't run the program in the requested mode, so the
+	// program was not left attached
+	ApAttachModeError LinkStatus = "AttachModeError"
 )
@@ -58,6 +58,13 @@ type XdpAttachInfo struct {
 	// +kubebuilder:validation:Maximum=1000
 	Priority *int32 `json:"priority,omitempty"`
 
+	// priorityClassName is an optional field and is the name of the
+	// BpfPriorityClass that determines the priority of the XDP program. When it
+	// is set, priority must be within the range of the class, and if priority is
+	// not provided, the defaultPriority of the class is used.
+	// +optional
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+
 	// proceedOn is an optional field and allows the user to call other XDP
 	// programs in a chain, or not call the next program in a chain based on the
 	// exit code of an XDP program. Allowed values, which are the possible exit
@@ -95,6 +102,13 @@ type XdpAttachInfoState struct {
 	// +required
 	NetnsPath string `json:"netnsPath"`
 
+	// pod is the pod in whose network namespace the link is attached, when the
+	// link was created for a pod selected by networkNamespaces. The link is
+	// attached again when the sandbox of the pod is recreated, and detached
+	// once the pod is gone.
+	// +optional
+	Pod *AttachPodState `json:"pod,omitempty"`
+
 	// priority is the provisioned priority of the XDP program in relation to other
 	// programs of the same type with the same attach point. It is a value from 0
 	// to 1000, where lower values have higher precedence.
@@ -21,6 +21,7 @@ limitations under the License.
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
@@ -28,6 +29,12 @@ import (
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AgentSpec) DeepCopyInto(out *AgentSpec) {
 	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	in.LinkDrift.DeepCopyInto(&out.LinkDrift)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AgentSpec.
@@ -40,6 +47,26 @@ func (in *AgentSpec) DeepCopy() *AgentSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApplicationStateSnapshot) DeepCopyInto(out *ApplicationStateSnapshot) {
+	*out = *in
+	if in.ProgramIDs != nil {
+		in, out := &in.ProgramIDs, &out.ProgramIDs
+		*out = make([]uint32, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationStateSnapshot.
+func (in *ApplicationStateSnapshot) DeepCopy() *ApplicationStateSnapshot {
+	if in == nil {
+		return nil
+	}
+	out := new(ApplicationStateSnapshot)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AttachInfoStateCommon) DeepCopyInto(out *AttachInfoStateCommon) {
 	*out = *in
@@ -60,6 +87,21 @@ func (in *AttachInfoStateCommon) DeepCopy() *AttachInfoStateCommon {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AttachPodState) DeepCopyInto(out *AttachPodState) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AttachPodState.
+func (in *AttachPodState) DeepCopy() *AttachPodState {
+	if in == nil {
+		return nil
+	}
+	out := new(AttachPodState)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BpfAppCommon) DeepCopyInto(out *BpfAppCommon) {
 	*out = *in
@@ -370,6 +412,183 @@ func (in *BpfApplicationStateStatus) DeepCopy() *BpfApplicationStateStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BpfPriorityClass) DeepCopyInto(out *BpfPriorityClass) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BpfPriorityClass.
+func (in *BpfPriorityClass) DeepCopy() *BpfPriorityClass {
+	if in == nil {
+		return nil
+	}
+	out := new(BpfPriorityClass)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *BpfPriorityClass) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BpfPriorityClassList) DeepCopyInto(out *BpfPriorityClassList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]BpfPriorityClass, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BpfPriorityClassList.
+func (in *BpfPriorityClassList) DeepCopy() *BpfPriorityClassList {
+	if in == nil {
+		return nil
+	}
+	out := new(BpfPriorityClassList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *BpfPriorityClassList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BpfPriorityClassSpec) DeepCopyInto(out *BpfPriorityClassSpec) {
+	*out = *in
+	if in.DefaultPriority != nil {
+		in, out := &in.DefaultPriority, &out.DefaultPriority
+		*out = new(int32)
+		**out = **in
+	}
+	if in.AllowedNamespaces != nil {
+		in, out := &in.AllowedNamespaces, &out.AllowedNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BpfPriorityClassSpec.
+func (in *BpfPriorityClassSpec) DeepCopy() *BpfPriorityClassSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(BpfPriorityClassSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BpfProgramInventory) DeepCopyInto(out *BpfProgramInventory) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BpfProgramInventory.
+func (in *BpfProgramInventory) DeepCopy() *BpfProgramInventory {
+	if in == nil {
+		return nil
+	}
+	out := new(BpfProgramInventory)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *BpfProgramInventory) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BpfProgramInventoryList) DeepCopyInto(out *BpfProgramInventoryList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]BpfProgramInventory, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BpfProgramInventoryList.
+func (in *BpfProgramInventoryList) DeepCopy() *BpfProgramInventoryList {
+	if in == nil {
+		return nil
+	}
+	out := new(BpfProgramInventoryList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *BpfProgramInventoryList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BpfProgramInventoryStatus) DeepCopyInto(out *BpfProgramInventoryStatus) {
+	*out = *in
+	if in.Programs != nil {
+		in, out := &in.Programs, &out.Programs
+		*out = make([]UnmanagedProgram, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Dispatchers != nil {
+		in, out := &in.Dispatchers, &out.Dispatchers
+		*out = make([]DispatcherChain, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BpfProgramInventoryStatus.
+func (in *BpfProgramInventoryStatus) DeepCopy() *BpfProgramInventoryStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BpfProgramInventoryStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BpfProgramStateCommon) DeepCopyInto(out *BpfProgramStateCommon) {
 	*out = *in
@@ -378,6 +597,11 @@ func (in *BpfProgramStateCommon) DeepCopyInto(out *BpfProgramStateCommon) {
 		*out = new(uint32)
 		**out = **in
 	}
+	if in.VerifierLogRef != nil {
+		in, out := &in.VerifierLogRef, &out.VerifierLogRef
+		*out = new(VerifierLogReference)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BpfProgramStateCommon.
@@ -969,6 +1193,11 @@ func (in *ClKretprobeProgramInfoState) DeepCopy() *ClKretprobeProgramInfoState {
 func (in *ClNetworkNamespaceSelector) DeepCopyInto(out *ClNetworkNamespaceSelector) {
 	*out = *in
 	in.Pods.DeepCopyInto(&out.Pods)
+	if in.NetworkAttachment != nil {
+		in, out := &in.NetworkAttachment, &out.NetworkAttachment
+		*out = new(NetworkAttachmentReference)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClNetworkNamespaceSelector.
@@ -1016,6 +1245,11 @@ func (in *ClTcAttachInfo) DeepCopy() *ClTcAttachInfo {
 func (in *ClTcAttachInfoState) DeepCopyInto(out *ClTcAttachInfoState) {
 	*out = *in
 	in.AttachInfoStateCommon.DeepCopyInto(&out.AttachInfoStateCommon)
+	if in.Pod != nil {
+		in, out := &in.Pod, &out.Pod
+		*out = new(AttachPodState)
+		**out = **in
+	}
 	if in.ProceedOn != nil {
 		in, out := &in.ProceedOn, &out.ProceedOn
 		*out = make([]TcProceedOnValue, len(*in))
@@ -1107,6 +1341,11 @@ func (in *ClTcxAttachInfo) DeepCopy() *ClTcxAttachInfo {
 func (in *ClTcxAttachInfoState) DeepCopyInto(out *ClTcxAttachInfoState) {
 	*out = *in
 	in.AttachInfoStateCommon.DeepCopyInto(&out.AttachInfoStateCommon)
+	if in.Pod != nil {
+		in, out := &in.Pod, &out.Pod
+		*out = new(AttachPodState)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClTcxAttachInfoState.
@@ -1275,6 +1514,11 @@ func (in *ClUprobeAttachInfoState) DeepCopyInto(out *ClUprobeAttachInfoState) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.Pod != nil {
+		in, out := &in.Pod, &out.Pod
+		*out = new(AttachPodState)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClUprobeAttachInfoState.
@@ -1366,6 +1610,11 @@ func (in *ClXdpAttachInfo) DeepCopy() *ClXdpAttachInfo {
 func (in *ClXdpAttachInfoState) DeepCopyInto(out *ClXdpAttachInfoState) {
 	*out = *in
 	in.AttachInfoStateCommon.DeepCopyInto(&out.AttachInfoStateCommon)
+	if in.Pod != nil {
+		in, out := &in.Pod, &out.Pod
+		*out = new(AttachPodState)
+		**out = **in
+	}
 	if in.ProceedOn != nil {
 		in, out := &in.ProceedOn, &out.ProceedOn
 		*out = make([]XdpProceedOnValue, len(*in))
@@ -1547,6 +1796,11 @@ func (in *ClusterBpfApplicationStateList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ComponentOverride) DeepCopyInto(out *ComponentOverride) {
 	*out = *in
+	if in.Patch != nil {
+		in, out := &in.Patch, &out.Patch
+		*out = new(ComponentPatch)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentOverride.
@@ -1559,6 +1813,36 @@ func (in *ComponentOverride) DeepCopy() *ComponentOverride {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComponentPatch) DeepCopyInto(out *ComponentPatch) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentPatch.
+func (in *ComponentPatch) DeepCopy() *ComponentPatch {
+	if in == nil {
+		return nil
+	}
+	out := new(ComponentPatch)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComponentStatus) DeepCopyInto(out *ComponentStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentStatus.
+func (in *ComponentStatus) DeepCopy() *ComponentStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ComponentStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Config) DeepCopyInto(out *Config) {
 	*out = *in
@@ -1621,12 +1905,23 @@ func (in *ConfigList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ConfigSpec) DeepCopyInto(out *ConfigSpec) {
 	*out = *in
-	out.Agent = in.Agent
-	out.Daemon = in.Daemon
+	in.Agent.DeepCopyInto(&out.Agent)
+	in.Daemon.DeepCopyInto(&out.Daemon)
+	if in.NodePools != nil {
+		in, out := &in.NodePools, &out.NodePools
+		*out = make([]NodePool, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.MetricsProxy.DeepCopyInto(&out.MetricsProxy)
+	in.Uninstall.DeepCopyInto(&out.Uninstall)
 	if in.Overrides != nil {
 		in, out := &in.Overrides, &out.Overrides
 		*out = make([]ComponentOverride, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 }
 
@@ -1650,6 +1945,18 @@ func (in *ConfigStatus) DeepCopyInto(out *ConfigStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Components != nil {
+		in, out := &in.Components, &out.Components
+		*out = make([]ComponentStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.DaemonUpgrades != nil {
+		in, out := &in.DaemonUpgrades, &out.DaemonUpgrades
+		*out = make([]DaemonUpgradeStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigStatus.
@@ -1686,6 +1993,46 @@ func (in *ContainerSelector) DeepCopy() *ContainerSelector {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DaemonSpec) DeepCopyInto(out *DaemonSpec) {
 	*out = *in
+	if in.LogTargets != nil {
+		in, out := &in.LogTargets, &out.LogTargets
+		*out = make([]LogTarget, len(*in))
+		copy(*out, *in)
+	}
+	if in.Database != nil {
+		in, out := &in.Database, &out.Database
+		*out = new(DatabaseConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Signing != nil {
+		in, out := &in.Signing, &out.Signing
+		*out = new(SigningConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Registry != nil {
+		in, out := &in.Registry, &out.Registry
+		*out = new(RegistryConfig)
+		**out = **in
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]corev1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	out.Upgrade = in.Upgrade
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DaemonSpec.
@@ -1698,6 +2045,132 @@ func (in *DaemonSpec) DeepCopy() *DaemonSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DaemonUpgradeNode) DeepCopyInto(out *DaemonUpgradeNode) {
+	*out = *in
+	if in.Applications != nil {
+		in, out := &in.Applications, &out.Applications
+		*out = make([]ApplicationStateSnapshot, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DaemonUpgradeNode.
+func (in *DaemonUpgradeNode) DeepCopy() *DaemonUpgradeNode {
+	if in == nil {
+		return nil
+	}
+	out := new(DaemonUpgradeNode)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DaemonUpgradeSpec) DeepCopyInto(out *DaemonUpgradeSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DaemonUpgradeSpec.
+func (in *DaemonUpgradeSpec) DeepCopy() *DaemonUpgradeSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DaemonUpgradeSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DaemonUpgradeStatus) DeepCopyInto(out *DaemonUpgradeStatus) {
+	*out = *in
+	if in.Nodes != nil {
+		in, out := &in.Nodes, &out.Nodes
+		*out = make([]DaemonUpgradeNode, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DaemonUpgradeStatus.
+func (in *DaemonUpgradeStatus) DeepCopy() *DaemonUpgradeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DaemonUpgradeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseConfig) DeepCopyInto(out *DatabaseConfig) {
+	*out = *in
+	if in.MaxRetries != nil {
+		in, out := &in.MaxRetries, &out.MaxRetries
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MillisecDelay != nil {
+		in, out := &in.MillisecDelay, &out.MillisecDelay
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseConfig.
+func (in *DatabaseConfig) DeepCopy() *DatabaseConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DispatcherChain) DeepCopyInto(out *DispatcherChain) {
+	*out = *in
+	if in.Programs != nil {
+		in, out := &in.Programs, &out.Programs
+		*out = make([]DispatcherProgram, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DispatcherChain.
+func (in *DispatcherChain) DeepCopy() *DispatcherChain {
+	if in == nil {
+		return nil
+	}
+	out := new(DispatcherChain)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DispatcherProgram) DeepCopyInto(out *DispatcherProgram) {
+	*out = *in
+	if in.ProceedOn != nil {
+		in, out := &in.ProceedOn, &out.ProceedOn
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DispatcherProgram.
+func (in *DispatcherProgram) DeepCopy() *DispatcherProgram {
+	if in == nil {
+		return nil
+	}
+	out := new(DispatcherProgram)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ImagePullSecretSelector) DeepCopyInto(out *ImagePullSecretSelector) {
 	*out = *in
@@ -1731,6 +2204,11 @@ func (in *InterfaceDiscovery) DeepCopyInto(out *InterfaceDiscovery) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.LinkSelector != nil {
+		in, out := &in.LinkSelector, &out.LinkSelector
+		*out = new(InterfaceLinkSelector)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InterfaceDiscovery.
@@ -1743,6 +2221,56 @@ func (in *InterfaceDiscovery) DeepCopy() *InterfaceDiscovery {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InterfaceLinkSelector) DeepCopyInto(out *InterfaceLinkSelector) {
+	*out = *in
+	if in.Drivers != nil {
+		in, out := &in.Drivers, &out.Drivers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Types != nil {
+		in, out := &in.Types, &out.Types
+		*out = make([]InterfaceLinkType, len(*in))
+		copy(*out, *in)
+	}
+	if in.MacOUIs != nil {
+		in, out := &in.MacOUIs, &out.MacOUIs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.MinMTU != nil {
+		in, out := &in.MinMTU, &out.MinMTU
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxMTU != nil {
+		in, out := &in.MaxMTU, &out.MaxMTU
+		*out = new(int32)
+		**out = **in
+	}
+	if in.SriovRole != nil {
+		in, out := &in.SriovRole, &out.SriovRole
+		*out = new(SriovRole)
+		**out = **in
+	}
+	if in.AltNames != nil {
+		in, out := &in.AltNames, &out.AltNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InterfaceLinkSelector.
+func (in *InterfaceLinkSelector) DeepCopy() *InterfaceLinkSelector {
+	if in == nil {
+		return nil
+	}
+	out := new(InterfaceLinkSelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *InterfaceSelector) DeepCopyInto(out *InterfaceSelector) {
 	*out = *in
@@ -1761,6 +2289,11 @@ func (in *InterfaceSelector) DeepCopyInto(out *InterfaceSelector) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.LinkSelector != nil {
+		in, out := &in.LinkSelector, &out.LinkSelector
+		*out = new(InterfaceLinkSelector)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InterfaceSelector.
@@ -1773,10 +2306,99 @@ func (in *InterfaceSelector) DeepCopy() *InterfaceSelector {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LinkDriftSpec) DeepCopyInto(out *LinkDriftSpec) {
+	*out = *in
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LinkDriftSpec.
+func (in *LinkDriftSpec) DeepCopy() *LinkDriftSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(LinkDriftSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LogTarget) DeepCopyInto(out *LogTarget) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogTarget.
+func (in *LogTarget) DeepCopy() *LogTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(LogTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetricsProxySpec) DeepCopyInto(out *MetricsProxySpec) {
+	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]corev1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetricsProxySpec.
+func (in *MetricsProxySpec) DeepCopy() *MetricsProxySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MetricsProxySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkAttachmentReference) DeepCopyInto(out *NetworkAttachmentReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkAttachmentReference.
+func (in *NetworkAttachmentReference) DeepCopy() *NetworkAttachmentReference {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkAttachmentReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NetworkNamespaceSelector) DeepCopyInto(out *NetworkNamespaceSelector) {
 	*out = *in
 	in.Pods.DeepCopyInto(&out.Pods)
+	if in.NetworkAttachment != nil {
+		in, out := &in.NetworkAttachment, &out.NetworkAttachment
+		*out = new(NetworkAttachmentReference)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkNamespaceSelector.
@@ -1789,6 +2411,80 @@ func (in *NetworkNamespaceSelector) DeepCopy() *NetworkNamespaceSelector {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodePool) DeepCopyInto(out *NodePool) {
+	*out = *in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]corev1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodePool.
+func (in *NodePool) DeepCopy() *NodePool {
+	if in == nil {
+		return nil
+	}
+	out := new(NodePool)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RegistryConfig) DeepCopyInto(out *RegistryConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RegistryConfig.
+func (in *RegistryConfig) DeepCopy() *RegistryConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(RegistryConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SigningConfig) DeepCopyInto(out *SigningConfig) {
+	*out = *in
+	if in.AllowUnsigned != nil {
+		in, out := &in.AllowUnsigned, &out.AllowUnsigned
+		*out = new(bool)
+		**out = **in
+	}
+	if in.VerifyEnabled != nil {
+		in, out := &in.VerifyEnabled, &out.VerifyEnabled
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SigningConfig.
+func (in *SigningConfig) DeepCopy() *SigningConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(SigningConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TcAttachInfo) DeepCopyInto(out *TcAttachInfo) {
 	*out = *in
@@ -1820,6 +2516,11 @@ func (in *TcAttachInfo) DeepCopy() *TcAttachInfo {
 func (in *TcAttachInfoState) DeepCopyInto(out *TcAttachInfoState) {
 	*out = *in
 	in.AttachInfoStateCommon.DeepCopyInto(&out.AttachInfoStateCommon)
+	if in.Pod != nil {
+		in, out := &in.Pod, &out.Pod
+		*out = new(AttachPodState)
+		**out = **in
+	}
 	if in.ProceedOn != nil {
 		in, out := &in.ProceedOn, &out.ProceedOn
 		*out = make([]TcProceedOnValue, len(*in))
@@ -1907,6 +2608,11 @@ func (in *TcxAttachInfo) DeepCopy() *TcxAttachInfo {
 func (in *TcxAttachInfoState) DeepCopyInto(out *TcxAttachInfoState) {
 	*out = *in
 	in.AttachInfoStateCommon.DeepCopyInto(&out.AttachInfoStateCommon)
+	if in.Pod != nil {
+		in, out := &in.Pod, &out.Pod
+		*out = new(AttachPodState)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TcxAttachInfoState.
@@ -1963,6 +2669,58 @@ func (in *TcxProgramInfoState) DeepCopy() *TcxProgramInfoState {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UninstallSpec) DeepCopyInto(out *UninstallSpec) {
+	*out = *in
+	if in.CleanupNodes != nil {
+		in, out := &in.CleanupNodes, &out.CleanupNodes
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UninstallSpec.
+func (in *UninstallSpec) DeepCopy() *UninstallSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(UninstallSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UnmanagedProgram) DeepCopyInto(out *UnmanagedProgram) {
+	*out = *in
+	if in.MapIDs != nil {
+		in, out := &in.MapIDs, &out.MapIDs
+		*out = make([]uint32, len(*in))
+		copy(*out, *in)
+	}
+	if in.Metadata != nil {
+		in, out := &in.Metadata, &out.Metadata
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.LinkIDs != nil {
+		in, out := &in.LinkIDs, &out.LinkIDs
+		*out = make([]uint32, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UnmanagedProgram.
+func (in *UnmanagedProgram) DeepCopy() *UnmanagedProgram {
+	if in == nil {
+		return nil
+	}
+	out := new(UnmanagedProgram)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *UprobeAttachInfo) DeepCopyInto(out *UprobeAttachInfo) {
 	*out = *in
@@ -1993,6 +2751,11 @@ func (in *UprobeAttachInfoState) DeepCopyInto(out *UprobeAttachInfoState) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.Pod != nil {
+		in, out := &in.Pod, &out.Pod
+		*out = new(AttachPodState)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UprobeAttachInfoState.
@@ -2049,6 +2812,21 @@ func (in *UprobeProgramInfoState) DeepCopy() *UprobeProgramInfoState {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VerifierLogReference) DeepCopyInto(out *VerifierLogReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VerifierLogReference.
+func (in *VerifierLogReference) DeepCopy() *VerifierLogReference {
+	if in == nil {
+		return nil
+	}
+	out := new(VerifierLogReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *XdpAttachInfo) DeepCopyInto(out *XdpAttachInfo) {
 	*out = *in
@@ -2080,6 +2858,11 @@ func (in *XdpAttachInfo) DeepCopy() *XdpAttachInfo {
 func (in *XdpAttachInfoState) DeepCopyInto(out *XdpAttachInfoState) {
 	*out = *in
 	in.AttachInfoStateCommon.DeepCopyInto(&out.AttachInfoStateCommon)
+	if in.Pod != nil {
+		in, out := &in.Pod, &out.Pod
+		*out = new(AttachPodState)
+		**out = **in
+	}
 	if in.ProceedOn != nil {
 		in, out := &in.ProceedOn, &out.ProceedOn
 		*out = make([]XdpProceedOnValue, len(*in))
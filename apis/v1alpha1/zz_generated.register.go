@@ -65,6 +65,10 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&BpfApplicationList{},
 		&BpfApplicationState{},
 		&BpfApplicationStateList{},
+		&BpfPriorityClass{},
+		&BpfPriorityClassList{},
+		&BpfProgramInventory{},
+		&BpfProgramInventoryList{},
 		&ClusterBpfApplication{},
 		&ClusterBpfApplicationList{},
 		&ClusterBpfApplicationState{},
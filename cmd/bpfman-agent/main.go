@@ -42,12 +42,14 @@ import (
 	"golang.org/x/sync/errgroup"
 	"google.golang.org/grpc/credentials/insecure"
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	"sigs.k8s.io/controller-runtime/pkg/metrics"
@@ -74,6 +76,48 @@ func init() {
 	//+kubebuilder:scaffold:scheme
 }
 
+// cleanupTimeout bounds how long --cleanup-node waits for bpfman to unload
+// the programs of the node.
+const cleanupTimeout = 5 * time.Minute
+
+// handleCleanupNode performs the --cleanup-node logic. The programs are
+// unloaded through the same audit log as the agent. It returns the exit
+// code.
+func handleCleanupNode(mountPoint string, auditLogOpts bpfmanagent.AuditLogOptions) int {
+	ctx, cancel := context.WithTimeout(context.Background(), cleanupTimeout)
+	defer cancel()
+
+	c, err := conn.CreateConnection(ctx, insecure.NewCredentials())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		return 1
+	}
+	defer c.Close()
+
+	var auditLog *bpfmanagent.AuditLog
+	if auditLogOpts.Path != "" {
+		auditLog = bpfmanagent.NewAuditLog(os.Getenv("KUBE_NODE_NAME"), auditLogOpts)
+		defer auditLog.Close()
+	}
+	client := gobpfman.NewBpfmanClient(c)
+	unload := func(ctx context.Context, id uint32) error {
+		return bpfmanagent.UnloadProgram(ctx, client, auditLog, id)
+	}
+
+	unloaded, removed, err := bpffs.Cleanup(ctx, client, unload, bpffs.DefaultRuntimeDir, mountPoint)
+	for _, id := range unloaded {
+		fmt.Printf("unloaded program %d\n", id)
+	}
+	for _, path := range removed {
+		fmt.Printf("removed %s\n", path)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
 // handleMountBPFFS performs the --mount-bpffs logic. It returns
 // (exitCode, message, err).
 func handleMountBPFFS(mountInfoPath, mountPoint string, remount bool) (int, string, error) {
@@ -148,6 +192,10 @@ type agentMetricsServer struct {
 type interfaceDiscovery struct {
 	events     <-chan ifaces.Event
 	interfaces *sync.Map
+	// notify receives an event for the node whenever an interface is
+	// created or deleted.
+	notify   []chan<- event.GenericEvent
+	nodeName string
 }
 
 // newInterfaceDiscovery creates an interfaceDiscovery instance and
@@ -158,8 +206,12 @@ type interfaceDiscovery struct {
 // events. The resulting events channel will be used by the run()
 // method to process events.
 //
+// Each interface event is also forwarded to the notify channels, as a
+// generic event for nodeName, without blocking.
+//
 // Returns an error if the subscription to interface events fails.
-func newInterfaceDiscovery(ctx context.Context, interfaces *sync.Map) (*interfaceDiscovery, error) {
+func newInterfaceDiscovery(ctx context.Context, interfaces *sync.Map, nodeName string,
+	notify ...chan<- event.GenericEvent) (*interfaceDiscovery, error) {
 	m := netobservmetrics.NoOp()
 	informer := ifaces.NewWatcher(buffersLength, m)
 	registerer, err := ifaces.NewRegisterer(informer, &config.Agent{BuffersLength: buffersLength}, m)
@@ -175,6 +227,8 @@ func newInterfaceDiscovery(ctx context.Context, interfaces *sync.Map) (*interfac
 	return &interfaceDiscovery{
 		events:     ifaceEvents,
 		interfaces: interfaces,
+		notify:     notify,
+		nodeName:   nodeName,
 	}, nil
 }
 
@@ -183,8 +237,9 @@ func newInterfaceDiscovery(ctx context.Context, interfaces *sync.Map) (*interfac
 //
 // This is a synchronous function that listens for EventAdded and
 // EventDeleted events on the subscribed channel, adding or removing
-// interfaces from the map accordingly. The function blocks until the
-// provided context is cancelled or the events channel closes.
+// interfaces from the map accordingly and notifying the reconcilers.
+// The function blocks until the provided context is cancelled or the
+// events channel closes.
 //
 // Returns nil when the context is cancelled, or an error if the
 // events channel closes unexpectedly.
@@ -206,7 +261,21 @@ func (id *interfaceDiscovery) run(ctx context.Context, logger logr.Logger) error
 				logger.Info("interface deleted", "Name", iface.Name, "netns", iface.NSName, "NsHandle", iface.NetNS)
 				id.interfaces.Delete(iface)
 			default:
+				continue
 			}
+			id.notifyReconcilers()
+		}
+	}
+}
+
+// notifyReconcilers sends an event for the node to each notify channel.
+// Events are dropped when a channel is full since a reconcile is
+// already pending.
+func (id *interfaceDiscovery) notifyReconcilers() {
+	for _, ch := range id.notify {
+		select {
+		case ch <- event.GenericEvent{Object: &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: id.nodeName}}}:
+		default:
 		}
 	}
 }
@@ -216,12 +285,14 @@ func (id *interfaceDiscovery) run(ctx context.Context, logger logr.Logger) error
 //
 // It removes any existing socket file at socketPath, creates a new
 // Unix socket listener, and sets the socket file permissions to 0666.
-// The returned server serves /metrics with Prometheus metrics and
-// returns HTTP 404 for all other paths.
+// The returned server serves /metrics with Prometheus metrics, /maps
+// with mapsHandler and returns HTTP 404 for all other paths. Both are
+// exposed to the cluster only through the metrics-proxy, which
+// authenticates and authorizes every request.
 //
 // The server must be started by calling its run() method with a
 // context for shutdown coordination.
-func newAgentMetricsServer(socketPath string) (*agentMetricsServer, error) {
+func newAgentMetricsServer(socketPath string, mapsHandler http.Handler) (*agentMetricsServer, error) {
 	if err := os.Remove(socketPath); err != nil && !os.IsNotExist(err) {
 		return nil, fmt.Errorf("removing existing metrics socket %q: %w", socketPath, err)
 	}
@@ -241,9 +312,12 @@ func newAgentMetricsServer(socketPath string) (*agentMetricsServer, error) {
 		listener:   listener,
 		server: &http.Server{
 			Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-				if r.URL.Path == "/metrics" {
+				switch r.URL.Path {
+				case "/metrics":
 					promhttp.HandlerFor(metrics.Registry, promhttp.HandlerOpts{}).ServeHTTP(w, r)
-				} else {
+				case "/maps":
+					mapsHandler.ServeHTTP(w, r)
+				default:
 					http.NotFound(w, r)
 				}
 			}),
@@ -374,6 +448,7 @@ func main() {
 	var pprofAddr string
 	var certDir string
 	var showVersion bool
+	var auditLogOpts bpfmanagent.AuditLogOptions
 
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8175", "The address the probe endpoint binds to.")
 	flag.BoolVar(&enableHTTP2, "enable-http2", enableHTTP2, "If HTTP/2 should be enabled for the metrics and webhook servers.")
@@ -383,7 +458,18 @@ func main() {
 	mountBPFFS := flag.Bool("mount-bpffs", false, "Ensure bpffs is mounted at the given path, then exit (init-container mode).")
 	mountBPFFSPath := flag.String("mount-bpffs-path", bpffs.DefaultMountPoint, "Path where bpffs should be mounted.")
 	remountBPFFS := flag.Bool("mount-bpffs-remount", false, "Unmount bpffs if mounted, then mount it (testing only).")
+	cleanupNode := flag.Bool("cleanup-node", false, "Unload the programs bpfman loaded on the node and remove their pins, then exit (uninstall mode).")
+	cleanupNodeDatabase := flag.Bool("cleanup-node-database", false, "Remove the bpfman database of the node, once bpfman is stopped, then exit (uninstall mode).")
+	linkDriftInterval := flag.Duration("link-drift-interval", 5*time.Minute, "How often the links of the node are verified. Set to 0 to disable it.")
+	linkDriftMode := flag.String("link-drift-mode", string(bpfmaniov1alpha1.LinkDriftRepair), "What to do with the missing links: Repair re-attaches them, Report only reports them.")
+	collectOrphans := flag.Bool("collect-orphans", true, "Unload, at startup, the programs loaded for BpfApplicationStates that no longer exist.")
+	collectOrphansDryRun := flag.Bool("collect-orphans-dry-run", false, "Only report the orphaned programs found at startup.")
+	programInventoryInterval := flag.Duration("program-inventory-interval", time.Minute, "How often the BpfProgramInventory of the node is refreshed. Set to 0 to disable it.")
 	flag.BoolVar(&showVersion, "version", false, "Print version information and exit.")
+	flag.StringVar(&auditLogOpts.Path, "audit-log-path", "/var/log/bpfman-agent/audit.log", "File to which every bpfman Load, Attach, Detach and Unload call is recorded. Leave empty to disable the audit log.")
+	flag.IntVar(&auditLogOpts.MaxSizeMB, "audit-log-max-size", 100, "Size in megabytes at which the audit log is rotated.")
+	flag.IntVar(&auditLogOpts.MaxBackups, "audit-log-max-backups", 5, "Number of rotated audit log files to retain.")
+	flag.IntVar(&auditLogOpts.MaxAgeDays, "audit-log-max-age", 30, "Number of days to retain rotated audit log files.")
 
 	flag.Parse()
 
@@ -407,6 +493,25 @@ func main() {
 		os.Exit(code)
 	}
 
+	// Handle --cleanup-node and --cleanup-node-database modes: remove
+	// the state bpfman left on the node and exit. These are used by the
+	// Jobs the operator runs on every node when bpfman is uninstalled,
+	// before and after stopping bpfman.
+	if *cleanupNode {
+		os.Exit(handleCleanupNode(*mountBPFFSPath, auditLogOpts))
+	}
+	if *cleanupNodeDatabase {
+		db, err := bpffs.RemoveDatabase(bpffs.DefaultRuntimeDir)
+		if db != "" {
+			fmt.Printf("removed %s\n", db)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
 	// Get the Log level for bpfman deployment where this pod is running.
 	logLevel := os.Getenv("GO_LOG")
 	switch logLevel {
@@ -444,10 +549,10 @@ func main() {
 			BindAddress: "0",
 		},
 		LeaderElection: false,
-		// Specify that Secrets's should not be cached.
+		// Specify that Secrets's and ConfigMaps should not be cached.
 		Client: client.Options{
 			Cache: &client.CacheOptions{
-				DisableFor: []client.Object{&v1.Secret{}},
+				DisableFor: []client.Object{&v1.Secret{}, &v1.ConfigMap{}},
 			},
 		},
 	})
@@ -481,26 +586,83 @@ func main() {
 		Scheme:       mgr.GetScheme(),
 		GrpcConn:     conn,
 		BpfmanClient: gobpfman.NewBpfmanClient(conn),
+		Recorder:     mgr.GetEventRecorderFor("bpfman-agent"),
 		NodeName:     nodeName,
 		Containers:   containerGetter,
 		Interfaces:   &sync.Map{},
 		NetNsCache:   &bpfmanagent.ReconcilerNetNsCache{},
+		Namespace:    os.Getenv("KUBE_POD_NAMESPACE"),
+	}
+
+	if auditLogOpts.Path != "" {
+		commonApp.AuditLog = bpfmanagent.NewAuditLog(nodeName, auditLogOpts)
+		defer commonApp.AuditLog.Close()
+		setupLog.Info("Recording bpfman calls in audit log", "path", auditLogOpts.Path)
 	}
 
-	if err = (&bpfmanagent.ClBpfApplicationReconciler{
+	clReconciler := &bpfmanagent.ClBpfApplicationReconciler{
 		ReconcilerCommon: commonApp,
-	}).SetupWithManager(mgr); err != nil {
+	}
+	nsReconciler := &bpfmanagent.NsBpfApplicationReconciler{
+		ReconcilerCommon: commonApp,
+	}
+
+	if *linkDriftInterval > 0 {
+		var repair bool
+		switch bpfmaniov1alpha1.LinkDriftMode(*linkDriftMode) {
+		case bpfmaniov1alpha1.LinkDriftRepair:
+			repair = true
+		case bpfmaniov1alpha1.LinkDriftReport:
+		default:
+			setupLog.Error(fmt.Errorf("invalid link drift mode %q", *linkDriftMode), "unable to set up link drift detection")
+			os.Exit(1)
+		}
+		driftDetector := bpfmanagent.NewLinkDriftDetector(mgr.GetClient(), commonApp.BpfmanClient,
+			commonApp.Recorder, nodeName, *linkDriftInterval, repair)
+		if err := mgr.Add(driftDetector); err != nil {
+			setupLog.Error(err, "unable to set up link drift detection")
+			os.Exit(1)
+		}
+		clReconciler.Repairs = driftDetector.ClusterRepairs()
+		nsReconciler.Repairs = driftDetector.NamespacedRepairs()
+	}
+
+	var ifaceEvents []chan<- event.GenericEvent
+	if enableInterfacesDiscovery {
+		clIfaceEvents := make(chan event.GenericEvent, buffersLength)
+		nsIfaceEvents := make(chan event.GenericEvent, buffersLength)
+		clReconciler.InterfaceEvents = clIfaceEvents
+		nsReconciler.InterfaceEvents = nsIfaceEvents
+		ifaceEvents = append(ifaceEvents, clIfaceEvents, nsIfaceEvents)
+	}
+
+	if err = clReconciler.SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create BpfApplicationReconciler")
 		os.Exit(1)
 	}
 
-	if err = (&bpfmanagent.NsBpfApplicationReconciler{
-		ReconcilerCommon: commonApp,
-	}).SetupWithManager(mgr); err != nil {
+	if err = nsReconciler.SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create BpfNsApplicationReconciler")
 		os.Exit(1)
 	}
 
+	if *collectOrphans {
+		orphanCollector := bpfmanagent.NewOrphanCollector(mgr.GetClient(), commonApp.BpfmanClient, commonApp.AuditLog,
+			nodeName, *collectOrphansDryRun)
+		if err := mgr.Add(orphanCollector); err != nil {
+			setupLog.Error(err, "unable to set up orphaned program collection")
+			os.Exit(1)
+		}
+	}
+
+	if *programInventoryInterval > 0 {
+		inventory := bpfmanagent.NewProgramInventory(mgr.GetClient(), commonApp.BpfmanClient, nodeName, *programInventoryInterval)
+		if err := mgr.Add(inventory); err != nil {
+			setupLog.Error(err, "unable to set up program inventory")
+			os.Exit(1)
+		}
+	}
+
 	//+kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
@@ -512,7 +674,8 @@ func main() {
 		os.Exit(1)
 	}
 
-	metricsServer, err := newAgentMetricsServer(internalMetricsSocketPath)
+	mapInspector := bpfmanagent.NewMapInspector(mgr.GetClient(), commonApp.BpfmanClient, nodeName)
+	metricsServer, err := newAgentMetricsServer(internalMetricsSocketPath, mapInspector)
 	if err != nil {
 		setupLog.Error(err, "failed to set up metrics server")
 		os.Exit(1)
@@ -522,7 +685,7 @@ func main() {
 
 	var ifaceDiscovery *interfaceDiscovery
 	if enableInterfacesDiscovery {
-		ifaceDiscovery, err = newInterfaceDiscovery(ctx, commonApp.Interfaces)
+		ifaceDiscovery, err = newInterfaceDiscovery(ctx, commonApp.Interfaces, nodeName, ifaceEvents...)
 		if err != nil {
 			setupLog.Error(err, "failed to set up interface discovery")
 			os.Exit(1)
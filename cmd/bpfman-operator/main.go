@@ -46,6 +46,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
@@ -251,6 +252,13 @@ func main() {
 
 	//+kubebuilder:scaffold:builder
 
+	// Publish cluster-wide application metrics derived from the
+	// BpfApplicationState objects on the operator metrics endpoint.
+	if err := metrics.Registry.Register(bpfmanoperator.NewApplicationMetricsCollector(mgr.GetClient())); err != nil {
+		setupLog.Error(err, "unable to register application metrics collector")
+		os.Exit(1)
+	}
+
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
 		setupLog.Error(err, "unable to set up health check")
 		os.Exit(1)
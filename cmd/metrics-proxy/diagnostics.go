@@ -0,0 +1,379 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"time"
+
+	authnv1 "k8s.io/api/authentication/v1"
+	authzv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/bpfman/bpfman-operator/internal"
+)
+
+const (
+	defaultSocketPath = "/var/run/bpfman-agent/metrics.sock"
+
+	// The OpenShift service CA bundle is injected alongside the
+	// service account token in every pod.
+	defaultServiceCAPath = "/var/run/secrets/kubernetes.io/serviceaccount/service-ca.crt"
+	namespacePath        = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+
+	diagnosticTimeout = 10 * time.Second
+)
+
+// defaultExpectedMetricFamilies are the metric families that a healthy
+// bpfman-agent always exports through the agent metrics socket.
+var defaultExpectedMetricFamilies = []string{
+	"controller_runtime_reconcile_total",
+	"controller_runtime_active_workers",
+	"workqueue_depth",
+}
+
+// timed runs check and records how long it took in the result.
+func timed(check func() TestResult) TestResult {
+	start := time.Now()
+	result := check()
+	result.LatencyMs = time.Since(start).Milliseconds()
+	return result
+}
+
+// envOrDefault returns the value of the environment variable key, or def
+// if it is unset or empty.
+func envOrDefault(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
+
+// testSocketPermissions verifies that the agent metrics socket exists, is
+// a Unix socket that is readable and writable by the proxy, and accepts
+// connections.
+func testSocketPermissions(socketPath string) TestResult {
+	const name = "socket_permissions"
+	const remediation = "Check that the bpfman-agent container is running on this node and that " +
+		"/var/run/bpfman-agent is mounted into both the bpfman-daemon and metrics-proxy pods."
+
+	info, err := os.Stat(socketPath)
+	if err != nil {
+		return TestResult{
+			Name:        name,
+			Status:      "failed",
+			Message:     fmt.Sprintf("stat %s failed: %v", socketPath, err),
+			Remediation: remediation,
+		}
+	}
+
+	if info.Mode()&os.ModeSocket == 0 {
+		return TestResult{
+			Name:        name,
+			Status:      "failed",
+			Message:     fmt.Sprintf("%s is not a Unix socket (mode %s)", socketPath, info.Mode()),
+			Remediation: "Remove the stale file and restart the bpfman-agent so that it recreates the socket.",
+		}
+	}
+
+	// The agent creates the socket world read/writable so that the
+	// unprivileged proxy can connect to it.
+	if perm := info.Mode().Perm(); perm&0006 != 0006 {
+		return TestResult{
+			Name:        name,
+			Status:      "failed",
+			Message:     fmt.Sprintf("%s has permissions %04o, expected at least 0666", socketPath, perm),
+			Remediation: "Restart the bpfman-agent so that it recreates the socket with mode 0666.",
+		}
+	}
+
+	conn, err := net.DialTimeout("unix", socketPath, diagnosticTimeout)
+	if err != nil {
+		return TestResult{
+			Name:        name,
+			Status:      "failed",
+			Message:     fmt.Sprintf("connect to %s failed: %v", socketPath, err),
+			Remediation: remediation,
+		}
+	}
+	conn.Close()
+
+	return TestResult{
+		Name:   name,
+		Status: "passed",
+	}
+}
+
+// testTLSChain verifies the certificate presented on the metrics port
+// against the service CA bundle, including the service DNS name that
+// Prometheus uses to scrape the proxy. The check is skipped when no
+// service CA bundle is available, e.g. on clusters without the OpenShift
+// service CA operator.
+func testTLSChain(caPath string) TestResult {
+	const name = "tls_chain"
+
+	caPEM, err := os.ReadFile(caPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return TestResult{
+				Name:    name,
+				Status:  "skipped",
+				Message: fmt.Sprintf("service CA bundle %s not found", caPath),
+			}
+		}
+		return TestResult{
+			Name:        name,
+			Status:      "failed",
+			Message:     fmt.Sprintf("read service CA bundle %s failed: %v", caPath, err),
+			Remediation: "Check that the service account token volume is mounted in the metrics-proxy pod.",
+		}
+	}
+
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(caPEM) {
+		return TestResult{
+			Name:        name,
+			Status:      "failed",
+			Message:     fmt.Sprintf("no certificates found in service CA bundle %s", caPath),
+			Remediation: "Check the health of the service-ca operator in openshift-service-ca.",
+		}
+	}
+
+	conn, err := tls.DialWithDialer(&net.Dialer{Timeout: diagnosticTimeout}, "tcp", "localhost:8443",
+		&tls.Config{InsecureSkipVerify: true})
+	if err != nil {
+		return TestResult{
+			Name:        name,
+			Status:      "failed",
+			Message:     fmt.Sprintf("TLS handshake failed: %v", err),
+			Remediation: "Check the metrics-proxy logs for errors loading the serving certificate.",
+		}
+	}
+	defer conn.Close()
+
+	certs := conn.ConnectionState().PeerCertificates
+	if len(certs) == 0 {
+		return TestResult{
+			Name:        name,
+			Status:      "failed",
+			Message:     "server presented no certificates",
+			Remediation: "Check the metrics-proxy logs for errors loading the serving certificate.",
+		}
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+
+	opts := x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+	}
+	if ns, err := os.ReadFile(namespacePath); err == nil {
+		opts.DNSName = fmt.Sprintf("%s.%s.svc", internal.BpfmanAgentMetricsServiceName, strings.TrimSpace(string(ns)))
+	}
+
+	if _, err := certs[0].Verify(opts); err != nil {
+		return TestResult{
+			Name:   name,
+			Status: "failed",
+			Message: fmt.Sprintf("certificate (subject %q, expires %s) does not verify: %v",
+				certs[0].Subject.String(), certs[0].NotAfter.Format(time.RFC3339), err),
+			Remediation: "Delete the agent-metrics-tls secret so that the service CA reissues it, " +
+				"then restart the metrics-proxy pods.",
+		}
+	}
+
+	return TestResult{
+		Name:   name,
+		Status: "passed",
+	}
+}
+
+// testTokenAuthorization resolves the token to a user with a TokenReview
+// and then asks the API server, with a SubjectAccessReview, whether that
+// user may read the metrics endpoints. This mirrors the checks performed
+// by the authentication and authorization filter on the metrics server.
+func testTokenAuthorization(token string) TestResult {
+	const name = "token_authorization"
+
+	cfg, err := ctrl.GetConfig()
+	if err != nil {
+		return TestResult{
+			Name:        name,
+			Status:      "failed",
+			Message:     fmt.Sprintf("load kubeconfig failed: %v", err),
+			Remediation: "Run the self-test from within the metrics-proxy pod.",
+		}
+	}
+
+	clientset, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return TestResult{
+			Name:    name,
+			Status:  "failed",
+			Message: fmt.Sprintf("create client failed: %v", err),
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), diagnosticTimeout)
+	defer cancel()
+
+	review, err := clientset.AuthenticationV1().TokenReviews().Create(ctx, &authnv1.TokenReview{
+		Spec: authnv1.TokenReviewSpec{Token: token},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return TestResult{
+			Name:        name,
+			Status:      "failed",
+			Message:     fmt.Sprintf("TokenReview failed: %v", err),
+			Remediation: "Check that the bpfman-daemon service account is bound to system:auth-delegator.",
+		}
+	}
+	if !review.Status.Authenticated {
+		return TestResult{
+			Name:        name,
+			Status:      "failed",
+			Message:     fmt.Sprintf("token is not authenticated: %s", review.Status.Error),
+			Remediation: "Generate a fresh token, e.g. with 'kubectl create token', and retry.",
+		}
+	}
+
+	user := review.Status.User
+	extra := make(map[string]authzv1.ExtraValue, len(user.Extra))
+	for k, v := range user.Extra {
+		extra[k] = authzv1.ExtraValue(v)
+	}
+
+	for _, path := range []string{"/metrics", "/agent-metrics"} {
+		sar, err := clientset.AuthorizationV1().SubjectAccessReviews().Create(ctx, &authzv1.SubjectAccessReview{
+			Spec: authzv1.SubjectAccessReviewSpec{
+				User:   user.Username,
+				UID:    user.UID,
+				Groups: user.Groups,
+				Extra:  extra,
+				NonResourceAttributes: &authzv1.NonResourceAttributes{
+					Path: path,
+					Verb: "get",
+				},
+			},
+		}, metav1.CreateOptions{})
+		if err != nil {
+			return TestResult{
+				Name:        name,
+				Status:      "failed",
+				Message:     fmt.Sprintf("SubjectAccessReview for %s failed: %v", path, err),
+				Remediation: "Check that the bpfman-daemon service account is bound to system:auth-delegator.",
+			}
+		}
+		if !sar.Status.Allowed {
+			return TestResult{
+				Name:   name,
+				Status: "failed",
+				Message: fmt.Sprintf("user %q is not allowed to get %s: %s",
+					user.Username, path, sar.Status.Reason),
+				Remediation: "Bind the bpfman-metrics-reader ClusterRole to the user or service account owning the token.",
+			}
+		}
+	}
+
+	return TestResult{
+		Name:    name,
+		Status:  "passed",
+		Message: fmt.Sprintf("authorized as %s", user.Username),
+	}
+}
+
+// testMetricFamilies checks that every expected metric family is present
+// in the metrics scraped from the agent.
+func testMetricFamilies(body string, expected []string) TestResult {
+	const name = "metric_families"
+
+	if body == "" {
+		return TestResult{
+			Name:        name,
+			Status:      "failed",
+			Message:     "no agent metrics available",
+			Remediation: "Resolve the agent-metrics failure first.",
+		}
+	}
+
+	families := parseMetricFamilies(body)
+	var missing []string
+	for _, family := range expected {
+		if _, ok := families[family]; !ok {
+			missing = append(missing, family)
+		}
+	}
+
+	if len(missing) > 0 {
+		return TestResult{
+			Name:        name,
+			Status:      "failed",
+			Message:     fmt.Sprintf("missing metric families: %s", strings.Join(missing, ", ")),
+			Metrics:     len(families),
+			Remediation: "Check the bpfman-agent logs; its controllers may not have started.",
+		}
+	}
+
+	return TestResult{
+		Name:    name,
+		Status:  "passed",
+		Metrics: len(families),
+	}
+}
+
+// parseMetricFamilies returns the set of metric family names declared by
+// "# TYPE" lines in a Prometheus text exposition.
+func parseMetricFamilies(body string) map[string]struct{} {
+	families := map[string]struct{}{}
+	scanner := bufio.NewScanner(strings.NewReader(body))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) >= 3 && fields[0] == "#" && fields[1] == "TYPE" {
+			families[fields[2]] = struct{}{}
+		}
+	}
+	return families
+}
+
+// expectedMetricFamilies returns the metric families to look for, taken
+// from the comma separated EXPECTED_METRICS environment variable when set.
+func expectedMetricFamilies() []string {
+	v := os.Getenv("EXPECTED_METRICS")
+	if v == "" {
+		return defaultExpectedMetricFamilies
+	}
+	var families []string
+	for _, family := range strings.Split(v, ",") {
+		if family = strings.TrimSpace(family); family != "" {
+			families = append(families, family)
+		}
+	}
+	return families
+}
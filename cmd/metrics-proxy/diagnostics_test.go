@@ -0,0 +1,181 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testMetricsBody = `# HELP controller_runtime_reconcile_total Total number of reconciliations per controller
+# TYPE controller_runtime_reconcile_total counter
+controller_runtime_reconcile_total{controller="application",result="success"} 12
+# HELP workqueue_depth Current depth of workqueue
+# TYPE workqueue_depth gauge
+workqueue_depth{name="application"} 0
+#TYPE not_a_family gauge
+# TYPE
+# HELP only_help A family without a TYPE line
+`
+
+func TestParseMetricFamilies(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want []string
+	}{
+		{name: "empty", body: ""},
+		{name: "samples only", body: "workqueue_depth 0\n"},
+		{
+			name: "type lines",
+			body: testMetricsBody,
+			want: []string{"controller_runtime_reconcile_total", "workqueue_depth"},
+		},
+		{
+			name: "extra whitespace",
+			body: "#   TYPE   workqueue_depth   gauge\n",
+			want: []string{"workqueue_depth"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			families := parseMetricFamilies(tt.body)
+			var got []string
+			for family := range families {
+				got = append(got, family)
+			}
+			require.ElementsMatch(t, tt.want, got)
+		})
+	}
+}
+
+func TestMetricFamilies(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		expected []string
+		status   string
+		message  string
+	}{
+		{
+			name:     "no metrics",
+			expected: defaultExpectedMetricFamilies,
+			status:   "failed",
+			message:  "no agent metrics available",
+		},
+		{
+			name:     "all present",
+			body:     testMetricsBody,
+			expected: []string{"controller_runtime_reconcile_total", "workqueue_depth"},
+			status:   "passed",
+		},
+		{
+			name:     "missing families",
+			body:     testMetricsBody,
+			expected: defaultExpectedMetricFamilies,
+			status:   "failed",
+			message:  "missing metric families: controller_runtime_active_workers",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := testMetricFamilies(tt.body, tt.expected)
+			require.Equal(t, "metric_families", result.Name)
+			require.Equal(t, tt.status, result.Status)
+			require.Equal(t, tt.message, result.Message)
+			if tt.status == "failed" {
+				require.NotEmpty(t, result.Remediation)
+			}
+			if tt.body != "" {
+				require.Equal(t, 2, result.Metrics)
+			}
+		})
+	}
+}
+
+func TestExpectedMetricFamilies(t *testing.T) {
+	tests := []struct {
+		name string
+		env  string
+		want []string
+	}{
+		{name: "unset", want: defaultExpectedMetricFamilies},
+		{name: "list", env: "workqueue_depth,bpfman_programs", want: []string{"workqueue_depth", "bpfman_programs"}},
+		{name: "blanks", env: " workqueue_depth , ,bpfman_programs,", want: []string{"workqueue_depth", "bpfman_programs"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("EXPECTED_METRICS", tt.env)
+			require.Equal(t, tt.want, expectedMetricFamilies())
+		})
+	}
+}
+
+func TestSocketPermissions(t *testing.T) {
+	dir := t.TempDir()
+
+	socketPath := filepath.Join(dir, "metrics.sock")
+	listener, err := net.Listen("unix", socketPath)
+	require.NoError(t, err)
+	defer listener.Close()
+
+	regularPath := filepath.Join(dir, "regular")
+	require.NoError(t, os.WriteFile(regularPath, nil, 0666))
+
+	tests := []struct {
+		name    string
+		path    string
+		perm    os.FileMode
+		status  string
+		message string
+	}{
+		{name: "missing", path: filepath.Join(dir, "missing.sock"), status: "failed", message: "stat"},
+		{name: "not a socket", path: regularPath, status: "failed", message: "is not a Unix socket"},
+		{name: "not world writable", path: socketPath, perm: 0660, status: "failed", message: "has permissions 0660"},
+		{name: "accessible", path: socketPath, perm: 0666, status: "passed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.perm != 0 {
+				require.NoError(t, os.Chmod(tt.path, tt.perm))
+			}
+			result := testSocketPermissions(tt.path)
+			require.Equal(t, "socket_permissions", result.Name)
+			require.Equal(t, tt.status, result.Status)
+			require.Contains(t, result.Message, tt.message)
+			if tt.status == "failed" {
+				require.NotEmpty(t, result.Remediation)
+			}
+		})
+	}
+
+	// The socket has the right permissions, but nothing accepts connections.
+	listener.Close()
+	stale := filepath.Join(dir, "stale.sock")
+	staleListener, err := net.ListenUnix("unix", &net.UnixAddr{Name: stale, Net: "unix"})
+	require.NoError(t, err)
+	staleListener.SetUnlinkOnClose(false)
+	require.NoError(t, staleListener.Close())
+	require.NoError(t, os.Chmod(stale, 0666))
+	result := testSocketPermissions(stale)
+	require.Equal(t, "failed", result.Status)
+	require.Contains(t, result.Message, "connect to "+stale+" failed")
+}
@@ -35,6 +35,7 @@ import (
 	"github.com/bpfman/bpfman-operator/internal/version"
 	"github.com/go-logr/logr"
 	"go.uber.org/zap/zapcore"
+	"k8s.io/client-go/kubernetes"
 
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
@@ -60,7 +61,7 @@ func main() {
 
 	flag.BoolVar(&enableHTTP2, "enable-http2", false, "Enable HTTP/2 on the metrics server")
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8443", "Metrics server address")
-	flag.StringVar(&socketPath, "socket", "/var/run/bpfman-agent/metrics.sock", "Path to Unix socket to proxy")
+	flag.StringVar(&socketPath, "socket", defaultSocketPath, "Path to Unix socket to proxy")
 	flag.StringVar(&certDir, "cert-dir", "/tmp/k8s-webhook-server/serving-certs", "Directory for TLS certs")
 	flag.BoolVar(&showVersion, "version", false, "Print version information and exit.")
 	flag.Parse()
@@ -136,6 +137,13 @@ func main() {
 	// Must create metrics server after manager so filters (i.e.,
 	// FilterProvider) can access the client config.
 	metricsHandler := redirectAgentMetrics("/agent-metrics", "/metrics", proxy, log)
+	clientset, err := kubernetes.NewForConfigAndClient(mgr.GetConfig(), mgr.GetHTTPClient())
+	if err != nil {
+		log.Error(err, "create clientset failed")
+		os.Exit(1)
+	}
+	mapsHandler := authorizeApplicationMaps(clientset,
+		redirectAgentMetrics("/agent-maps", "/maps", proxy, log), log)
 	metricsServer, err := server.NewServer(server.Options{
 		BindAddress:    metricsAddr,
 		SecureServing:  true,
@@ -144,6 +152,7 @@ func main() {
 		FilterProvider: filters.WithAuthenticationAndAuthorization,
 		ExtraHandlers: map[string]http.Handler{
 			"/agent-metrics": metricsHandler,
+			"/agent-maps":    mapsHandler,
 		},
 	}, mgr.GetConfig(), mgr.GetHTTPClient())
 	if err != nil {
@@ -215,10 +224,12 @@ func readiness(transport *http.Transport, upstreamURL *url.URL) func(*http.Reque
 }
 
 type TestResult struct {
-	Name    string `json:"name"`
-	Status  string `json:"status"`
-	Message string `json:"message,omitempty"`
-	Metrics int    `json:"metrics,omitempty"`
+	Name        string `json:"name"`
+	Status      string `json:"status"`
+	Message     string `json:"message,omitempty"`
+	Metrics     int    `json:"metrics,omitempty"`
+	LatencyMs   int64  `json:"latencyMs"`
+	Remediation string `json:"remediation,omitempty"`
 }
 
 type TestResults struct {
@@ -236,25 +247,53 @@ func runSelfTests() {
 	if token == "" {
 		results.Summary = "failed"
 		results.Results = append(results.Results, TestResult{
-			Name:    "token_check",
-			Status:  "failed",
-			Message: "TOKEN environment variable not set",
+			Name:        "token_check",
+			Status:      "failed",
+			Message:     "TOKEN environment variable not set",
+			Remediation: "Run the self-test as 'env TOKEN=$(kubectl create token <sa>) /metrics-proxy test'.",
 		})
 		outputResults(results)
 		os.Exit(1)
 	}
 
 	// Test health endpoint
-	healthResult := testHealthEndpoint()
-	results.Results = append(results.Results, healthResult)
+	results.Results = append(results.Results, timed(testHealthEndpoint))
+
+	// Test that the agent metrics socket is reachable by the proxy
+	socketPath := envOrDefault("SOCKET", defaultSocketPath)
+	results.Results = append(results.Results, timed(func() TestResult {
+		return testSocketPermissions(socketPath)
+	}))
+
+	// Test the serving certificate against the service CA
+	caPath := envOrDefault("SERVICE_CA", defaultServiceCAPath)
+	results.Results = append(results.Results, timed(func() TestResult {
+		return testTLSChain(caPath)
+	}))
+
+	// Test that the token is authorized to read metrics
+	results.Results = append(results.Results, timed(func() TestResult {
+		return testTokenAuthorization(token)
+	}))
 
 	// Test direct metrics endpoint
-	directResult := testMetricsEndpoint("/metrics", token)
-	results.Results = append(results.Results, directResult)
+	results.Results = append(results.Results, timed(func() TestResult {
+		result, _ := testMetricsEndpoint("/metrics", token)
+		return result
+	}))
 
 	// Test agent metrics proxy endpoint
-	agentResult := testMetricsEndpoint("/agent-metrics", token)
-	results.Results = append(results.Results, agentResult)
+	var agentMetrics string
+	results.Results = append(results.Results, timed(func() TestResult {
+		var result TestResult
+		result, agentMetrics = testMetricsEndpoint("/agent-metrics", token)
+		return result
+	}))
+
+	// Test that the agent exports the expected metric families
+	results.Results = append(results.Results, timed(func() TestResult {
+		return testMetricFamilies(agentMetrics, expectedMetricFamilies())
+	}))
 
 	// Determine overall status
 	failed := false
@@ -281,9 +320,10 @@ func testHealthEndpoint() TestResult {
 	resp, err := client.Get("http://localhost:8081/healthz")
 	if err != nil {
 		return TestResult{
-			Name:    "health_check",
-			Status:  "failed",
-			Message: fmt.Sprintf("request failed: %v", err),
+			Name:        "health_check",
+			Status:      "failed",
+			Remediation: "Check that the metrics-proxy container is running and its health probe listens on :8081.",
+			Message:     fmt.Sprintf("request failed: %v", err),
 		}
 	}
 	defer resp.Body.Close()
@@ -291,25 +331,28 @@ func testHealthEndpoint() TestResult {
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return TestResult{
-			Name:    "health_check",
-			Status:  "failed",
-			Message: fmt.Sprintf("read body failed: %v", err),
+			Name:        "health_check",
+			Status:      "failed",
+			Remediation: "Check that the metrics-proxy container is running and its health probe listens on :8081.",
+			Message:     fmt.Sprintf("read body failed: %v", err),
 		}
 	}
 
 	if resp.StatusCode != http.StatusOK {
 		return TestResult{
-			Name:    "health_check",
-			Status:  "failed",
-			Message: fmt.Sprintf("status %d, body: %s", resp.StatusCode, string(body)),
+			Name:        "health_check",
+			Status:      "failed",
+			Remediation: "Check that the metrics-proxy container is running and its health probe listens on :8081.",
+			Message:     fmt.Sprintf("status %d, body: %s", resp.StatusCode, string(body)),
 		}
 	}
 
 	if !strings.Contains(string(body), "ok") {
 		return TestResult{
-			Name:    "health_check",
-			Status:  "failed",
-			Message: fmt.Sprintf("response does not contain 'ok': %s", string(body)),
+			Name:        "health_check",
+			Status:      "failed",
+			Remediation: "Check that the metrics-proxy container is running and its health probe listens on :8081.",
+			Message:     fmt.Sprintf("response does not contain 'ok': %s", string(body)),
 		}
 	}
 
@@ -319,7 +362,9 @@ func testHealthEndpoint() TestResult {
 	}
 }
 
-func testMetricsEndpoint(path, token string) TestResult {
+// testMetricsEndpoint scrapes path on the secure metrics port using token
+// and returns the result together with the scraped body.
+func testMetricsEndpoint(path, token string) (TestResult, string) {
 	// Create TLS config that accepts self-signed certificates.
 	tlsConfig := &tls.Config{
 		InsecureSkipVerify: true,
@@ -342,49 +387,40 @@ func testMetricsEndpoint(path, token string) TestResult {
 	url := fmt.Sprintf("https://localhost:8443%s", path)
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
-		return TestResult{
-			Name:    strings.TrimPrefix(path, "/"),
-			Status:  "failed",
-			Message: fmt.Sprintf("create request failed: %v", err),
-		}
+		return metricsFailure(path, fmt.Sprintf("create request failed: %v", err))
 	}
 
 	req.Header.Set("Authorization", "Bearer "+token)
 
 	resp, err := client.Do(req)
 	if err != nil {
-		return TestResult{
-			Name:    strings.TrimPrefix(path, "/"),
-			Status:  "failed",
-			Message: fmt.Sprintf("request failed: %v", err),
-		}
+		return metricsFailure(path, fmt.Sprintf("request failed: %v", err))
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return TestResult{
-			Name:    strings.TrimPrefix(path, "/"),
-			Status:  "failed",
-			Message: fmt.Sprintf("read body failed: %v", err),
-		}
+		return metricsFailure(path, fmt.Sprintf("read body failed: %v", err))
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return TestResult{
-			Name:    strings.TrimPrefix(path, "/"),
-			Status:  "failed",
-			Message: fmt.Sprintf("status %d, body: %s", resp.StatusCode, string(body)),
+		result, _ := metricsFailure(path, fmt.Sprintf("status %d, body: %s", resp.StatusCode, string(body)))
+		switch resp.StatusCode {
+		case http.StatusUnauthorized:
+			result.Remediation = "The token was rejected; generate a fresh token and retry."
+		case http.StatusForbidden:
+			result.Remediation = "Bind the bpfman-metrics-reader ClusterRole to the user or service account owning the token."
+		default:
+			if path == "/agent-metrics" {
+				result.Remediation = "Check the socket_permissions result and the bpfman-agent logs."
+			}
 		}
+		return result, ""
 	}
 
 	bodyStr := string(body)
 	if !strings.Contains(bodyStr, "# HELP") || !strings.Contains(bodyStr, "# TYPE") {
-		return TestResult{
-			Name:    strings.TrimPrefix(path, "/"),
-			Status:  "failed",
-			Message: "response does not contain Prometheus metrics format",
-		}
+		return metricsFailure(path, "response does not contain Prometheus metrics format")
 	}
 
 	// Count metrics lines (non-comment, non-empty lines).
@@ -401,7 +437,16 @@ func testMetricsEndpoint(path, token string) TestResult {
 		Name:    strings.TrimPrefix(path, "/"),
 		Status:  "passed",
 		Metrics: metricCount,
-	}
+	}, bodyStr
+}
+
+// metricsFailure builds a failed result for a metrics endpoint check.
+func metricsFailure(path, message string) (TestResult, string) {
+	return TestResult{
+		Name:    strings.TrimPrefix(path, "/"),
+		Status:  "failed",
+		Message: message,
+	}, ""
 }
 
 func getServerCertPool() *x509.CertPool {
@@ -0,0 +1,113 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/go-logr/logr"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// authorizeApplicationMaps authorizes each request for the maps of an
+// application with a SubjectAccessReview to get the application, so that a
+// user bound to the map-reader ClusterRole can only inspect the maps of the
+// applications it can read. The metrics server filter only authorizes the
+// /agent-maps path.
+func authorizeApplicationMaps(clientset kubernetes.Interface, next http.Handler, logger logr.Logger) http.Handler {
+	logger = logger.WithName("maps-authz")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		namespace := query.Get("namespace")
+		resource := ""
+		switch query.Get("kind") {
+		case "ClusterBpfApplication":
+			resource = "clusterbpfapplications"
+		case "BpfApplication":
+			resource = "bpfapplications"
+		case "":
+			resource = "clusterbpfapplications"
+			if namespace != "" {
+				resource = "bpfapplications"
+			}
+		default:
+			http.Error(w, fmt.Sprintf("unsupported kind %q", query.Get("kind")), http.StatusBadRequest)
+			return
+		}
+		if resource == "clusterbpfapplications" {
+			namespace = ""
+		}
+
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || token == "" {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		review, err := clientset.AuthenticationV1().TokenReviews().Create(r.Context(), &authenticationv1.TokenReview{
+			Spec: authenticationv1.TokenReviewSpec{Token: token},
+		}, metav1.CreateOptions{})
+		if err != nil {
+			logger.Error(err, "token review failed")
+			http.Error(w, "Authentication failed", http.StatusInternalServerError)
+			return
+		}
+		if !review.Status.Authenticated {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		user := review.Status.User
+		extra := map[string]authorizationv1.ExtraValue{}
+		for key, value := range user.Extra {
+			extra[key] = authorizationv1.ExtraValue(value)
+		}
+		sar, err := clientset.AuthorizationV1().SubjectAccessReviews().Create(r.Context(), &authorizationv1.SubjectAccessReview{
+			Spec: authorizationv1.SubjectAccessReviewSpec{
+				User:   user.Username,
+				UID:    user.UID,
+				Groups: user.Groups,
+				Extra:  extra,
+				ResourceAttributes: &authorizationv1.ResourceAttributes{
+					Namespace: namespace,
+					Verb:      "get",
+					Group:     "bpfman.io",
+					Resource:  resource,
+					Name:      query.Get("name"),
+				},
+			},
+		}, metav1.CreateOptions{})
+		if err != nil {
+			logger.Error(err, "subject access review failed", "user", user.Username)
+			http.Error(w, fmt.Sprintf("Authorization for user %s failed", user.Username), http.StatusInternalServerError)
+			return
+		}
+		if !sar.Status.Allowed {
+			logger.Info("map inspection denied", "user", user.Username, "resource", resource,
+				"namespace", namespace, "name", query.Get("name"), "reason", sar.Status.Reason)
+			http.Error(w, fmt.Sprintf("Authorization denied for user %s", user.Username), http.StatusForbidden)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
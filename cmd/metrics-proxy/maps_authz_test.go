@@ -0,0 +1,80 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/require"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestAuthorizeApplicationMaps(t *testing.T) {
+	clientset := fake.NewClientset()
+	clientset.PrependReactor("create", "tokenreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authenticationv1.TokenReview)
+		review.Status.Authenticated = review.Spec.Token == "team-a-token"
+		review.Status.User = authenticationv1.UserInfo{Username: "team-a", Groups: []string{"developers"}}
+		return true, review, nil
+	})
+	var reviewed *authorizationv1.ResourceAttributes
+	clientset.PrependReactor("create", "subjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		sar := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SubjectAccessReview)
+		reviewed = sar.Spec.ResourceAttributes
+		// team-a can only read the BpfApplications of its namespace.
+		sar.Status.Allowed = sar.Spec.User == "team-a" &&
+			reviewed.Resource == "bpfapplications" && reviewed.Namespace == "team-a"
+		return true, sar, nil
+	})
+	handler := authorizeApplicationMaps(clientset, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}), logr.Discard())
+
+	serve := func(token, query string) int {
+		req := httptest.NewRequest(http.MethodGet, "/agent-maps?"+query, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	require.Equal(t, http.StatusOK, serve("team-a-token", "namespace=team-a&name=stats"))
+	require.Equal(t, &authorizationv1.ResourceAttributes{
+		Namespace: "team-a",
+		Verb:      "get",
+		Group:     "bpfman.io",
+		Resource:  "bpfapplications",
+		Name:      "stats",
+	}, reviewed)
+
+	require.Equal(t, http.StatusForbidden, serve("team-a-token", "namespace=team-b&name=stats"))
+	require.Equal(t, http.StatusForbidden, serve("team-a-token", "name=firewall"))
+	require.Equal(t, "clusterbpfapplications", reviewed.Resource)
+	require.Empty(t, reviewed.Namespace)
+	require.Equal(t, http.StatusBadRequest, serve("team-a-token", "kind=Pod&name=firewall"))
+	require.Equal(t, http.StatusUnauthorized, serve("", "namespace=team-a&name=stats"))
+	require.Equal(t, http.StatusUnauthorized, serve("other-token", "namespace=team-a&name=stats"))
+}
@@ -36,8 +36,10 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
 //+kubebuilder:rbac:groups=bpfman.io,resources=clusterbpfapplications,verbs=get;list;watch
@@ -52,6 +54,13 @@ import (
 
 type ClBpfApplicationReconciler struct {
 	ReconcilerCommon
+	// Repairs, when set, triggers reconciles to re-attach the links found
+	// missing on the node.
+	Repairs <-chan event.GenericEvent
+	// InterfaceEvents, when set, triggers reconciles when interfaces are
+	// created or deleted on the node, so that the programs attached with
+	// interface discovery follow them.
+	InterfaceEvents <-chan event.GenericEvent
 	currentApp      *bpfmaniov1alpha1.ClusterBpfApplication
 	currentAppState *bpfmaniov1alpha1.ClusterBpfApplicationState
 }
@@ -59,6 +68,20 @@ type ClBpfApplicationReconciler struct {
 type ClProgramReconcilerCommon struct {
 	currentProgram      *bpfmaniov1alpha1.ClBpfApplicationProgram
 	currentProgramState *bpfmaniov1alpha1.ClBpfApplicationProgramState
+	appName             string
+	appState            *bpfmaniov1alpha1.ClusterBpfApplicationState
+}
+
+// attachMetadata returns the metadata of the attach request of the link uuid
+// of the current program, attached for pod if it isn't nil.
+func (r *ClProgramReconcilerCommon) attachMetadata(uuid string, pod *bpfmaniov1alpha1.AttachPodState) map[string]string {
+	return attachMetadata(uuid, "", r.appName, r.currentProgram.Name, pod)
+}
+
+// getAppState returns the application state the events of the links of the
+// current program are recorded on.
+func (r *ClProgramReconcilerCommon) getAppState() client.Object {
+	return r.appState
 }
 
 func (r *ClBpfApplicationReconciler) getAppStateName() string {
@@ -95,7 +118,7 @@ func (r *ClBpfApplicationReconciler) setAppLoadStatus(status bpfmaniov1alpha1.Ap
 // programs on the node via bpfman, and create or update a BpfApplicationState
 // object to reflect per node state information.
 func (r *ClBpfApplicationReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
+	b := ctrl.NewControllerManagedBy(mgr).
 		For(&bpfmaniov1alpha1.ClusterBpfApplication{}, builder.WithPredicates(predicate.And(predicate.GenerationChangedPredicate{}, predicate.ResourceVersionChangedPredicate{}))).
 		WithOptions(controller.Options{MaxConcurrentReconciles: 1}).
 		Owns(&bpfmaniov1alpha1.ClusterBpfApplicationState{},
@@ -116,7 +139,19 @@ func (r *ClBpfApplicationReconciler) SetupWithManager(mgr ctrl.Manager) error {
 			&handler.EnqueueRequestForObject{},
 			builder.WithPredicates(podOnNodePredicate(r.NodeName)),
 		).
-		Complete(r)
+		// Watch for changes in BpfPriorityClasses since they determine the
+		// priority of the links that reference them.
+		Watches(
+			&bpfmaniov1alpha1.BpfPriorityClass{},
+			&handler.EnqueueRequestForObject{},
+		)
+	if r.Repairs != nil {
+		b = b.WatchesRawSource(source.Channel(r.Repairs, &handler.EnqueueRequestForObject{}))
+	}
+	if r.InterfaceEvents != nil {
+		b = b.WatchesRawSource(source.Channel(r.InterfaceEvents, &handler.EnqueueRequestForObject{}))
+	}
+	return b.Complete(r)
 }
 
 func (r *ClBpfApplicationReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -150,6 +185,14 @@ func (r *ClBpfApplicationReconciler) Reconcile(ctx context.Context, req ctrl.Req
 	for appProgramIndex := range appPrograms.Items {
 		r.currentApp = &appPrograms.Items[appProgramIndex]
 
+		// Attribute the bpfman calls made for this application in the
+		// audit log.
+		ctx := bpfmanagentinternal.WithAuditSubject(ctx, bpfmanagentinternal.AuditSubject{
+			Kind: clusterBpfApplicationKind,
+			Name: r.currentApp.Name,
+			UID:  string(r.currentApp.UID),
+		})
+
 		r.Logger.Info("Reconciling ClusterBpfApplication", "Name", r.currentApp.Name)
 
 		// Get the BpfApplicationState object for this node if it exists.
@@ -187,7 +230,7 @@ func (r *ClBpfApplicationReconciler) Reconcile(ctx context.Context, req ctrl.Req
 			// There's no point continuing to reconcile the links if we
 			// can't load the code.
 			r.Logger.Error(err, "failed to reconcileLoad")
-			r.updateBpfAppStateCondition(r, bpfmaniov1alpha1.BpfAppStateCondError)
+			r.updateBpfAppStateCondition(r, loadFailureCondition(err))
 			statusChanged, err := r.updateBpfAppStateStatus(ctx, nil)
 			if err != nil {
 				r.Logger.Error(err, "failed to update BpfApplicationState status", "Name", r.currentApp.Name)
@@ -317,6 +360,8 @@ func (r *ClBpfApplicationReconciler) getProgramReconciler(prog *bpfmaniov1alpha1
 			ClProgramReconcilerCommon: ClProgramReconcilerCommon{
 				currentProgram:      prog,
 				currentProgramState: progState,
+				appName:             r.currentApp.Name,
+				appState:            r.currentAppState,
 			},
 		}
 
@@ -326,6 +371,8 @@ func (r *ClBpfApplicationReconciler) getProgramReconciler(prog *bpfmaniov1alpha1
 			ClProgramReconcilerCommon: ClProgramReconcilerCommon{
 				currentProgram:      prog,
 				currentProgramState: progState,
+				appName:             r.currentApp.Name,
+				appState:            r.currentAppState,
 			},
 		}
 
@@ -335,6 +382,8 @@ func (r *ClBpfApplicationReconciler) getProgramReconciler(prog *bpfmaniov1alpha1
 			ClProgramReconcilerCommon: ClProgramReconcilerCommon{
 				currentProgram:      prog,
 				currentProgramState: progState,
+				appName:             r.currentApp.Name,
+				appState:            r.currentAppState,
 			},
 		}
 
@@ -344,6 +393,8 @@ func (r *ClBpfApplicationReconciler) getProgramReconciler(prog *bpfmaniov1alpha1
 			ClProgramReconcilerCommon: ClProgramReconcilerCommon{
 				currentProgram:      prog,
 				currentProgramState: progState,
+				appName:             r.currentApp.Name,
+				appState:            r.currentAppState,
 			},
 		}
 
@@ -353,6 +404,8 @@ func (r *ClBpfApplicationReconciler) getProgramReconciler(prog *bpfmaniov1alpha1
 			ClProgramReconcilerCommon: ClProgramReconcilerCommon{
 				currentProgram:      prog,
 				currentProgramState: progState,
+				appName:             r.currentApp.Name,
+				appState:            r.currentAppState,
 			},
 		}
 
@@ -362,6 +415,8 @@ func (r *ClBpfApplicationReconciler) getProgramReconciler(prog *bpfmaniov1alpha1
 			ClProgramReconcilerCommon: ClProgramReconcilerCommon{
 				currentProgram:      prog,
 				currentProgramState: progState,
+				appName:             r.currentApp.Name,
+				appState:            r.currentAppState,
 			},
 		}
 
@@ -371,6 +426,8 @@ func (r *ClBpfApplicationReconciler) getProgramReconciler(prog *bpfmaniov1alpha1
 			ClProgramReconcilerCommon: ClProgramReconcilerCommon{
 				currentProgram:      prog,
 				currentProgramState: progState,
+				appName:             r.currentApp.Name,
+				appState:            r.currentAppState,
 			},
 		}
 
@@ -380,6 +437,8 @@ func (r *ClBpfApplicationReconciler) getProgramReconciler(prog *bpfmaniov1alpha1
 			ClProgramReconcilerCommon: ClProgramReconcilerCommon{
 				currentProgram:      prog,
 				currentProgramState: progState,
+				appName:             r.currentApp.Name,
+				appState:            r.currentAppState,
 			},
 		}
 
@@ -389,6 +448,8 @@ func (r *ClBpfApplicationReconciler) getProgramReconciler(prog *bpfmaniov1alpha1
 			ClProgramReconcilerCommon: ClProgramReconcilerCommon{
 				currentProgram:      prog,
 				currentProgramState: progState,
+				appName:             r.currentApp.Name,
+				appState:            r.currentAppState,
 			},
 		}
 
@@ -414,6 +475,16 @@ func (r *ClBpfApplicationReconciler) checkProgramStatus() bpfmaniov1alpha1.BpfAp
 	return bpfmaniov1alpha1.BpfAppStateCondSuccess
 }
 
+// programStateCommons returns the fields common to all program states of the
+// current BpfApplicationState.
+func (r *ClBpfApplicationReconciler) programStateCommons() []*bpfmaniov1alpha1.BpfProgramStateCommon {
+	programs := make([]*bpfmaniov1alpha1.BpfProgramStateCommon, 0, len(r.currentAppState.Status.Programs))
+	for i := range r.currentAppState.Status.Programs {
+		programs = append(programs, &r.currentAppState.Status.Programs[i].BpfProgramStateCommon)
+	}
+	return programs
+}
+
 // getProgState returns the BpfApplicationProgramState object for the current node.
 func (r *ClBpfApplicationReconciler) getProgState(prog *bpfmaniov1alpha1.ClBpfApplicationProgram,
 	programs []bpfmaniov1alpha1.ClBpfApplicationProgramState) (*bpfmaniov1alpha1.ClBpfApplicationProgramState, error) {
@@ -739,17 +810,29 @@ func (r *ClBpfApplicationReconciler) getLoadRequest() (*gobpfman.LoadRequest, er
 }
 
 func (r *ClBpfApplicationReconciler) load(ctx context.Context) error {
+	if uuid := r.currentApp.Spec.AdoptUUID; uuid != "" {
+		adopted, err := r.adoptPrograms(ctx, uuid, r.programStateCommons())
+		if err != nil {
+			return fmt.Errorf("failed to adopt eBPF Programs: %w", err)
+		}
+		if adopted {
+			return nil
+		}
+	}
+
 	loadRequest, err := r.getLoadRequest()
 	if err != nil {
 		return fmt.Errorf("failed to get LoadRequest: %w", err)
 	}
 
-	loadResponse, err := r.BpfmanClient.Load(ctx, loadRequest)
+	programs, err := bpfmanagentinternal.LoadBpfmanProgram(ctx, r.BpfmanClient, r.AuditLog, loadRequest)
 	if err != nil {
-		return fmt.Errorf("failed to load eBPF Program: %v", err)
+		r.recordVerifierLog(ctx, r.currentAppState, r.verifierLogNamespace(), r.programStateCommons(), err)
+		return fmt.Errorf("failed to load eBPF Program: %w", err)
 	} else {
+		r.clearVerifierLog(ctx, r.currentAppState, r.verifierLogNamespace(), r.programStateCommons())
 		for p, program := range r.currentAppState.Status.Programs {
-			id, err := bpfmanagentinternal.GetBpfProgramId(program.Name, loadResponse.Programs)
+			id, err := bpfmanagentinternal.GetBpfProgramId(program.Name, programs)
 			// This should never happen because the bpfman load is all or nothing,
 			// and we aren't allowing users to add or remove programs from an
 			// existing BpfApplication.  However, if it does happen, log an error.
@@ -770,7 +853,7 @@ func (r *ClBpfApplicationReconciler) unload(ctx context.Context) {
 	for i := len(r.currentAppState.Status.Programs) - 1; i >= 0; i-- {
 		program := r.currentAppState.Status.Programs[i]
 		if program.ProgramId != nil {
-			err := bpfmanagentinternal.UnloadBpfmanProgram(ctx, r.BpfmanClient, *program.ProgramId)
+			err := bpfmanagentinternal.UnloadBpfmanProgram(ctx, r.BpfmanClient, r.AuditLog, *program.ProgramId)
 			if err != nil {
 				// This should never happen under normal operations.  However,
 				// it is possible that someone unloaded the program manually. In
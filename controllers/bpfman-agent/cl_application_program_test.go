@@ -19,17 +19,26 @@ package bpfmanagent
 import (
 	"context"
 	"fmt"
+	"os"
+	"path/filepath"
 	"reflect"
+	"strings"
 	"testing"
 
 	bpfmaniov1alpha1 "github.com/bpfman/bpfman-operator/apis/v1alpha1"
+	bpfmanagentinternal "github.com/bpfman/bpfman-operator/controllers/bpfman-agent/internal"
 	agenttestutils "github.com/bpfman/bpfman-operator/controllers/bpfman-agent/internal/test-utils"
 	"github.com/bpfman/bpfman-operator/internal"
 	testutils "github.com/bpfman/bpfman-operator/internal/test-utils"
 	"github.com/bpfman/bpfman-operator/pkg/helpers"
+	gobpfman "github.com/bpfman/bpfman/clients/gobpfman/v1"
 
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	v1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -39,6 +48,7 @@ import (
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/yaml"
 )
 
 func TestClBpfApplicationReconcilerGetBpfAppState(t *testing.T) {
@@ -466,6 +476,81 @@ func createFakeClusterReconciler(objs []runtime.Object, bpfApp *bpfmaniov1alpha1
 	return r
 }
 
+func TestClBpfApplicationControllerXdpAttachMode(t *testing.T) {
+	var (
+		fakeNode = testutils.NewNode("fake-control-plane")
+		ctx      = context.TODO()
+	)
+
+	// bpfman runs the XDP dispatcher of the interface in generic mode.
+	xdpState := bpfmanagentinternal.XdpLinkState{AttachMode: bpfmaniov1alpha1.XdpAttachModeGeneric, MTU: 1500}
+	defer func(f func(string, string) (bpfmanagentinternal.XdpLinkState, error)) { getXdpLinkState = f }(getXdpLinkState)
+	getXdpLinkState = func(netnsPath string, name string) (bpfmanagentinternal.XdpLinkState, error) {
+		require.Equal(t, fakeInt0, name)
+		return xdpState, nil
+	}
+
+	bpfApp := &bpfmaniov1alpha1.ClusterBpfApplication{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: testAppProgramName,
+		},
+		Spec: bpfmaniov1alpha1.ClBpfApplicationSpec{
+			BpfAppCommon: bpfmaniov1alpha1.BpfAppCommon{
+				NodeSelector: metav1.LabelSelector{},
+				ByteCode: bpfmaniov1alpha1.ByteCodeSelector{
+					Path: ptr.To(testBytecodePath),
+				},
+			},
+			Programs: []bpfmaniov1alpha1.ClBpfApplicationProgram{{
+				Name: testXdpBpfFunctionName,
+				Type: bpfmaniov1alpha1.ProgTypeXDP,
+				XDP: &bpfmaniov1alpha1.ClXdpProgramInfo{
+					Links: []bpfmaniov1alpha1.ClXdpAttachInfo{{
+						InterfaceSelector: bpfmaniov1alpha1.InterfaceSelector{Interfaces: []string{fakeInt0}},
+						AttachMode:        bpfmaniov1alpha1.XdpAttachModeNative,
+					}},
+				},
+			}},
+		},
+	}
+
+	r := createFakeClusterReconciler([]runtime.Object{fakeNode, bpfApp}, bpfApp, fakeNode)
+	cli := r.BpfmanClient.(*agenttestutils.BpfmanClientFake)
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: testAppProgramName}}
+	getLink := func() bpfmaniov1alpha1.ClXdpAttachInfoState {
+		bpfAppState, err := r.getBpfAppState(ctx)
+		require.NoError(t, err)
+		require.Len(t, bpfAppState.Status.Programs[0].XDP.Links, 1)
+		return bpfAppState.Status.Programs[0].XDP.Links[0]
+	}
+
+	// The program isn't attached in generic mode.
+	runReconciler(t, ctx, r, req, r.Logger)
+	runReconciler(t, ctx, r, req, r.Logger)
+	link := getLink()
+	require.Equal(t, bpfmaniov1alpha1.ApAttachModeError, link.LinkStatus)
+	require.Equal(t, bpfmaniov1alpha1.XdpAttachModeGeneric, link.ObtainedAttachMode)
+	require.Nil(t, link.LinkId)
+	require.Empty(t, cli.Links)
+
+	// Once bpfman runs the dispatcher in native mode, the program is attached.
+	xdpState.AttachMode = bpfmaniov1alpha1.XdpAttachModeNative
+	runReconciler(t, ctx, r, req, r.Logger)
+	link = getLink()
+	require.Equal(t, bpfmaniov1alpha1.ApAttachAttached, link.LinkStatus)
+	require.Equal(t, bpfmaniov1alpha1.XdpAttachModeNative, link.ObtainedAttachMode)
+	require.Len(t, cli.Links, 1)
+
+	// The program isn't frags aware, so it's detached when the MTU no longer
+	// fits in a single buffer.
+	xdpState.MTU = 9000
+	runReconciler(t, ctx, r, req, r.Logger)
+	link = getLink()
+	require.Equal(t, bpfmaniov1alpha1.ApAttachModeError, link.LinkStatus)
+	require.Nil(t, link.LinkId)
+	require.Empty(t, cli.Links)
+}
+
 // verifyClusterBpfProgramState checks that all BPF programs in the application state have
 // successfully attached and that the number of programs matches the expected count.
 func verifyClusterBpfProgramState(t *testing.T, bpfAppState *bpfmaniov1alpha1.ClusterBpfApplicationState,
@@ -498,3 +583,231 @@ func verifyClusterBpfProgramState(t *testing.T, bpfAppState *bpfmaniov1alpha1.Cl
 	}
 	require.Equal(t, len(programs), numMatches)
 }
+
+func TestClBpfApplicationControllerVerifierRejected(t *testing.T) {
+	var (
+		fakeNode = testutils.NewNode("fake-control-plane")
+		ctx      = context.TODO()
+	)
+
+	programs := []bpfmaniov1alpha1.ClBpfApplicationProgram{
+		{
+			Name: testFentryBpfFunctionName,
+			Type: bpfmaniov1alpha1.ProgTypeFentry,
+			FEntry: &bpfmaniov1alpha1.ClFentryProgramInfo{
+				ClFentryLoadInfo: bpfmaniov1alpha1.ClFentryLoadInfo{
+					Function: testAttachName,
+				},
+				Links: []bpfmaniov1alpha1.ClFentryAttachInfo{{}},
+			},
+		},
+	}
+	bpfApp := &bpfmaniov1alpha1.ClusterBpfApplication{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: testAppProgramName,
+		},
+		Spec: bpfmaniov1alpha1.ClBpfApplicationSpec{
+			BpfAppCommon: bpfmaniov1alpha1.BpfAppCommon{
+				NodeSelector: metav1.LabelSelector{},
+				ByteCode: bpfmaniov1alpha1.ByteCodeSelector{
+					Path: ptr.To(testBytecodePath),
+				},
+			},
+			Programs: programs,
+		},
+	}
+
+	r := createFakeClusterReconciler([]runtime.Object{fakeNode, bpfApp}, bpfApp, fakeNode)
+	r.Namespace = testNamespace
+	cli := r.BpfmanClient.(*agenttestutils.BpfmanClientFake)
+
+	verifierLog := strings.Repeat("0: (b7) r0 = 0\n", 400) + "R1 invalid mem access 'scalar'"
+	cli.LoadErr = status.Error(codes.Aborted,
+		"An error occurred. the BPF_PROG_LOAD syscall failed. Verifier output: "+verifierLog)
+
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: testAppProgramName}}
+
+	// The first reconcile creates the state, the second one fails to load.
+	runReconciler(t, ctx, r, req, r.Logger)
+	runReconciler(t, ctx, r, req, r.Logger)
+
+	bpfAppState, err := r.getBpfAppState(ctx)
+	require.NoError(t, err)
+	verifyBpfApplicationState(t, bpfAppState, fakeNode, testAppProgramName, bpfmaniov1alpha1.BpfAppStateCondVerifierRejected)
+	require.True(t, helpers.IsBpfAppStateConditionFailure(bpfAppState.Status.Conditions))
+
+	program := bpfAppState.Status.Programs[0]
+	require.Nil(t, program.ProgramId)
+	require.LessOrEqual(t, len(program.VerifierLog), verifierLogStatusLimit)
+	require.True(t, strings.HasSuffix(program.VerifierLog, "R1 invalid mem access 'scalar'"))
+
+	// The full log doesn't fit in the status and is stored in a ConfigMap.
+	require.NotNil(t, program.VerifierLogRef)
+	cm := &v1.ConfigMap{}
+	require.NoError(t, r.Get(ctx, types.NamespacedName{
+		Namespace: program.VerifierLogRef.Namespace,
+		Name:      program.VerifierLogRef.Name,
+	}, cm))
+	require.Equal(t, testNamespace, cm.Namespace)
+	require.Equal(t, verifierLog, cm.Data[program.VerifierLogRef.Key])
+
+	// Once the program loads the verifier log is cleared.
+	cli.LoadErr = nil
+	runReconciler(t, ctx, r, req, r.Logger)
+
+	bpfAppState, err = r.getBpfAppState(ctx)
+	require.NoError(t, err)
+	verifyBpfApplicationState(t, bpfAppState, fakeNode, testAppProgramName, bpfmaniov1alpha1.BpfAppStateCondSuccess)
+	require.Empty(t, bpfAppState.Status.Programs[0].VerifierLog)
+	require.Nil(t, bpfAppState.Status.Programs[0].VerifierLogRef)
+	err = r.Get(ctx, types.NamespacedName{Namespace: cm.Namespace, Name: cm.Name}, &v1.ConfigMap{})
+	require.True(t, apierrors.IsNotFound(err))
+}
+
+func TestClBpfApplicationControllerAdopt(t *testing.T) {
+	var (
+		fakeNode  = testutils.NewNode("fake-control-plane")
+		ctx       = context.TODO()
+		adoptUUID = "c9a8f6ad-7d51-4e0e-9a4a-3a4c1c3f2f6e"
+	)
+
+	programs := []bpfmaniov1alpha1.ClBpfApplicationProgram{
+		{
+			Name: testFentryBpfFunctionName,
+			Type: bpfmaniov1alpha1.ProgTypeFentry,
+			FEntry: &bpfmaniov1alpha1.ClFentryProgramInfo{
+				ClFentryLoadInfo: bpfmaniov1alpha1.ClFentryLoadInfo{
+					Function: testAttachName,
+				},
+				Links: []bpfmaniov1alpha1.ClFentryAttachInfo{{}},
+			},
+		},
+	}
+	bpfApp := &bpfmaniov1alpha1.ClusterBpfApplication{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: testAppProgramName,
+			// Set by the operator.
+			Finalizers: []string{internal.BpfmanOperatorFinalizer},
+		},
+		Spec: bpfmaniov1alpha1.ClBpfApplicationSpec{
+			BpfAppCommon: bpfmaniov1alpha1.BpfAppCommon{
+				NodeSelector: metav1.LabelSelector{},
+				ByteCode: bpfmaniov1alpha1.ByteCodeSelector{
+					Path: ptr.To(testBytecodePath),
+				},
+			},
+			Programs:  programs,
+			AdoptUUID: adoptUUID,
+		},
+	}
+
+	r := createFakeClusterReconciler([]runtime.Object{fakeNode, bpfApp}, bpfApp, fakeNode)
+	cli := r.BpfmanClient.(*agenttestutils.BpfmanClientFake)
+
+	// The program was loaded through bpfman by another tool.
+	cli.Programs[42] = &gobpfman.GetResponse{
+		Info: &gobpfman.ProgramInfo{
+			Name:     testFentryBpfFunctionName,
+			Metadata: map[string]string{internal.UuidMetadataKey: adoptUUID},
+		},
+		KernelInfo: &gobpfman.KernelProgramInfo{Id: 42, Name: testFentryBpfFunctionName},
+	}
+
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: testAppProgramName}}
+	runReconciler(t, ctx, r, req, r.Logger)
+	runReconciler(t, ctx, r, req, r.Logger)
+
+	bpfAppState, err := r.getBpfAppState(ctx)
+	require.NoError(t, err)
+	verifyBpfApplicationState(t, bpfAppState, fakeNode, testAppProgramName, bpfmaniov1alpha1.BpfAppStateCondSuccess)
+	require.Equal(t, ptr.To(uint32(42)), bpfAppState.Status.Programs[0].ProgramId)
+	require.Empty(t, cli.LoadRequests)
+	require.Len(t, cli.Programs[42].Info.Links, 1)
+
+	// The adopted programs are unloaded with the application.
+	require.NoError(t, r.Delete(ctx, bpfApp))
+	runReconciler(t, ctx, r, req, r.Logger)
+	require.Contains(t, cli.UnloadRequests, 42)
+}
+
+func TestClBpfApplicationControllerAdoptManaged(t *testing.T) {
+	var (
+		fakeNode = testutils.NewNode("fake-control-plane")
+		ctx      = context.TODO()
+		stateUID = "b1f1f1c4-2b4e-4a57-8f0e-5b2c2b8a6d10"
+	)
+
+	bpfApp := &bpfmaniov1alpha1.ClusterBpfApplication{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       testAppProgramName,
+			Finalizers: []string{internal.BpfmanOperatorFinalizer},
+		},
+		Spec: bpfmaniov1alpha1.ClBpfApplicationSpec{
+			BpfAppCommon: bpfmaniov1alpha1.BpfAppCommon{
+				NodeSelector: metav1.LabelSelector{},
+				ByteCode: bpfmaniov1alpha1.ByteCodeSelector{
+					Path: ptr.To(testBytecodePath),
+				},
+			},
+			Programs: []bpfmaniov1alpha1.ClBpfApplicationProgram{{
+				Name: testFentryBpfFunctionName,
+				Type: bpfmaniov1alpha1.ProgTypeFentry,
+				FEntry: &bpfmaniov1alpha1.ClFentryProgramInfo{
+					ClFentryLoadInfo: bpfmaniov1alpha1.ClFentryLoadInfo{
+						Function: testAttachName,
+					},
+					Links: []bpfmaniov1alpha1.ClFentryAttachInfo{{}},
+				},
+			}},
+			// The UUID of the programs of another application.
+			AdoptUUID: stateUID,
+		},
+	}
+	otherState := &bpfmaniov1alpha1.BpfApplicationState{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: testNamespace,
+			Name:      "other-app-state",
+			UID:       types.UID(stateUID),
+			Labels:    map[string]string{internal.K8sHostLabel: fakeNode.Name},
+		},
+	}
+
+	r := createFakeClusterReconciler([]runtime.Object{fakeNode, bpfApp, otherState}, bpfApp, fakeNode)
+	cli := r.BpfmanClient.(*agenttestutils.BpfmanClientFake)
+	cli.Programs[42] = &gobpfman.GetResponse{
+		Info: &gobpfman.ProgramInfo{
+			Name:     testFentryBpfFunctionName,
+			Metadata: map[string]string{internal.UuidMetadataKey: stateUID},
+		},
+		KernelInfo: &gobpfman.KernelProgramInfo{Id: 42, Name: testFentryBpfFunctionName},
+	}
+
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: testAppProgramName}}
+	runReconciler(t, ctx, r, req, r.Logger)
+	runReconciler(t, ctx, r, req, r.Logger)
+
+	// The programs of the other application are neither adopted nor loaded
+	// again.
+	bpfAppState, err := r.getBpfAppState(ctx)
+	require.NoError(t, err)
+	verifyBpfApplicationState(t, bpfAppState, fakeNode, testAppProgramName, bpfmaniov1alpha1.BpfAppStateCondError)
+	require.Nil(t, bpfAppState.Status.Programs[0].ProgramId)
+	require.Empty(t, cli.LoadRequests)
+	require.Empty(t, cli.Programs[42].Info.Links)
+}
+
+// TestAdoptUUIDClusterScoped checks that only cluster scoped applications,
+// which only cluster administrators create, can adopt programs.
+func TestAdoptUUIDClusterScoped(t *testing.T) {
+	specProperties := func(file string) map[string]apiextensionsv1.JSONSchemaProps {
+		data, err := os.ReadFile(filepath.Join("..", "..", "config", "crd", "bases", file))
+		require.NoError(t, err)
+		crd := &apiextensionsv1.CustomResourceDefinition{}
+		require.NoError(t, yaml.Unmarshal(data, crd))
+		return crd.Spec.Versions[0].Schema.OpenAPIV3Schema.Properties["spec"].Properties
+	}
+
+	require.Contains(t, specProperties("bpfman.io_clusterbpfapplications.yaml"), "adoptUUID")
+	// The API server prunes the field from BpfApplications.
+	require.NotContains(t, specProperties("bpfman.io_bpfapplications.yaml"), "adoptUUID")
+}
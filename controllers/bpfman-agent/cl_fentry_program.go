@@ -95,7 +95,7 @@ func (r *ClFentryProgramReconciler) getAttachRequest() *gobpfman.AttachRequest {
 		Attach: &gobpfman.AttachInfo{
 			Info: &gobpfman.AttachInfo_FentryAttachInfo{
 				FentryAttachInfo: &gobpfman.FentryAttachInfo{
-					Metadata: map[string]string{internal.UuidMetadataKey: string(r.currentLink.UUID)},
+					Metadata: r.attachMetadata(r.currentLink.UUID, nil),
 				},
 			},
 		},
@@ -95,7 +95,7 @@ func (r *ClFexitProgramReconciler) getAttachRequest() *gobpfman.AttachRequest {
 		Attach: &gobpfman.AttachInfo{
 			Info: &gobpfman.AttachInfo_FexitAttachInfo{
 				FexitAttachInfo: &gobpfman.FexitAttachInfo{
-					Metadata: map[string]string{internal.UuidMetadataKey: string(r.currentLink.UUID)},
+					Metadata: r.attachMetadata(r.currentLink.UUID, nil),
 				},
 			},
 		},
@@ -96,7 +96,7 @@ func (r *ClKprobeProgramReconciler) getAttachRequest() *gobpfman.AttachRequest {
 				KprobeAttachInfo: &gobpfman.KprobeAttachInfo{
 					FnName:   r.currentLink.Function,
 					Offset:   uint64(r.currentLink.Offset),
-					Metadata: map[string]string{internal.UuidMetadataKey: string(r.currentLink.UUID)},
+					Metadata: r.attachMetadata(r.currentLink.UUID, nil),
 				},
 			},
 		},
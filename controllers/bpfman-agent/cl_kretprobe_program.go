@@ -95,7 +95,7 @@ func (r *ClKretprobeProgramReconciler) getAttachRequest() *gobpfman.AttachReques
 			Info: &gobpfman.AttachInfo_KprobeAttachInfo{
 				KprobeAttachInfo: &gobpfman.KprobeAttachInfo{
 					FnName:   r.currentLink.Function,
-					Metadata: map[string]string{internal.UuidMetadataKey: string(r.currentLink.UUID)},
+					Metadata: r.attachMetadata(r.currentLink.UUID, nil),
 				},
 			},
 		},
@@ -23,7 +23,6 @@ import (
 
 	bpfmaniov1alpha1 "github.com/bpfman/bpfman-operator/apis/v1alpha1"
 	internal "github.com/bpfman/bpfman-operator/internal"
-	"github.com/bpfman/bpfman-operator/pkg/helpers"
 	gobpfman "github.com/bpfman/bpfman/clients/gobpfman/v1"
 	"github.com/google/uuid"
 )
@@ -101,7 +100,7 @@ func (r *ClTcxProgramReconciler) getAttachRequest() *gobpfman.AttachRequest {
 		Priority:  r.currentLink.Priority,
 		Iface:     r.currentLink.InterfaceName,
 		Direction: directionToStr(r.currentLink.Direction),
-		Metadata:  map[string]string{internal.UuidMetadataKey: string(r.currentLink.UUID)},
+		Metadata:  r.attachMetadata(r.currentLink.UUID, r.currentLink.Pod),
 		Netns:     netnsPath,
 	}
 
@@ -146,8 +145,10 @@ func (r *ClTcxProgramReconciler) updateLinks(ctx context.Context, isBeingDeleted
 					continue
 				}
 				if index != nil {
-					// Link already exists, so set ShouldAttach to true.
+					// Link already exists, so set ShouldAttach to true and
+					// record its pod.
 					r.currentProgramState.TCX.Links[*index].AttachInfoStateCommon.ShouldAttach = true
+					r.currentProgramState.TCX.Links[*index].Pod = link.Pod
 				} else {
 					// Link doesn't exist, so add it.
 					r.Logger.Info("Link doesn't exist.  Adding it.")
@@ -185,11 +186,13 @@ func (r *ClTcxProgramReconciler) findLink(attachInfoState bpfmaniov1alpha1.ClTcx
 	r.Logger.V(1).Info("findlink", "New Path", attachInfoState.NetnsPath, "NetnsId", newNetnsId)
 	for i, a := range r.currentProgramState.TCX.Links {
 		// attachInfoState is the same as a if the the following fields are the
-		// same: InterfaceName, Direction, Priority, and network namespace.
+		// same: InterfaceName, Direction, Priority, network namespace, and pod
+		// sandbox.
 		if a.InterfaceName == attachInfoState.InterfaceName &&
 			a.Direction == attachInfoState.Direction &&
 			a.Priority == attachInfoState.Priority &&
-			reflect.DeepEqual(r.NetNsCache.GetNetNsId(a.NetnsPath), newNetnsId) {
+			reflect.DeepEqual(r.NetNsCache.GetNetNsId(a.NetnsPath), newNetnsId) &&
+			samePodSandbox(a.Pod, attachInfoState.Pod) {
 			return &i, nil
 		}
 	}
@@ -259,9 +262,14 @@ func (r *ClTcxProgramReconciler) removeLinks(links []bpfmaniov1alpha1.ClTcxAttac
 // getExpectedLinks expands *AttachInfo into a list of specific attach
 // points.
 func (r *ClTcxProgramReconciler) getExpectedLinks(ctx context.Context, attachInfo bpfmaniov1alpha1.ClTcxAttachInfo) ([]bpfmaniov1alpha1.ClTcxAttachInfoState, error) {
+	priority, err := r.getLinkPriority(ctx, "", attachInfo.PriorityClassName, attachInfo.Priority)
+	if err != nil {
+		return nil, err
+	}
+
 	nodeLinks := []bpfmaniov1alpha1.ClTcxAttachInfoState{}
 	// Helper function to create a ClTcxAttachInfoState entry
-	createLinkEntry := func(interfaceName, netnsPath string) bpfmaniov1alpha1.ClTcxAttachInfoState {
+	createLinkEntry := func(interfaceName, netnsPath string, pod *bpfmaniov1alpha1.AttachPodState) bpfmaniov1alpha1.ClTcxAttachInfoState {
 		return bpfmaniov1alpha1.ClTcxAttachInfoState{
 			AttachInfoStateCommon: bpfmaniov1alpha1.AttachInfoStateCommon{
 				ShouldAttach: true,
@@ -271,56 +279,61 @@ func (r *ClTcxProgramReconciler) getExpectedLinks(ctx context.Context, attachInf
 			},
 			InterfaceName: interfaceName,
 			NetnsPath:     netnsPath,
-			Priority:      helpers.GetPriority(attachInfo.Priority),
+			Pod:           pod,
+			Priority:      priority,
 			Direction:     attachInfo.Direction,
 		}
 	}
 
-	// Handle interface discovery
-	if isInterfacesDiscoveryEnabled(&attachInfo.InterfaceSelector) {
+	// Handle interface discovery on the node. With network namespaces, the
+	// interfaces are discovered in the network namespace of each pod instead.
+	discovery := isInterfacesDiscoveryEnabled(&attachInfo.InterfaceSelector)
+	if discovery && attachInfo.NetworkNamespaces == nil {
 		discoveredInterfaces := getDiscoveredInterfaces(&attachInfo.InterfaceSelector, r.Interfaces)
 
 		r.Logger.Info("getExpectedLinks", "num discoveredInterfaces", len(discoveredInterfaces))
 		for _, intf := range discoveredInterfaces {
-			nodeLinks = append(nodeLinks, createLinkEntry(intf.interfaceName, intf.netNSPath))
+			nodeLinks = append(nodeLinks, createLinkEntry(intf.interfaceName, intf.netNSPath, nil))
 		}
 		r.Logger.V(1).Info("getExpectedLinks-discovery", "Links created", len(nodeLinks))
 		return nodeLinks, nil
 	}
 
-	// Fetch interfaces if discovery is disabled
-	interfaces, err := getInterfaces(&attachInfo.InterfaceSelector, r.ourNode)
-	if err != nil {
-		r.Logger.V(1).Info("getExpectedLinks failed to get interfaces", "error", err)
-		return nil, fmt.Errorf("failed to get interfaces for XdpProgram: %w", err)
+	// Fetch interfaces if discovery is disabled. With a network attachment,
+	// the interfaces come from the attachment in each pod instead.
+	var interfaces []string
+	if !discovery && (attachInfo.NetworkNamespaces == nil || attachInfo.NetworkNamespaces.NetworkAttachment == nil) {
+		interfaces, err = getInterfaces(&attachInfo.InterfaceSelector, r.ourNode)
+		if err != nil {
+			r.Logger.V(1).Info("getExpectedLinks failed to get interfaces", "error", err)
+			return nil, fmt.Errorf("failed to get interfaces for XdpProgram: %w", err)
+		}
 	}
 
 	r.Logger.Info("getExpectedLinks", "Number of interfaces", len(interfaces))
 
 	// Handle network namespaces if provided
 	if attachInfo.NetworkNamespaces != nil {
-		containerInfo, err := r.Containers.GetContainers(
+		pods, err := r.Containers.GetPodNetworkNamespaces(
 			ctx,
 			attachInfo.NetworkNamespaces.Namespace,
 			attachInfo.NetworkNamespaces.Pods,
-			nil,
 			r.Logger,
 		)
 		if err != nil {
-			r.Logger.V(1).Info("getExpectedLinks failed to get container pids", "error", err)
-			return nil, fmt.Errorf("failed to get container pids: %w", err)
+			r.Logger.V(1).Info("getExpectedLinks failed to get pod network namespaces", "error", err)
+			return nil, fmt.Errorf("failed to get pod network namespaces: %w", err)
 		}
 
-		if containerInfo == nil {
-			r.Logger.Info("NetworkNamespaces is configured but no matching container found")
-			return nodeLinks, nil
+		selectedPods := selectPodNetns(pods, attachInfo.NetworkNamespaces.HostNetwork)
+		podsInterfaces, err := r.getPodsInterfaces(&attachInfo.InterfaceSelector,
+			attachInfo.NetworkNamespaces.NetworkAttachment, interfaces, selectedPods)
+		if err != nil {
+			return nil, err
 		}
-
-		containerInfo = GetOneContainerPerPod(containerInfo)
-		for _, container := range *containerInfo {
-			netnsPath := netnsPathFromPID(container.pid)
-			for _, iface := range interfaces {
-				nodeLinks = append(nodeLinks, createLinkEntry(iface, netnsPath))
+		for i, pod := range selectedPods {
+			for _, iface := range podsInterfaces[i] {
+				nodeLinks = append(nodeLinks, createLinkEntry(iface, pod.netnsPath, pod.podState()))
 			}
 		}
 		r.Logger.V(1).Info("getExpectedLinks", "Links created", len(nodeLinks))
@@ -329,7 +342,7 @@ func (r *ClTcxProgramReconciler) getExpectedLinks(ctx context.Context, attachInf
 
 	// Fallback: Assign interfaces without a namespace
 	for _, iface := range interfaces {
-		nodeLinks = append(nodeLinks, createLinkEntry(iface, ""))
+		nodeLinks = append(nodeLinks, createLinkEntry(iface, "", nil))
 	}
 
 	r.Logger.V(1).Info("getExpectedLinks", "Links created", len(nodeLinks))
@@ -95,7 +95,7 @@ func (r *ClTracepointProgramReconciler) getAttachRequest() *gobpfman.AttachReque
 			Info: &gobpfman.AttachInfo_TracepointAttachInfo{
 				TracepointAttachInfo: &gobpfman.TracepointAttachInfo{
 					Tracepoint: r.currentLink.Name,
-					Metadata:   map[string]string{internal.UuidMetadataKey: string(r.currentLink.UUID)},
+					Metadata:   r.attachMetadata(r.currentLink.UUID, nil),
 				},
 			},
 		},
@@ -96,7 +96,7 @@ func (r *ClUprobeProgramReconciler) getAttachRequest() *gobpfman.AttachRequest {
 		Offset:   uint64(r.currentLink.Offset),
 		Target:   r.currentLink.Target,
 		Pid:      r.currentLink.Pid,
-		Metadata: map[string]string{internal.UuidMetadataKey: string(r.currentLink.UUID)},
+		Metadata: r.attachMetadata(r.currentLink.UUID, r.currentLink.Pod),
 	}
 
 	if r.currentLink.ContainerPid != nil {
@@ -142,8 +142,10 @@ func (r *ClUprobeProgramReconciler) updateLinks(ctx context.Context, isBeingDele
 		for _, link := range expectedLinks {
 			index := r.findLink(link, appStateLinks)
 			if index != nil {
-				// Link already exists, so set ShouldAttach to true.
+				// Link already exists, so set ShouldAttach to true and record
+				// its pod.
 				(*appStateLinks)[*index].AttachInfoStateCommon.ShouldAttach = true
+				(*appStateLinks)[*index].Pod = link.Pod
 			} else {
 				// Link doesn't exist, so add it.
 				r.Logger.Info("Link doesn't exist.  Adding it.")
@@ -164,11 +166,12 @@ func (r *ClUprobeProgramReconciler) findLink(attachInfoState bpfmaniov1alpha1.Cl
 	links *[]bpfmaniov1alpha1.ClUprobeAttachInfoState) *int {
 	for i, a := range *links {
 		// attachInfoState is the same as a if the the following fields are the
-		// same: Function, Offset, Target, Pid, and ContainerPid.
+		// same: Function, Offset, Target, Pid, ContainerPid, and pod sandbox.
 		if a.Function == attachInfoState.Function && a.Offset == attachInfoState.Offset &&
 			a.Target == attachInfoState.Target &&
 			reflect.DeepEqual(a.Pid, attachInfoState.Pid) &&
-			reflect.DeepEqual(a.ContainerPid, attachInfoState.ContainerPid) {
+			reflect.DeepEqual(a.ContainerPid, attachInfoState.ContainerPid) &&
+			samePodSandbox(a.Pod, attachInfoState.Pod) {
 			return &i
 		}
 	}
@@ -305,6 +308,7 @@ func (r *ClUprobeProgramReconciler) getExpectedLinks(ctx context.Context, attach
 					Target:       attachInfo.Target,
 					Pid:          attachInfo.Pid,
 					ContainerPid: &containerPid,
+					Pod:          container.podState(),
 				}
 				nodeLinks = append(nodeLinks, link)
 			}
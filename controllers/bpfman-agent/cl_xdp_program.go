@@ -23,11 +23,15 @@ import (
 
 	bpfmaniov1alpha1 "github.com/bpfman/bpfman-operator/apis/v1alpha1"
 	internal "github.com/bpfman/bpfman-operator/internal"
-	"github.com/bpfman/bpfman-operator/pkg/helpers"
 	gobpfman "github.com/bpfman/bpfman/clients/gobpfman/v1"
 	"github.com/google/uuid"
 )
 
+// xdpMaxSingleBufferMTU is the largest MTU whose frames fit in the single
+// page buffer that XDP programs which are not frags aware require in native
+// and offload modes.
+const xdpMaxSingleBufferMTU = 3498
+
 // ClXdpProgramReconciler contains the info required to reconcile an XdpProgram
 type ClXdpProgramReconciler struct {
 	ReconcilerCommon
@@ -90,6 +94,49 @@ func (r *ClXdpProgramReconciler) getCurrentLinkStatus() bpfmaniov1alpha1.LinkSta
 	return r.currentLink.LinkStatus
 }
 
+// checkAttachMode records the mode of the XDP programs on the interface of the
+// current link and returns an error if it isn't the requested one. The
+// interface has no mode before its first XDP program is attached, in which
+// case the link can only be checked once attached.
+func (r *ClXdpProgramReconciler) checkAttachMode() error {
+	link := r.currentLink
+	state, err := getXdpLinkState(link.NetnsPath, link.InterfaceName)
+	if err != nil {
+		if link.AttachMode == "" {
+			// The link accepts any mode, so it doesn't depend on the state.
+			r.Logger.Info("Failed to get the XDP mode of the interface", "interface", link.InterfaceName, "error", err)
+			return nil
+		}
+		return fmt.Errorf("failed to get the XDP mode of interface %s: %w", link.InterfaceName, err)
+	}
+	if state.AttachMode == "" {
+		return nil
+	}
+	link.ObtainedAttachMode = state.AttachMode
+	if !xdpAttachModeObtained(link.AttachMode, state.AttachMode) {
+		return fmt.Errorf("XDP programs run in %s mode on interface %s instead of %s, set the xdp_mode of the interface in the bpfman configuration",
+			state.AttachMode, link.InterfaceName, link.AttachMode)
+	}
+	if !link.FragsAware && state.AttachMode != bpfmaniov1alpha1.XdpAttachModeGeneric && state.MTU > xdpMaxSingleBufferMTU {
+		return fmt.Errorf("the MTU %d of interface %s requires a frags aware XDP program in %s mode",
+			state.MTU, link.InterfaceName, state.AttachMode)
+	}
+	return nil
+}
+
+// xdpAttachModeObtained returns true if the XDP programs of an interface
+// running in mode obtained satisfy the requested mode.
+func xdpAttachModeObtained(requested, obtained bpfmaniov1alpha1.XdpAttachMode) bool {
+	switch requested {
+	case "":
+		return true
+	case bpfmaniov1alpha1.XdpAttachModeOffloadIfSupported:
+		return obtained == bpfmaniov1alpha1.XdpAttachModeOffload || obtained == bpfmaniov1alpha1.XdpAttachModeNative
+	default:
+		return obtained == requested
+	}
+}
+
 // Must match with bpfman internal types
 func xdpProceedOnToInt(proceedOn []bpfmaniov1alpha1.XdpProceedOnValue) []int32 {
 	var out []int32
@@ -125,7 +172,7 @@ func (r *ClXdpProgramReconciler) getAttachRequest() *gobpfman.AttachRequest {
 		Priority:  r.currentLink.Priority,
 		Iface:     r.currentLink.InterfaceName,
 		ProceedOn: xdpProceedOnToInt(r.currentLink.ProceedOn),
-		Metadata:  map[string]string{internal.UuidMetadataKey: string(r.currentLink.UUID)},
+		Metadata:  r.attachMetadata(r.currentLink.UUID, r.currentLink.Pod),
 		Netns:     netnsPath,
 	}
 
@@ -170,8 +217,10 @@ func (r *ClXdpProgramReconciler) updateLinks(ctx context.Context, isBeingDeleted
 					continue
 				}
 				if index != nil {
-					// Link already exists, so set ShouldAttach to true.
+					// Link already exists, so set ShouldAttach to true and
+					// record its pod.
 					r.currentProgramState.XDP.Links[*index].AttachInfoStateCommon.ShouldAttach = true
+					r.currentProgramState.XDP.Links[*index].Pod = link.Pod
 				} else {
 					// Link doesn't exist, so add it.
 					r.Logger.Info("Link doesn't exist.  Adding it.")
@@ -209,11 +258,15 @@ func (r *ClXdpProgramReconciler) findLink(attachInfoState bpfmaniov1alpha1.ClXdp
 	r.Logger.V(1).Info("findlink", "New Path", attachInfoState.NetnsPath, "NetnsId", newNetnsId)
 	for i, a := range r.currentProgramState.XDP.Links {
 		// attachInfoState is the same as a if the the following fields are the
-		// same: InterfaceName, Priority, ProceedOn, and network namespace.
+		// same: InterfaceName, Priority, ProceedOn, AttachMode, FragsAware,
+		// network namespace, and pod sandbox.
 		if a.InterfaceName == attachInfoState.InterfaceName &&
 			a.Priority == attachInfoState.Priority &&
 			reflect.DeepEqual(a.ProceedOn, attachInfoState.ProceedOn) &&
-			reflect.DeepEqual(r.NetNsCache.GetNetNsId(a.NetnsPath), newNetnsId) {
+			a.AttachMode == attachInfoState.AttachMode &&
+			a.FragsAware == attachInfoState.FragsAware &&
+			reflect.DeepEqual(r.NetNsCache.GetNetNsId(a.NetnsPath), newNetnsId) &&
+			samePodSandbox(a.Pod, attachInfoState.Pod) {
 			return &i, nil
 		}
 	}
@@ -283,9 +336,14 @@ func (r *ClXdpProgramReconciler) removeLinks(links []bpfmaniov1alpha1.ClXdpAttac
 // getExpectedLinks expands *AttachInfo into a list of specific attach
 // points.
 func (r *ClXdpProgramReconciler) getExpectedLinks(ctx context.Context, attachInfo bpfmaniov1alpha1.ClXdpAttachInfo) ([]bpfmaniov1alpha1.ClXdpAttachInfoState, error) {
+	priority, err := r.getLinkPriority(ctx, "", attachInfo.PriorityClassName, attachInfo.Priority)
+	if err != nil {
+		return nil, err
+	}
+
 	nodeLinks := []bpfmaniov1alpha1.ClXdpAttachInfoState{}
 	// Helper function to create a ClXdpAttachInfoState entry
-	createLinkEntry := func(interfaceName, netnsPath string) bpfmaniov1alpha1.ClXdpAttachInfoState {
+	createLinkEntry := func(interfaceName, netnsPath string, pod *bpfmaniov1alpha1.AttachPodState) bpfmaniov1alpha1.ClXdpAttachInfoState {
 		return bpfmaniov1alpha1.ClXdpAttachInfoState{
 			AttachInfoStateCommon: bpfmaniov1alpha1.AttachInfoStateCommon{
 				ShouldAttach: true,
@@ -295,55 +353,62 @@ func (r *ClXdpProgramReconciler) getExpectedLinks(ctx context.Context, attachInf
 			},
 			InterfaceName: interfaceName,
 			NetnsPath:     netnsPath,
-			Priority:      helpers.GetPriority(attachInfo.Priority),
+			Pod:           pod,
+			Priority:      priority,
 			ProceedOn:     attachInfo.ProceedOn,
+			AttachMode:    attachInfo.AttachMode,
+			FragsAware:    attachInfo.FragsAware,
 		}
 	}
 
-	// Handle interface discovery
-	if isInterfacesDiscoveryEnabled(&attachInfo.InterfaceSelector) {
+	// Handle interface discovery on the node. With network namespaces, the
+	// interfaces are discovered in the network namespace of each pod instead.
+	discovery := isInterfacesDiscoveryEnabled(&attachInfo.InterfaceSelector)
+	if discovery && attachInfo.NetworkNamespaces == nil {
 		discoveredInterfaces := getDiscoveredInterfaces(&attachInfo.InterfaceSelector, r.Interfaces)
 		r.Logger.Info("getExpectedLinks", "num discoveredInterfaces", len(discoveredInterfaces))
 		for _, intf := range discoveredInterfaces {
-			nodeLinks = append(nodeLinks, createLinkEntry(intf.interfaceName, intf.netNSPath))
+			nodeLinks = append(nodeLinks, createLinkEntry(intf.interfaceName, intf.netNSPath, nil))
 		}
 		r.Logger.V(1).Info("getExpectedLinks-discovery", "Links created", len(nodeLinks))
 		return nodeLinks, nil
 	}
 
-	// Fetch interfaces if discovery is disabled
-	interfaces, err := getInterfaces(&attachInfo.InterfaceSelector, r.ourNode)
-	if err != nil {
-		r.Logger.V(1).Info("getExpectedLinks failed to get interfaces", "error", err)
-		return nil, fmt.Errorf("failed to get interfaces for XdpProgram: %w", err)
+	// Fetch interfaces if discovery is disabled. With a network attachment,
+	// the interfaces come from the attachment in each pod instead.
+	var interfaces []string
+	if !discovery && (attachInfo.NetworkNamespaces == nil || attachInfo.NetworkNamespaces.NetworkAttachment == nil) {
+		interfaces, err = getInterfaces(&attachInfo.InterfaceSelector, r.ourNode)
+		if err != nil {
+			r.Logger.V(1).Info("getExpectedLinks failed to get interfaces", "error", err)
+			return nil, fmt.Errorf("failed to get interfaces for XdpProgram: %w", err)
+		}
 	}
 
 	r.Logger.Info("getExpectedLinks", "Number of interfaces", len(interfaces))
 
 	// Handle network namespaces if provided
 	if attachInfo.NetworkNamespaces != nil {
-		containerInfo, err := r.Containers.GetContainers(
+		pods, err := r.Containers.GetPodNetworkNamespaces(
 			ctx,
 			attachInfo.NetworkNamespaces.Namespace,
 			attachInfo.NetworkNamespaces.Pods,
-			nil,
 			r.Logger,
 		)
 		if err != nil {
-			r.Logger.V(1).Info("getExpectedLinks failed to get container pids", "error", err)
-			return nil, fmt.Errorf("failed to get container pids: %w", err)
+			r.Logger.V(1).Info("getExpectedLinks failed to get pod network namespaces", "error", err)
+			return nil, fmt.Errorf("failed to get pod network namespaces: %w", err)
 		}
 
-		if containerInfo == nil {
-			r.Logger.Info("NetworkNamespaces is configured but no matching container found")
-			return nodeLinks, nil
+		selectedPods := selectPodNetns(pods, attachInfo.NetworkNamespaces.HostNetwork)
+		podsInterfaces, err := r.getPodsInterfaces(&attachInfo.InterfaceSelector,
+			attachInfo.NetworkNamespaces.NetworkAttachment, interfaces, selectedPods)
+		if err != nil {
+			return nil, err
 		}
-
-		containerInfo = GetOneContainerPerPod(containerInfo)
-		for _, container := range *containerInfo {
-			netnsPath := netnsPathFromPID(container.pid)
-			for _, iface := range interfaces {
-				nodeLinks = append(nodeLinks, createLinkEntry(iface, netnsPath))
+		for i, pod := range selectedPods {
+			for _, iface := range podsInterfaces[i] {
+				nodeLinks = append(nodeLinks, createLinkEntry(iface, pod.netnsPath, pod.podState()))
 			}
 		}
 		r.Logger.V(1).Info("getExpectedLinks", "Links created", len(nodeLinks))
@@ -352,7 +417,7 @@ func (r *ClXdpProgramReconciler) getExpectedLinks(ctx context.Context, attachInf
 
 	// Fallback: Assign interfaces without a namespace
 	for _, iface := range interfaces {
-		nodeLinks = append(nodeLinks, createLinkEntry(iface, ""))
+		nodeLinks = append(nodeLinks, createLinkEntry(iface, "", nil))
 	}
 
 	r.Logger.V(1).Info("getExpectedLinks", "Links created", len(nodeLinks))
@@ -18,9 +18,11 @@ package bpfmanagent
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"regexp"
+	"slices"
 	"strings"
 	"sync"
 	"syscall"
@@ -30,8 +32,10 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
@@ -41,6 +45,7 @@ import (
 	bpfmaniov1alpha1 "github.com/bpfman/bpfman-operator/apis/v1alpha1"
 	bpfmanagentinternal "github.com/bpfman/bpfman-operator/controllers/bpfman-agent/internal"
 	"github.com/bpfman/bpfman-operator/internal"
+	"github.com/bpfman/bpfman-operator/pkg/helpers"
 	gobpfman "github.com/bpfman/bpfman/clients/gobpfman/v1"
 	"github.com/go-logr/logr"
 	"github.com/google/uuid"
@@ -52,6 +57,7 @@ import (
 // +kubebuilder:rbac:groups=bpfman.io,resources=clusterbpfapplicationstates/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=bpfman.io,resources=clusterbpfapplicationstates/finalizers,verbs=update
 // +kubebuilder:rbac:groups=bpfman.io,resources=clusterbpfapplications/finalizers,verbs=update
+// +kubebuilder:rbac:groups=bpfman.io,resources=bpfpriorityclasses,verbs=get;list;watch
 // +kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch
 // +kubebuilder:rbac:groups=core,resources=nodes,verbs=get;list;watch
 // +kubebuilder:rbac:groups=core,resources=secrets,verbs=get
@@ -75,6 +81,36 @@ type ReconcilerCommon struct {
 	ourNode      *v1.Node
 	Interfaces   *sync.Map
 	NetNsCache   NetNsCache
+	// AuditLog records every mutating bpfman call. It may be nil, in
+	// which case nothing is recorded.
+	AuditLog *bpfmanagentinternal.AuditLog
+	// Namespace is the namespace the agent runs in. Verifier logs of
+	// cluster scoped applications are stored there.
+	Namespace string
+	// Recorder records events on the application states, e.g., when a
+	// missing link is re-attached. It may be nil, in which case nothing is
+	// recorded.
+	Recorder record.EventRecorder
+}
+
+// AuditLogOptions configures the rotating audit log of bpfman calls.
+type AuditLogOptions = bpfmanagentinternal.AuditLogOptions
+
+// AuditLog records the mutating bpfman calls of the agent.
+type AuditLog = bpfmanagentinternal.AuditLog
+
+// NewAuditLog returns an audit log for the ReconcilerCommon.AuditLog field
+// that records the bpfman calls made on node.
+func NewAuditLog(node string, opts AuditLogOptions) *AuditLog {
+	return bpfmanagentinternal.NewAuditLog(node, opts)
+}
+
+// UnloadProgram unloads the program with the given kernel ID through bpfman
+// and records the call in auditLog, which may be nil. It is used outside of
+// the reconcilers, e.g., when the node is cleaned up on uninstall.
+func UnloadProgram(ctx context.Context, bpfmanClient gobpfman.BpfmanClient,
+	auditLog *AuditLog, id uint32) error {
+	return bpfmanagentinternal.UnloadBpfmanProgram(ctx, bpfmanClient, auditLog, id)
 }
 
 type NetNsCache interface {
@@ -176,6 +212,7 @@ type ProgramReconciler interface {
 	getCurrentLinkStatus() bpfmaniov1alpha1.LinkStatus
 	reconcileProgram(ctx context.Context, program ProgramReconciler, isBeingDeleted bool) error
 	getProgramLoadInfo() *gobpfman.LoadInfo
+	getAppState() client.Object
 }
 
 // Load or unload the programs as appropriate.
@@ -205,7 +242,7 @@ func (r *ReconcilerCommon) reconcileLoad(ctx context.Context, rec ApplicationRec
 			err := rec.load(ctx)
 			if err != nil {
 				rec.setAppLoadStatus(bpfmaniov1alpha1.AppLoadError)
-				return fmt.Errorf("failed to load program: %v", err)
+				return fmt.Errorf("failed to load program: %w", err)
 			} else {
 				rec.setAppLoadStatus(bpfmaniov1alpha1.AppLoadSuccess)
 			}
@@ -215,6 +252,62 @@ func (r *ReconcilerCommon) reconcileLoad(ctx context.Context, rec ApplicationRec
 	return nil
 }
 
+// adoptPrograms looks for the programs loaded through bpfman with the
+// given bpfman.io/uuid metadata and records their IDs in programs instead
+// of loading the bytecode again. It returns false when no program has the
+// UUID on this node. Otherwise, the loaded programs must match programs by
+// name. The programs of a BpfApplicationState of this node, whose UID is
+// their UUID, can't be adopted, so that an application can't take over the
+// programs of another one.
+func (r *ReconcilerCommon) adoptPrograms(ctx context.Context, uuid string,
+	programs []*bpfmaniov1alpha1.BpfProgramStateCommon) (bool, error) {
+	_, managedUIDs, err := managedPrograms(ctx, r.Client, r.NodeName)
+	if err != nil {
+		return false, err
+	}
+	if managedUIDs[uuid] {
+		return false, fmt.Errorf("programs with uuid %s belong to a BpfApplicationState and can't be adopted", uuid)
+	}
+
+	results, err := bpfmanagentinternal.ListBpfmanProgramsByUUID(ctx, r.BpfmanClient, uuid)
+	if err != nil {
+		return false, fmt.Errorf("failed to list programs with uuid %s: %w", uuid, err)
+	}
+	if len(results) == 0 {
+		return false, nil
+	}
+
+	ids := make(map[string]uint32, len(results))
+	for _, result := range results {
+		ids[result.GetInfo().GetName()] = result.GetKernelInfo().GetId()
+	}
+	if len(ids) != len(programs) {
+		return false, fmt.Errorf("%d programs with uuid %s are loaded, expected %d", len(ids), uuid, len(programs))
+	}
+	for _, program := range programs {
+		if _, ok := ids[program.Name]; !ok {
+			return false, fmt.Errorf("program %s with uuid %s is not loaded", program.Name, uuid)
+		}
+	}
+
+	for _, program := range programs {
+		id := ids[program.Name]
+		program.ProgramId = &id
+		r.Logger.Info("Adopted program", "Program", program.Name, "ProgramId", id, "UUID", uuid)
+	}
+	return true, nil
+}
+
+// loadFailureCondition returns the BpfApplicationState condition reporting
+// the given reconcileLoad error.
+func loadFailureCondition(err error) bpfmaniov1alpha1.BpfApplicationStateConditionType {
+	var verr *bpfmanagentinternal.VerifierError
+	if errors.As(err, &verr) {
+		return bpfmaniov1alpha1.BpfAppStateCondVerifierRejected
+	}
+	return bpfmaniov1alpha1.BpfAppStateCondError
+}
+
 // updateBpfAppStateCondition updates the overall status of a BpfApplicationState object
 // maintained in the Conditions field if needed, returning true if the status
 // was changed, and false if the status was not changed.
@@ -294,11 +387,17 @@ func interfaceInExcludeList(interfaceSelector *bpfmaniov1alpha1.InterfaceSelecto
 }
 
 func setupAllowedInterfacesLists(interfaceSelector *bpfmaniov1alpha1.InterfaceSelector) ([]*regexp.Regexp, []string) {
+	return setupNameMatchers(interfaceSelector.InterfacesDiscoveryConfig.AllowedInterfaces)
+}
+
+// setupNameMatchers splits definitions into the /regexp/ entries, compiled,
+// and the exact names.
+func setupNameMatchers(definitions []string) ([]*regexp.Regexp, []string) {
 	var isRegexp = regexp.MustCompile("^/(.*)/$")
 	var allowedRegexpes []*regexp.Regexp
 	var allowedMatches []string
 
-	for _, definition := range interfaceSelector.InterfacesDiscoveryConfig.AllowedInterfaces {
+	for _, definition := range definitions {
 		definition = strings.Trim(definition, " ")
 		// the user defined a /regexp/ between slashes: compile and store it as regular expression
 		if sm := isRegexp.FindStringSubmatch(definition); len(sm) > 1 {
@@ -334,6 +433,68 @@ func interfaceInAllowedList(intf string, allowedRegexpes []*regexp.Regexp, allow
 	return false
 }
 
+// listLinkProperties lists the link properties of the interfaces of a network
+// namespace. It is replaced in tests.
+var listLinkProperties = bpfmanagentinternal.ListLinkProperties
+
+// getXdpLinkState gets the XDP state of an interface of a network namespace.
+// It is replaced in tests.
+var getXdpLinkState = bpfmanagentinternal.GetXdpLinkState
+
+// linkSelected returns true if the link properties p match every field set in
+// selector.
+func linkSelected(selector *bpfmaniov1alpha1.InterfaceLinkSelector, p *bpfmanagentinternal.LinkProperties) bool {
+	if len(selector.Drivers) > 0 && !slices.Contains(selector.Drivers, p.Driver) {
+		return false
+	}
+	if len(selector.Types) > 0 && !slices.Contains(selector.Types, p.Type) {
+		return false
+	}
+	if len(selector.MacOUIs) > 0 && !slices.ContainsFunc(selector.MacOUIs, func(oui string) bool {
+		return len(p.MAC) >= 3 && strings.EqualFold(p.MAC[:3].String(), oui)
+	}) {
+		return false
+	}
+	if selector.MinMTU != nil && p.MTU < int(*selector.MinMTU) {
+		return false
+	}
+	if selector.MaxMTU != nil && p.MTU > int(*selector.MaxMTU) {
+		return false
+	}
+	if selector.SriovRole != nil && p.SriovRole != *selector.SriovRole {
+		return false
+	}
+	if len(selector.AltNames) > 0 {
+		regexpes, matches := setupNameMatchers(selector.AltNames)
+		names := p.AltNames
+		if p.Alias != "" {
+			names = append(slices.Clone(names), p.Alias)
+		}
+		if !slices.ContainsFunc(names, func(name string) bool {
+			return interfaceInAllowedList(name, regexpes, matches)
+		}) {
+			return false
+		}
+	}
+	return true
+}
+
+// selectLinks returns the names of the interfaces of the network namespace
+// netnsPath, or of the host if it is empty, that selector selects.
+func selectLinks(selector *bpfmaniov1alpha1.InterfaceLinkSelector, netnsPath string) ([]string, error) {
+	props, err := listLinkProperties(netnsPath)
+	if err != nil {
+		return nil, err
+	}
+	var interfaces []string
+	for i := range props {
+		if linkSelected(selector, &props[i]) {
+			interfaces = append(interfaces, props[i].Name)
+		}
+	}
+	return interfaces, nil
+}
+
 type discoveredInterface struct {
 	interfaceName string
 	netNSPath     string
@@ -343,6 +504,9 @@ func getDiscoveredInterfaces(interfaceSelector *bpfmaniov1alpha1.InterfaceSelect
 	var discoveredInterfaces []discoveredInterface
 	var netNSPath string
 	allowedRegexpes, allowedMatches := setupAllowedInterfacesLists(interfaceSelector)
+	linkSelector := interfaceSelector.InterfacesDiscoveryConfig.LinkSelector
+	// The interfaces selected by linkSelector, by network namespace.
+	selectedLinks := make(map[string][]string)
 	seenInterface := make(map[discoveredInterface]bool)
 	discoveredInterfacesMap.Range(func(key, value any) bool {
 		if value.(bool) {
@@ -352,6 +516,18 @@ func getDiscoveredInterfaces(interfaceSelector *bpfmaniov1alpha1.InterfaceSelect
 				if intf.NSName != "" {
 					netNSPath = internal.NetNsPath + "/" + intf.NSName
 				}
+				if linkSelector != nil {
+					selected, ok := selectedLinks[netNSPath]
+					if !ok {
+						// Interfaces whose properties can't be read, e.g., because
+						// their namespace was just removed, are not selected.
+						selected, _ = selectLinks(linkSelector, netNSPath)
+						selectedLinks[netNSPath] = selected
+					}
+					if !slices.Contains(selected, intf.Name) {
+						return true
+					}
+				}
 				if _, ok := seenInterface[discoveredInterface{intf.Name, netNSPath}]; !ok {
 					discoveredInterfaces = append(discoveredInterfaces, discoveredInterface{
 						interfaceName: intf.Name,
@@ -373,6 +549,10 @@ func getInterfaces(interfaceSelector *bpfmaniov1alpha1.InterfaceSelector, ourNod
 		return interfaceSelector.Interfaces, nil
 	}
 
+	if interfaceSelector.LinkSelector != nil {
+		return selectLinks(interfaceSelector.LinkSelector, "")
+	}
+
 	if interfaceSelector.PrimaryNodeInterface != nil {
 		nodeIface, err := bpfmanagentinternal.GetPrimaryNodeInterface(ourNode)
 		if err != nil {
@@ -386,6 +566,83 @@ func getInterfaces(interfaceSelector *bpfmaniov1alpha1.InterfaceSelector, ourNod
 	return nil, fmt.Errorf("no interfaces selected")
 }
 
+// getNetnsInterfaces returns the interfaces to attach in the network namespace
+// of pod, given the interfaces that getInterfaces returned. Only network
+// attachments and link selectors depend on the network namespace.
+func getNetnsInterfaces(interfaceSelector *bpfmaniov1alpha1.InterfaceSelector,
+	attachment *bpfmaniov1alpha1.NetworkAttachmentReference, interfaces []string,
+	pod *PodNetnsInfo) ([]string, error) {
+	if attachment != nil {
+		return networkAttachmentInterfaces(attachment, pod)
+	}
+	if interfaceSelector.LinkSelector == nil {
+		return interfaces, nil
+	}
+	return selectLinks(interfaceSelector.LinkSelector, pod.netnsPath)
+}
+
+// getPodsInterfaces returns the interfaces to attach in the network namespace
+// of each of pods, given the interfaces that getInterfaces returned. When
+// interface discovery is enabled, they are the interfaces discovered in the
+// network namespace of the pod instead, so that the interfaces added to a pod
+// after it started are attached too.
+func (r *ReconcilerCommon) getPodsInterfaces(interfaceSelector *bpfmaniov1alpha1.InterfaceSelector,
+	attachment *bpfmaniov1alpha1.NetworkAttachmentReference, interfaces []string,
+	pods []PodNetnsInfo) ([][]string, error) {
+	// Discovery names the network namespaces under /run/netns while pods are
+	// identified by the path of their sandbox, so they are matched by inode.
+	var discovered map[uint64][]string
+	if isInterfacesDiscoveryEnabled(interfaceSelector) {
+		discovered = make(map[uint64][]string)
+		for _, intf := range getDiscoveredInterfaces(interfaceSelector, r.Interfaces) {
+			if id := r.NetNsCache.GetNetNsId(intf.netNSPath); id != nil {
+				discovered[*id] = append(discovered[*id], intf.interfaceName)
+			}
+		}
+	}
+
+	podsInterfaces := make([][]string, len(pods))
+	for i := range pods {
+		pod := &pods[i]
+		var err error
+		if discovered != nil {
+			podsInterfaces[i], err = discoveredPodInterfaces(discovered, r.NetNsCache.GetNetNsId(pod.netnsPath),
+				attachment, pod)
+		} else {
+			podsInterfaces[i], err = getNetnsInterfaces(interfaceSelector, attachment, interfaces, pod)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get interfaces of pod %s/%s: %w", pod.podNamespace, pod.podName, err)
+		}
+	}
+	return podsInterfaces, nil
+}
+
+// discoveredPodInterfaces returns the interfaces discovered in the network
+// namespace netnsId of pod, restricted to the interfaces of attachment when it
+// is set.
+func discoveredPodInterfaces(discovered map[uint64][]string, netnsId *uint64,
+	attachment *bpfmaniov1alpha1.NetworkAttachmentReference, pod *PodNetnsInfo) ([]string, error) {
+	if netnsId == nil {
+		return nil, nil
+	}
+	interfaces := discovered[*netnsId]
+	if attachment == nil {
+		return interfaces, nil
+	}
+	attachmentInterfaces, err := networkAttachmentInterfaces(attachment, pod)
+	if err != nil {
+		return nil, err
+	}
+	var selected []string
+	for _, iface := range interfaces {
+		if slices.Contains(attachmentInterfaces, iface) {
+			selected = append(selected, iface)
+		}
+	}
+	return selected, nil
+}
+
 // Only return node updates for our node (all events)
 func nodePredicate(nodeName string) predicate.Funcs {
 	return predicate.Funcs{
@@ -417,8 +674,12 @@ func podOnNodePredicate(nodeName string) predicate.Funcs {
 			return ok && pod.Spec.NodeName == nodeName && pod.Status.Phase == v1.PodRunning
 		},
 		UpdateFunc: func(e event.UpdateEvent) bool {
+			// Pods that stop running are reconciled too so that their links
+			// are detached.
+			oldPod, _ := e.ObjectOld.(*v1.Pod)
 			pod, ok := e.ObjectNew.(*v1.Pod)
-			return ok && pod.Spec.NodeName == nodeName && pod.Status.Phase == v1.PodRunning
+			return ok && pod.Spec.NodeName == nodeName &&
+				(pod.Status.Phase == v1.PodRunning || (oldPod != nil && oldPod.Status.Phase != pod.Status.Phase))
 		},
 		DeleteFunc: func(e event.DeleteEvent) bool {
 			pod, ok := e.Object.(*v1.Pod)
@@ -556,23 +817,44 @@ func (r *ReconcilerCommon) reconcileBpfLink(ctx context.Context, rec ProgramReco
 		switch isAttached {
 		case true:
 			// The link is attached and it should be attached.
+			if err := checkAttachMode(rec); err != nil {
+				r.detachForAttachMode(ctx, rec, err)
+				break
+			}
 			// Link exists and bpfProgram K8s Object is up to date
 			r.Logger.V(1).Info("Program link is in correct state.  Nothing to do in bpfman")
 			rec.setCurrentLinkStatus(bpfmaniov1alpha1.ApAttachAttached)
 		case false:
 			// The link should be attached, but it isn't.
+			if err := checkAttachMode(rec); err != nil {
+				r.Logger.Error(err, "Not attaching eBPF Program")
+				rec.setCurrentLinkStatus(bpfmaniov1alpha1.ApAttachModeError)
+				break
+			}
+			// A link recorded but gone from bpfman drifted, e.g., it was
+			// detached by another tool.
+			missingLinkId := rec.getLinkId()
 			r.Logger.V(1).Info("Program is not attached, calling getAttachRequest()")
 			attachRequest := rec.getAttachRequest()
 			r.Logger.V(1).Info("AttachRequest", "attachRequest", attachRequest)
 			r.Logger.Info("Calling bpfman to attach eBPF Program on node")
-			linkId, err := bpfmanagentinternal.AttachBpfmanProgram(ctx, r.BpfmanClient, attachRequest)
+			linkId, err := bpfmanagentinternal.AttachBpfmanProgram(ctx, r.BpfmanClient, r.AuditLog, attachRequest)
 			if err != nil {
 				r.Logger.Error(err, "Failed to attach eBPF Program")
 				rec.setCurrentLinkStatus(bpfmaniov1alpha1.ApAttachError)
 			} else {
 				r.Logger.Info("Successfully attached eBPF Program", "Link ID", linkId)
 				rec.setLinkId(linkId)
+				if err := checkAttachMode(rec); err != nil {
+					r.detachForAttachMode(ctx, rec, err)
+					break
+				}
 				rec.setCurrentLinkStatus(bpfmaniov1alpha1.ApAttachAttached)
+				if missingLinkId != nil && r.Recorder != nil {
+					r.Recorder.Eventf(rec.getAppState(), v1.EventTypeNormal, linkDriftCorrectedReason,
+						"Link %d of program %s was missing on node %s, re-attached it as link %d",
+						*missingLinkId, rec.getProgName(), r.NodeName, *linkId)
+				}
 			}
 		}
 	case false:
@@ -580,7 +862,7 @@ func (r *ReconcilerCommon) reconcileBpfLink(ctx context.Context, rec ProgramReco
 		case true:
 			// The program is attached but it shouldn't be attached.  Detach it.
 			r.Logger.Info("Calling bpfman to detach eBPF Program", "Link ID", rec.getLinkId())
-			if err := bpfmanagentinternal.DetachBpfmanProgram(ctx, r.BpfmanClient, *rec.getLinkId()); err != nil {
+			if err := bpfmanagentinternal.DetachBpfmanProgram(ctx, r.BpfmanClient, r.AuditLog, *rec.getLinkId()); err != nil {
 				r.Logger.Error(err, "Failed to detach eBPF Program")
 				rec.setCurrentLinkStatus(bpfmaniov1alpha1.ApDetachError)
 			} else {
@@ -599,6 +881,92 @@ func (r *ReconcilerCommon) reconcileBpfLink(ctx context.Context, rec ProgramReco
 	return remove, nil
 }
 
+// attachMetadata returns the metadata of the attach requests of a link, which
+// maps the link back to the Kubernetes objects it was attached for. namespace
+// is empty for the links of a ClusterBpfApplication, and pod is nil for the
+// links that are not attached for a pod.
+func attachMetadata(uuid string, namespace string, appName string, programName string,
+	pod *bpfmaniov1alpha1.AttachPodState) map[string]string {
+	metadata := map[string]string{
+		internal.UuidMetadataKey:    uuid,
+		internal.AppNameMetadataKey: appName,
+		internal.ProgramNameKey:     programName,
+	}
+	if namespace == "" {
+		metadata[internal.AppKindMetadataKey] = clusterBpfApplicationKind
+	} else {
+		metadata[internal.AppKindMetadataKey] = bpfApplicationKind
+		metadata[internal.AppNamespaceMetadataKey] = namespace
+	}
+	if pod != nil {
+		metadata[internal.PodNamespaceMetadataKey] = pod.Namespace
+		metadata[internal.PodNameMetadataKey] = pod.Name
+		metadata[internal.PodUIDMetadataKey] = pod.UID
+		if pod.ContainerName != "" {
+			metadata[internal.ContainerNameMetadataKey] = pod.ContainerName
+		}
+	}
+	return metadata
+}
+
+// attachModeChecker is implemented by the program reconcilers whose links
+// request a mode of the attach point that bpfman can't be asked for, but that
+// the kernel reports once a program is attached.
+type attachModeChecker interface {
+	// checkAttachMode records the mode of the attach point of the current link
+	// and returns an error if the program doesn't, or wouldn't, run in the
+	// requested mode.
+	checkAttachMode() error
+}
+
+// checkAttachMode checks the mode of the attach point of the current link of
+// rec, if rec is an attachModeChecker.
+func checkAttachMode(rec ProgramReconciler) error {
+	if checker, ok := rec.(attachModeChecker); ok {
+		return checker.checkAttachMode()
+	}
+	return nil
+}
+
+// detachForAttachMode detaches the current link of rec, whose program doesn't
+// run in the requested mode.
+func (r *ReconcilerCommon) detachForAttachMode(ctx context.Context, rec ProgramReconciler, modeErr error) {
+	r.Logger.Error(modeErr, "eBPF Program is not attached in the requested mode, detaching it", "Link ID", *rec.getLinkId())
+	if err := bpfmanagentinternal.DetachBpfmanProgram(ctx, r.BpfmanClient, r.AuditLog, *rec.getLinkId()); err != nil {
+		r.Logger.Error(err, "Failed to detach eBPF Program")
+		rec.setCurrentLinkStatus(bpfmaniov1alpha1.ApDetachError)
+		return
+	}
+	rec.setLinkId(nil)
+	rec.setCurrentLinkStatus(bpfmaniov1alpha1.ApAttachModeError)
+}
+
+// getLinkPriority returns the priority of a link of an application in
+// namespace, which is empty for a ClusterBpfApplication. When className is
+// set, the priority is resolved with the BpfPriorityClass it names.
+// Otherwise, a BpfApplication can't use a priority reserved by a class its
+// namespace is not allowed to use.
+func (r *ReconcilerCommon) getLinkPriority(ctx context.Context, namespace string, className string,
+	priority *int32) (int32, error) {
+	if className == "" {
+		if namespace != "" {
+			classes := &bpfmaniov1alpha1.BpfPriorityClassList{}
+			if err := r.List(ctx, classes); err != nil {
+				return 0, fmt.Errorf("failed to list BpfPriorityClasses: %w", err)
+			}
+			if err := helpers.CheckRawPriority(classes.Items, namespace, priority); err != nil {
+				return 0, err
+			}
+		}
+		return helpers.GetPriority(priority), nil
+	}
+	class := &bpfmaniov1alpha1.BpfPriorityClass{}
+	if err := r.Get(ctx, types.NamespacedName{Name: className}, class); err != nil {
+		return 0, fmt.Errorf("failed to get BpfPriorityClass %s: %w", className, err)
+	}
+	return helpers.GetClassPriority(class, namespace, priority)
+}
+
 func isAttachSuccess(shouldAttach bool, status bpfmaniov1alpha1.LinkStatus) bool {
 	if shouldAttach && status == bpfmaniov1alpha1.ApAttachAttached {
 		return true
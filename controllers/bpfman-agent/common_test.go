@@ -0,0 +1,199 @@
+/*
+Copyright 2025 The bpfman Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bpfmanagent
+
+import (
+	"net"
+	"sync"
+	"testing"
+
+	bpfmaniov1alpha1 "github.com/bpfman/bpfman-operator/apis/v1alpha1"
+	bpfmanagentinternal "github.com/bpfman/bpfman-operator/controllers/bpfman-agent/internal"
+	"github.com/bpfman/bpfman-operator/internal"
+	"github.com/netobserv/netobserv-ebpf-agent/pkg/ifaces"
+	"github.com/stretchr/testify/require"
+	"k8s.io/utils/ptr"
+)
+
+func TestLinkSelected(t *testing.T) {
+	mac, err := net.ParseMAC("0c:42:a1:00:00:01")
+	require.NoError(t, err)
+	vf := &bpfmanagentinternal.LinkProperties{
+		Name:      "ens1f0v0",
+		Driver:    "mlx5_core",
+		Type:      bpfmaniov1alpha1.InterfaceLinkTypePhysical,
+		MAC:       mac,
+		MTU:       9000,
+		SriovRole: bpfmaniov1alpha1.SriovRoleVF,
+		AltNames:  []string{"enp3s0f0v0"},
+		Alias:     "uplink-a",
+	}
+
+	tests := []struct {
+		name     string
+		selector bpfmaniov1alpha1.InterfaceLinkSelector
+		want     bool
+	}{
+		{name: "driver", selector: bpfmaniov1alpha1.InterfaceLinkSelector{Drivers: []string{"ice", "mlx5_core"}}, want: true},
+		{name: "other driver", selector: bpfmaniov1alpha1.InterfaceLinkSelector{Drivers: []string{"ice"}}},
+		{name: "type", selector: bpfmaniov1alpha1.InterfaceLinkSelector{Types: []bpfmaniov1alpha1.InterfaceLinkType{"physical"}}, want: true},
+		{name: "other type", selector: bpfmaniov1alpha1.InterfaceLinkSelector{Types: []bpfmaniov1alpha1.InterfaceLinkType{"veth", "bond"}}},
+		{name: "OUI", selector: bpfmaniov1alpha1.InterfaceLinkSelector{MacOUIs: []string{"0C:42:A1"}}, want: true},
+		{name: "other OUI", selector: bpfmaniov1alpha1.InterfaceLinkSelector{MacOUIs: []string{"00:1b:21"}}},
+		{name: "MTU range", selector: bpfmaniov1alpha1.InterfaceLinkSelector{MinMTU: ptr.To(int32(1500)), MaxMTU: ptr.To(int32(9000))}, want: true},
+		{name: "MTU too small", selector: bpfmaniov1alpha1.InterfaceLinkSelector{MinMTU: ptr.To(int32(9001))}},
+		{name: "SR-IOV role", selector: bpfmaniov1alpha1.InterfaceLinkSelector{SriovRole: ptr.To(bpfmaniov1alpha1.SriovRoleVF)}, want: true},
+		{name: "other SR-IOV role", selector: bpfmaniov1alpha1.InterfaceLinkSelector{SriovRole: ptr.To(bpfmaniov1alpha1.SriovRolePF)}},
+		{name: "altname", selector: bpfmaniov1alpha1.InterfaceLinkSelector{AltNames: []string{"enp3s0f0v0"}}, want: true},
+		{name: "alias regexp", selector: bpfmaniov1alpha1.InterfaceLinkSelector{AltNames: []string{"/^uplink-/"}}, want: true},
+		{name: "other altname", selector: bpfmaniov1alpha1.InterfaceLinkSelector{AltNames: []string{"enp4s0"}}},
+		{
+			name: "every field must match",
+			selector: bpfmaniov1alpha1.InterfaceLinkSelector{
+				Drivers:   []string{"mlx5_core"},
+				SriovRole: ptr.To(bpfmaniov1alpha1.SriovRolePF),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, linkSelected(&tt.selector, vf))
+		})
+	}
+}
+
+func TestLinkSelectorInterfaces(t *testing.T) {
+	podNetns := internal.NetNsPath + "/pod"
+	pod := &PodNetnsInfo{podName: "pod", netnsPath: "/host/proc/1234/ns/net"}
+	links := map[string][]bpfmanagentinternal.LinkProperties{
+		"": {
+			{Name: "lo", MTU: 65536},
+			{Name: "eth0", Driver: "ice", Type: bpfmaniov1alpha1.InterfaceLinkTypePhysical, MTU: 1500},
+			{Name: "bond0", Driver: "bonding", Type: bpfmaniov1alpha1.InterfaceLinkTypeBond, MTU: 1500},
+		},
+		pod.netnsPath: {
+			{Name: "eth0", Driver: "veth", Type: bpfmaniov1alpha1.InterfaceLinkTypeVeth, MTU: 1450},
+			{Name: "net1", Driver: "iavf", Type: bpfmaniov1alpha1.InterfaceLinkTypePhysical, MTU: 9000,
+				SriovRole: bpfmaniov1alpha1.SriovRoleVF},
+		},
+		podNetns: {
+			{Name: "eth0", Driver: "veth", Type: bpfmaniov1alpha1.InterfaceLinkTypeVeth, MTU: 1450},
+			{Name: "veth1", Driver: "veth", Type: bpfmaniov1alpha1.InterfaceLinkTypeVeth, MTU: 9000},
+		},
+	}
+	defer func(f func(string) ([]bpfmanagentinternal.LinkProperties, error)) { listLinkProperties = f }(listLinkProperties)
+	listLinkProperties = func(netnsPath string) ([]bpfmanagentinternal.LinkProperties, error) {
+		return links[netnsPath], nil
+	}
+
+	// The static path selects the interfaces of the host, or of the
+	// network namespaces of the selected pods.
+	physical := &bpfmaniov1alpha1.InterfaceSelector{
+		LinkSelector: &bpfmaniov1alpha1.InterfaceLinkSelector{
+			Types: []bpfmaniov1alpha1.InterfaceLinkType{bpfmaniov1alpha1.InterfaceLinkTypePhysical},
+		},
+	}
+	interfaces, err := getInterfaces(physical, nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{"eth0"}, interfaces)
+	interfaces, err = getNetnsInterfaces(physical, nil, interfaces, pod)
+	require.NoError(t, err)
+	require.Equal(t, []string{"net1"}, interfaces)
+
+	// Other selectors don't depend on the network namespace.
+	names := &bpfmaniov1alpha1.InterfaceSelector{Interfaces: []string{"eth1"}}
+	interfaces, err = getNetnsInterfaces(names, nil, []string{"eth1"}, pod)
+	require.NoError(t, err)
+	require.Equal(t, []string{"eth1"}, interfaces)
+
+	// Discovered interfaces are filtered by their properties in their
+	// network namespace.
+	discovered := &sync.Map{}
+	for _, key := range []ifaces.InterfaceKey{
+		{Name: "lo"}, {Name: "eth0"}, {Name: "bond0"},
+		{Name: "eth0", NSName: "pod"}, {Name: "veth1", NSName: "pod"},
+	} {
+		discovered.Store(ifaces.Interface{InterfaceKey: key}, true)
+	}
+	discovery := &bpfmaniov1alpha1.InterfaceSelector{
+		InterfacesDiscoveryConfig: &bpfmaniov1alpha1.InterfaceDiscovery{
+			InterfaceAutoDiscovery: ptr.To(true),
+			ExcludeInterfaces:      []string{"lo"},
+			LinkSelector: &bpfmaniov1alpha1.InterfaceLinkSelector{
+				MaxMTU: ptr.To(int32(1500)),
+			},
+		},
+	}
+	require.ElementsMatch(t, []discoveredInterface{
+		{interfaceName: "eth0"},
+		{interfaceName: "bond0"},
+		{interfaceName: "eth0", netNSPath: podNetns},
+	}, getDiscoveredInterfaces(discovery, discovered))
+}
+
+func TestPodDiscoveredInterfaces(t *testing.T) {
+	// Discovery names the network namespace of the pods under /run/netns.
+	discovered := &sync.Map{}
+	for _, key := range []ifaces.InterfaceKey{
+		{Name: "eth0"},
+		{Name: "eth0", NSName: "cni-1"}, {Name: "net1", NSName: "cni-1"},
+		{Name: "eth0", NSName: "cni-2"},
+		{Name: "eth0", NSName: "cni-other"},
+	} {
+		discovered.Store(ifaces.Interface{InterfaceKey: key}, true)
+	}
+	r := &ReconcilerCommon{
+		Interfaces: discovered,
+		NetNsCache: MockNetNsCache{
+			"":                                ptr.To(uint64(1)),
+			internal.NetNsPath + "/cni-1":     ptr.To(uint64(10)),
+			internal.NetNsPath + "/cni-2":     ptr.To(uint64(20)),
+			internal.NetNsPath + "/cni-other": ptr.To(uint64(30)),
+			"/host/proc/100/ns/net":           ptr.To(uint64(10)),
+			"/host/proc/200/ns/net":           ptr.To(uint64(20)),
+		},
+	}
+	pods := []PodNetnsInfo{
+		{podName: "pod-1", podNamespace: "default", netnsPath: "/host/proc/100/ns/net",
+			networkStatus: `[{"name": "default/sriov", "interface": "net1"}]`},
+		{podName: "pod-2", podNamespace: "default", netnsPath: "/host/proc/200/ns/net"},
+	}
+	discovery := &bpfmaniov1alpha1.InterfaceSelector{
+		InterfacesDiscoveryConfig: &bpfmaniov1alpha1.InterfaceDiscovery{
+			InterfaceAutoDiscovery: ptr.To(true),
+		},
+	}
+
+	// Only the interfaces in the network namespaces of the pods are selected.
+	podsInterfaces, err := r.getPodsInterfaces(discovery, nil, nil, pods)
+	require.NoError(t, err)
+	require.Len(t, podsInterfaces, 2)
+	require.ElementsMatch(t, []string{"eth0", "net1"}, podsInterfaces[0])
+	require.Equal(t, []string{"eth0"}, podsInterfaces[1])
+
+	// A network attachment restricts them to its interfaces.
+	podsInterfaces, err = r.getPodsInterfaces(discovery,
+		&bpfmaniov1alpha1.NetworkAttachmentReference{Name: "sriov"}, nil, pods)
+	require.NoError(t, err)
+	require.Equal(t, [][]string{{"net1"}, nil}, podsInterfaces)
+
+	// Without discovery, the interfaces of the selector are used.
+	podsInterfaces, err = r.getPodsInterfaces(&bpfmaniov1alpha1.InterfaceSelector{Interfaces: []string{"eth1"}},
+		nil, []string{"eth1"}, pods)
+	require.NoError(t, err)
+	require.Equal(t, [][]string{{"eth1"}, {"eth1"}}, podsInterfaces)
+}
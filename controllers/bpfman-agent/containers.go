@@ -24,6 +24,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 
+	bpfmaniov1alpha1 "github.com/bpfman/bpfman-operator/apis/v1alpha1"
 	"github.com/bpfman/bpfman-operator/pkg/crictl"
 	"github.com/go-logr/logr"
 )
@@ -37,10 +38,65 @@ const (
 
 type ContainerInfo struct {
 	podName       string
+	podNamespace  string
+	podUID        string
+	sandboxID     string
 	containerName string
 	pid           int32
 }
 
+// podState returns the pod recorded in the links attached in the container.
+func (c *ContainerInfo) podState() *bpfmaniov1alpha1.AttachPodState {
+	return &bpfmaniov1alpha1.AttachPodState{
+		Name:          c.podName,
+		Namespace:     c.podNamespace,
+		UID:           c.podUID,
+		SandboxID:     c.sandboxID,
+		ContainerName: c.containerName,
+	}
+}
+
+// PodNetnsInfo identifies the network namespace of a pod.
+type PodNetnsInfo struct {
+	podName      string
+	podNamespace string
+	podUID       string
+	sandboxID    string
+	// netnsPath is the network namespace of the pod sandbox, or "" for pods
+	// that use the network namespace of the node.
+	netnsPath   string
+	hostNetwork bool
+	// networkStatus is the network-status annotation of the pod, set by
+	// Multus.
+	networkStatus string
+}
+
+// podState returns the pod recorded in the links attached in the network
+// namespace of the pod, or nil for pods that use the network namespace of the
+// node since the links of the root network namespace are not tied to a pod.
+func (p *PodNetnsInfo) podState() *bpfmaniov1alpha1.AttachPodState {
+	if p.hostNetwork {
+		return nil
+	}
+	return &bpfmaniov1alpha1.AttachPodState{
+		Name:      p.podName,
+		Namespace: p.podNamespace,
+		UID:       p.podUID,
+		SandboxID: p.sandboxID,
+	}
+}
+
+// samePodSandbox returns true if a link recorded for the pod existing stands
+// for a link expected for the pod expected. When the pod was recreated, or its
+// sandbox was, the link is in a network namespace that is gone and must be
+// attached again. Links recorded without a pod stand for any pod.
+func samePodSandbox(existing, expected *bpfmaniov1alpha1.AttachPodState) bool {
+	if existing == nil || expected == nil {
+		return true
+	}
+	return existing.UID == expected.UID && existing.SandboxID == expected.SandboxID
+}
+
 // Create an interface for getting the list of containers in which the program
 // should be attached so we can mock it in unit tests.
 type ContainerGetter interface {
@@ -50,6 +106,13 @@ type ContainerGetter interface {
 		selectorPods metav1.LabelSelector,
 		selectorContainerNames *[]string,
 		logger logr.Logger) (*[]ContainerInfo, error)
+
+	// Get the network namespaces of the pods on this node that match the
+	// selector, from their sandboxes rather than from their containers.
+	GetPodNetworkNamespaces(ctx context.Context,
+		selectorNamespace string,
+		selectorPods metav1.LabelSelector,
+		logger logr.Logger) (*[]PodNetnsInfo, error)
 }
 
 type RealContainerGetter struct {
@@ -166,6 +229,10 @@ func getContainerInfo(ctx context.Context, podList *v1.PodList, containerNames *
 		if err != nil {
 			return nil, fmt.Errorf("failed to get container info for pod %s: %w", pod.Name, err)
 		}
+		for j := range containerInfos {
+			containerInfos[j].podNamespace = pod.Namespace
+			containerInfos[j].podUID = string(pod.UID)
+		}
 
 		containers = append(containers, containerInfos...)
 	}
@@ -199,6 +266,7 @@ func getContainerInfoFromPod(ctx context.Context, podName string, containerNames
 	for i, info := range pidInfos {
 		result[i] = ContainerInfo{
 			podName:       info.PodName,
+			sandboxID:     info.PodSandboxID,
 			containerName: info.ContainerName,
 			pid:           info.PID,
 		}
@@ -212,15 +280,104 @@ func getContainerInfoFromPod(ctx context.Context, podName string, containerNames
 	return result, nil
 }
 
-func GetOneContainerPerPod(containers *[]ContainerInfo) *[]ContainerInfo {
-	uniquePods := make(map[string]bool)
-	uniqueContainers := []ContainerInfo{}
-	for _, container := range *containers {
-		if _, ok := uniquePods[container.podName]; !ok {
-			uniquePods[container.podName] = true
-			uniqueContainers = append(uniqueContainers, container)
+func (c *RealContainerGetter) GetPodNetworkNamespaces(
+	ctx context.Context,
+	selectorNamespace string,
+	selectorPods metav1.LabelSelector,
+	logger logr.Logger) (*[]PodNetnsInfo, error) {
+
+	podList, err := c.getPodsForNode(ctx, selectorNamespace, selectorPods)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pod list: %v", err)
+	}
+
+	var podUIDs []string
+	for _, pod := range podList.Items {
+		if !pod.Spec.HostNetwork && podHasSandbox(&pod) {
+			podUIDs = append(podUIDs, string(pod.UID))
+		}
+	}
+
+	sandboxes := map[string]crictl.PodSandboxNetnsInfo{}
+	if len(podUIDs) > 0 {
+		crictlCtx, cancel := context.WithTimeout(ctx, containerDiscoveryTimeout)
+		defer cancel()
+		sandboxes, err = crictl.GetPodSandboxNetns(crictlCtx, podUIDs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get pod sandboxes: %w", err)
+		}
+	}
+
+	return podNetnsInfo(podList, sandboxes, logger), nil
+}
+
+// podHasSandbox returns true if the pod may have a running sandbox.
+func podHasSandbox(pod *v1.Pod) bool {
+	return pod.DeletionTimestamp == nil &&
+		(pod.Status.Phase == v1.PodPending || pod.Status.Phase == v1.PodRunning)
+}
+
+// podNetnsInfo returns the network namespaces of the pods in podList. Pods
+// that use the network namespace of the node are returned without a
+// netnsPath, and the other pods are omitted until their sandbox is ready.
+func podNetnsInfo(podList *v1.PodList, sandboxes map[string]crictl.PodSandboxNetnsInfo, logger logr.Logger) *[]PodNetnsInfo {
+	pods := []PodNetnsInfo{}
+	for _, pod := range podList.Items {
+		info := PodNetnsInfo{
+			podName:       pod.Name,
+			podNamespace:  pod.Namespace,
+			podUID:        string(pod.UID),
+			networkStatus: pod.Annotations[networkStatusAnnotation],
+		}
+		if pod.Spec.HostNetwork {
+			info.hostNetwork = true
+			pods = append(pods, info)
+			continue
+		}
+
+		sandbox, ok := sandboxes[string(pod.UID)]
+		if !ok {
+			logger.V(1).Info("No ready sandbox for pod", "namespace", pod.Namespace, "pod", pod.Name)
+			continue
 		}
+		info.sandboxID = sandbox.SandboxID
+		switch {
+		case sandbox.HostNetwork:
+			info.hostNetwork = true
+		case sandbox.PID > 0:
+			info.netnsPath = netnsPathFromPID(sandbox.PID)
+		case sandbox.NetnsPath != "":
+			info.netnsPath = sandbox.NetnsPath
+		default:
+			logger.Info("Network namespace of pod sandbox not found", "namespace", pod.Namespace,
+				"pod", pod.Name, "sandbox", sandbox.SandboxID)
+			continue
+		}
+		logger.V(1).Info("Pod network namespace", "namespace", pod.Namespace, "pod", pod.Name,
+			"sandbox", sandbox.SandboxID, "netnsPath", info.netnsPath, "hostNetwork", info.hostNetwork)
+		pods = append(pods, info)
 	}
+	return &pods
+}
 
-	return &uniqueContainers
+// selectPodNetns returns the pods whose network namespace the program should
+// be attached to. Pods that use the network namespace of the node are dropped
+// unless policy is RootNetns, in which case a single one of them is kept to
+// stand for the root network namespace.
+func selectPodNetns(pods *[]PodNetnsInfo, policy bpfmaniov1alpha1.HostNetworkPodPolicy) []PodNetnsInfo {
+	if pods == nil {
+		return nil
+	}
+	var selected []PodNetnsInfo
+	rootNetns := false
+	for _, pod := range *pods {
+		if pod.hostNetwork {
+			if policy != bpfmaniov1alpha1.HostNetworkPodRootNetns || rootNetns {
+				continue
+			}
+			rootNetns = true
+		}
+		selected = append(selected, pod)
+	}
+	return selected
 }
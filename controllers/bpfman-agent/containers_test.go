@@ -0,0 +1,71 @@
+/*
+Copyright 2025 The bpfman Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bpfmanagent
+
+import (
+	"testing"
+
+	bpfmaniov1alpha1 "github.com/bpfman/bpfman-operator/apis/v1alpha1"
+	"github.com/bpfman/bpfman-operator/pkg/crictl"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func TestPodNetnsInfo(t *testing.T) {
+	newPod := func(name string, hostNetwork bool) v1.Pod {
+		return v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", UID: types.UID(name + "-uid")},
+			Spec:       v1.PodSpec{HostNetwork: hostNetwork},
+		}
+	}
+	podList := &v1.PodList{Items: []v1.Pod{
+		newPod("containerd", false),
+		newPod("spec-only", false),
+		newPod("host", true),
+		newPod("pending", false),
+		newPod("host-sandbox", false),
+	}}
+	sandboxes := map[string]crictl.PodSandboxNetnsInfo{
+		"containerd-uid":   {SandboxID: "1", PID: 1234, NetnsPath: "/var/run/netns/cni-1"},
+		"spec-only-uid":    {SandboxID: "2", NetnsPath: "/var/run/netns/cni-2"},
+		"host-sandbox-uid": {SandboxID: "3", PID: 1, HostNetwork: true},
+	}
+
+	pods := podNetnsInfo(podList, sandboxes, ctrl.Log.WithName("test"))
+	require.Equal(t, []PodNetnsInfo{
+		{podName: "containerd", podNamespace: "default", podUID: "containerd-uid", sandboxID: "1", netnsPath: "/host/proc/1234/ns/net"},
+		{podName: "spec-only", podNamespace: "default", podUID: "spec-only-uid", sandboxID: "2", netnsPath: "/var/run/netns/cni-2"},
+		{podName: "host", podNamespace: "default", podUID: "host-uid", hostNetwork: true},
+		{podName: "host-sandbox", podNamespace: "default", podUID: "host-sandbox-uid", sandboxID: "3", hostNetwork: true},
+	}, *pods)
+
+	selected := selectPodNetns(pods, bpfmaniov1alpha1.HostNetworkPodSkip)
+	require.Len(t, selected, 2)
+	require.Equal(t, "containerd", selected[0].podName)
+	require.Equal(t, "spec-only", selected[1].podName)
+
+	// The root network namespace is only selected once.
+	selected = selectPodNetns(pods, bpfmaniov1alpha1.HostNetworkPodRootNetns)
+	require.Len(t, selected, 3)
+	require.Equal(t, "host", selected[2].podName)
+	require.Empty(t, selected[2].netnsPath)
+
+	require.Empty(t, selectPodNetns(nil, bpfmaniov1alpha1.HostNetworkPodRootNetns))
+}
@@ -0,0 +1,198 @@
+/*
+Copyright 2025 The bpfman Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bpfmanagent
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	bpfmaniov1alpha1 "github.com/bpfman/bpfman-operator/apis/v1alpha1"
+	"github.com/bpfman/bpfman-operator/internal"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	priorityConflictReason   = "EqualPriority"
+	noPriorityConflictReason = "NoConflict"
+)
+
+type dispatcherChainKey struct {
+	netnsPath     string
+	interfaceName string
+	hook          bpfmaniov1alpha1.DispatcherHook
+}
+
+// dispatcherChains returns the XDP and TC dispatcher chains of nodeName,
+// built from the attached links recorded in its BpfApplicationStates. The
+// order of the programs is inferred from their priorities, with ties broken
+// by link ID, rather than read from bpfman.
+func dispatcherChains(ctx context.Context, reader client.Reader, nodeName string) ([]bpfmaniov1alpha1.DispatcherChain, error) {
+	chains := map[dispatcherChainKey][]bpfmaniov1alpha1.DispatcherProgram{}
+	add := func(key dispatcherChainKey, program bpfmaniov1alpha1.DispatcherProgram, attachInfo *bpfmaniov1alpha1.AttachInfoStateCommon) {
+		if !attachInfo.ShouldAttach || attachInfo.LinkId == nil {
+			return
+		}
+		program.LinkId = *attachInfo.LinkId
+		chains[key] = append(chains[key], program)
+	}
+	opts := []client.ListOption{client.MatchingLabels{internal.K8sHostLabel: nodeName}}
+
+	clStates := &bpfmaniov1alpha1.ClusterBpfApplicationStateList{}
+	if err := reader.List(ctx, clStates, opts...); err != nil {
+		return nil, fmt.Errorf("failed to list ClusterBpfApplicationStates: %w", err)
+	}
+	for i := range clStates.Items {
+		state := &clStates.Items[i]
+		for _, program := range state.Status.Programs {
+			entry := bpfmaniov1alpha1.DispatcherProgram{
+				ApplicationKind: clusterBpfApplicationKind,
+				ApplicationName: appOwnerName(state),
+				Program:         program.Name,
+			}
+			switch {
+			case program.XDP != nil:
+				for _, link := range program.XDP.Links {
+					entry.Priority = link.Priority
+					entry.ProceedOn = xdpProceedOnStrings(link.ProceedOn)
+					add(dispatcherChainKey{link.NetnsPath, link.InterfaceName, bpfmaniov1alpha1.DispatcherHookXDP},
+						entry, &link.AttachInfoStateCommon)
+				}
+			case program.TC != nil:
+				for _, link := range program.TC.Links {
+					entry.Priority = link.Priority
+					entry.ProceedOn = tcProceedOnStrings(link.ProceedOn)
+					add(dispatcherChainKey{link.NetnsPath, link.InterfaceName, tcDispatcherHook(link.Direction)},
+						entry, &link.AttachInfoStateCommon)
+				}
+			}
+		}
+	}
+
+	nsStates := &bpfmaniov1alpha1.BpfApplicationStateList{}
+	if err := reader.List(ctx, nsStates, opts...); err != nil {
+		return nil, fmt.Errorf("failed to list BpfApplicationStates: %w", err)
+	}
+	for i := range nsStates.Items {
+		state := &nsStates.Items[i]
+		for _, program := range state.Status.Programs {
+			entry := bpfmaniov1alpha1.DispatcherProgram{
+				ApplicationKind:      bpfApplicationKind,
+				ApplicationNamespace: state.Namespace,
+				ApplicationName:      appOwnerName(state),
+				Program:              program.Name,
+			}
+			switch {
+			case program.XDP != nil:
+				for _, link := range program.XDP.Links {
+					entry.Priority = link.Priority
+					entry.ProceedOn = xdpProceedOnStrings(link.ProceedOn)
+					add(dispatcherChainKey{link.NetnsPath, link.InterfaceName, bpfmaniov1alpha1.DispatcherHookXDP},
+						entry, &link.AttachInfoStateCommon)
+				}
+			case program.TC != nil:
+				for _, link := range program.TC.Links {
+					entry.Priority = link.Priority
+					entry.ProceedOn = tcProceedOnStrings(link.ProceedOn)
+					add(dispatcherChainKey{link.NetnsPath, link.InterfaceName, tcDispatcherHook(link.Direction)},
+						entry, &link.AttachInfoStateCommon)
+				}
+			}
+		}
+	}
+
+	out := make([]bpfmaniov1alpha1.DispatcherChain, 0, len(chains))
+	for key, programs := range chains {
+		sort.Slice(programs, func(i, j int) bool {
+			if programs[i].Priority != programs[j].Priority {
+				return programs[i].Priority < programs[j].Priority
+			}
+			return programs[i].LinkId < programs[j].LinkId
+		})
+		for i := range programs {
+			programs[i].Position = int32(i)
+		}
+		out = append(out, bpfmaniov1alpha1.DispatcherChain{
+			InterfaceName: key.interfaceName,
+			NetnsPath:     key.netnsPath,
+			Hook:          key.hook,
+			Programs:      programs,
+		})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].NetnsPath != out[j].NetnsPath {
+			return out[i].NetnsPath < out[j].NetnsPath
+		}
+		if out[i].InterfaceName != out[j].InterfaceName {
+			return out[i].InterfaceName < out[j].InterfaceName
+		}
+		return out[i].Hook < out[j].Hook
+	})
+	return out, nil
+}
+
+// priorityConflicts describes the programs of different applications that
+// share a priority in chains.
+func priorityConflicts(chains []bpfmaniov1alpha1.DispatcherChain) []string {
+	var conflicts []string
+	for _, chain := range chains {
+		where := chain.InterfaceName
+		if chain.NetnsPath != "" {
+			where = fmt.Sprintf("%s in %s", chain.InterfaceName, chain.NetnsPath)
+		}
+		for i := 1; i < len(chain.Programs); i++ {
+			prev, cur := &chain.Programs[i-1], &chain.Programs[i]
+			if prev.Priority != cur.Priority || dispatcherProgramApp(prev) == dispatcherProgramApp(cur) {
+				continue
+			}
+			conflicts = append(conflicts, fmt.Sprintf("%s %s and %s %s have priority %d on %s %s",
+				dispatcherProgramApp(prev), prev.Program, dispatcherProgramApp(cur), cur.Program,
+				cur.Priority, chain.Hook, where))
+		}
+	}
+	return conflicts
+}
+
+func dispatcherProgramApp(program *bpfmaniov1alpha1.DispatcherProgram) string {
+	if program.ApplicationNamespace != "" {
+		return fmt.Sprintf("%s %s/%s", program.ApplicationKind, program.ApplicationNamespace, program.ApplicationName)
+	}
+	return fmt.Sprintf("%s %s", program.ApplicationKind, program.ApplicationName)
+}
+
+func tcDispatcherHook(direction bpfmaniov1alpha1.TCDirectionType) bpfmaniov1alpha1.DispatcherHook {
+	if direction == bpfmaniov1alpha1.TCEgress {
+		return bpfmaniov1alpha1.DispatcherHookTCEgress
+	}
+	return bpfmaniov1alpha1.DispatcherHookTCIngress
+}
+
+func xdpProceedOnStrings(proceedOn []bpfmaniov1alpha1.XdpProceedOnValue) []string {
+	var out []string
+	for _, value := range proceedOn {
+		out = append(out, string(value))
+	}
+	return out
+}
+
+func tcProceedOnStrings(proceedOn []bpfmaniov1alpha1.TcProceedOnValue) []string {
+	var out []string
+	for _, value := range proceedOn {
+		out = append(out, string(value))
+	}
+	return out
+}
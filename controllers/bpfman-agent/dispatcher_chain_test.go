@@ -0,0 +1,183 @@
+/*
+Copyright 2025 The bpfman Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bpfmanagent
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	bpfmaniov1alpha1 "github.com/bpfman/bpfman-operator/apis/v1alpha1"
+	agenttestutils "github.com/bpfman/bpfman-operator/controllers/bpfman-agent/internal/test-utils"
+	"github.com/bpfman/bpfman-operator/internal"
+	testutils "github.com/bpfman/bpfman-operator/internal/test-utils"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestProgramInventoryDispatchers(t *testing.T) {
+	ctx := context.TODO()
+	fakeNode := testutils.NewNode("fake-control-plane")
+
+	s := scheme.Scheme
+	s.AddKnownTypes(bpfmaniov1alpha1.SchemeGroupVersion,
+		&bpfmaniov1alpha1.BpfProgramInventory{},
+		&bpfmaniov1alpha1.BpfProgramInventoryList{},
+		&bpfmaniov1alpha1.ClusterBpfApplicationState{},
+		&bpfmaniov1alpha1.ClusterBpfApplicationStateList{},
+		&bpfmaniov1alpha1.BpfApplicationState{},
+		&bpfmaniov1alpha1.BpfApplicationStateList{},
+	)
+
+	owner := func(kind, name string) []metav1.OwnerReference {
+		return []metav1.OwnerReference{{
+			APIVersion: bpfmaniov1alpha1.SchemeGroupVersion.String(),
+			Kind:       kind,
+			Name:       name,
+			Controller: ptr.To(true),
+		}}
+	}
+	xdpLink := func(linkId uint32, priority int32) bpfmaniov1alpha1.ClXdpAttachInfoState {
+		return bpfmaniov1alpha1.ClXdpAttachInfoState{
+			AttachInfoStateCommon: bpfmaniov1alpha1.AttachInfoStateCommon{
+				ShouldAttach: true,
+				LinkId:       ptr.To(linkId),
+				LinkStatus:   bpfmaniov1alpha1.ApAttachAttached,
+			},
+			InterfaceName: fakeInt0,
+			Priority:      priority,
+			ProceedOn:     []bpfmaniov1alpha1.XdpProceedOnValue{"pass"},
+		}
+	}
+	clState := &bpfmaniov1alpha1.ClusterBpfApplicationState{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "firewall-state",
+			Labels:          map[string]string{internal.K8sHostLabel: fakeNode.Name},
+			OwnerReferences: owner(clusterBpfApplicationKind, "firewall"),
+		},
+		Status: bpfmaniov1alpha1.ClBpfApplicationStateStatus{
+			Programs: []bpfmaniov1alpha1.ClBpfApplicationProgramState{{
+				BpfProgramStateCommon: bpfmaniov1alpha1.BpfProgramStateCommon{Name: "firewall"},
+				Type:                  bpfmaniov1alpha1.ProgTypeXDP,
+				XDP: &bpfmaniov1alpha1.ClXdpProgramInfoState{
+					Links: []bpfmaniov1alpha1.ClXdpAttachInfoState{xdpLink(3, 50)},
+				},
+			}, {
+				BpfProgramStateCommon: bpfmaniov1alpha1.BpfProgramStateCommon{Name: "counter"},
+				Type:                  bpfmaniov1alpha1.ProgTypeXDP,
+				XDP: &bpfmaniov1alpha1.ClXdpProgramInfoState{
+					Links: []bpfmaniov1alpha1.ClXdpAttachInfoState{xdpLink(1, 10)},
+				},
+			}},
+		},
+	}
+	nsState := &bpfmaniov1alpha1.BpfApplicationState{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       testNamespace,
+			Name:            "stats-state",
+			Labels:          map[string]string{internal.K8sHostLabel: fakeNode.Name},
+			OwnerReferences: owner(bpfApplicationKind, "stats"),
+		},
+		Status: bpfmaniov1alpha1.BpfApplicationStateStatus{
+			Programs: []bpfmaniov1alpha1.BpfApplicationProgramState{{
+				BpfProgramStateCommon: bpfmaniov1alpha1.BpfProgramStateCommon{Name: "stats"},
+				Type:                  bpfmaniov1alpha1.ProgTypeTC,
+				TC: &bpfmaniov1alpha1.TcProgramInfoState{
+					Links: []bpfmaniov1alpha1.TcAttachInfoState{{
+						AttachInfoStateCommon: bpfmaniov1alpha1.AttachInfoStateCommon{
+							ShouldAttach: true,
+							LinkId:       ptr.To(uint32(5)),
+						},
+						InterfaceName: fakeInt0,
+						NetnsPath:     "/host/proc/1234/ns/net",
+						Direction:     bpfmaniov1alpha1.TCEgress,
+						Priority:      50,
+					}, {
+						// Not attached yet.
+						AttachInfoStateCommon: bpfmaniov1alpha1.AttachInfoStateCommon{
+							ShouldAttach: true,
+						},
+						InterfaceName: "eth1",
+						Direction:     bpfmaniov1alpha1.TCIngress,
+						Priority:      50,
+					}},
+				},
+			}},
+		},
+	}
+
+	cl := fake.NewClientBuilder().WithScheme(s).
+		WithStatusSubresource(&bpfmaniov1alpha1.BpfProgramInventory{}).
+		WithObjects(fakeNode, clState, nsState).Build()
+	cli := agenttestutils.NewBpfmanClientFake()
+	inventory := NewProgramInventory(cl, cli, fakeNode.Name, time.Minute)
+	require.NoError(t, inventory.Update(ctx))
+
+	got := &bpfmaniov1alpha1.BpfProgramInventory{}
+	require.NoError(t, cl.Get(ctx, types.NamespacedName{Name: fakeNode.Name}, got))
+	require.Equal(t, []bpfmaniov1alpha1.DispatcherChain{
+		{
+			InterfaceName: fakeInt0,
+			Hook:          bpfmaniov1alpha1.DispatcherHookXDP,
+			Programs: []bpfmaniov1alpha1.DispatcherProgram{
+				{Position: 0, ApplicationKind: clusterBpfApplicationKind, ApplicationName: "firewall", Program: "counter", LinkId: 1, Priority: 10, ProceedOn: []string{"pass"}},
+				{Position: 1, ApplicationKind: clusterBpfApplicationKind, ApplicationName: "firewall", Program: "firewall", LinkId: 3, Priority: 50, ProceedOn: []string{"pass"}},
+			},
+		},
+		{
+			InterfaceName: fakeInt0,
+			NetnsPath:     "/host/proc/1234/ns/net",
+			Hook:          bpfmaniov1alpha1.DispatcherHookTCEgress,
+			Programs: []bpfmaniov1alpha1.DispatcherProgram{
+				{Position: 0, ApplicationKind: bpfApplicationKind, ApplicationNamespace: testNamespace, ApplicationName: "stats", Program: "stats", LinkId: 5, Priority: 50},
+			},
+		},
+	}, got.Status.Dispatchers)
+	require.True(t, meta.IsStatusConditionFalse(got.Status.Conditions,
+		string(bpfmaniov1alpha1.BpfProgramInventoryConditionPriorityConflict)))
+
+	// Another application attaches to the XDP dispatcher of eth0 with the
+	// priority of the firewall.
+	other := clState.DeepCopy()
+	other.ResourceVersion = ""
+	other.Name = "monitor-state"
+	other.OwnerReferences = owner(clusterBpfApplicationKind, "monitor")
+	other.Status.Programs = other.Status.Programs[:1]
+	other.Status.Programs[0].Name = "monitor"
+	other.Status.Programs[0].XDP.Links = []bpfmaniov1alpha1.ClXdpAttachInfoState{xdpLink(7, 50)}
+	require.NoError(t, cl.Create(ctx, other))
+
+	require.NoError(t, inventory.Update(ctx))
+	require.NoError(t, cl.Get(ctx, types.NamespacedName{Name: fakeNode.Name}, got))
+	xdpChain := got.Status.Dispatchers[0]
+	require.Len(t, xdpChain.Programs, 3)
+	require.Equal(t, "monitor", xdpChain.Programs[2].ApplicationName)
+	require.Equal(t, int32(2), xdpChain.Programs[2].Position)
+	conflict := meta.FindStatusCondition(got.Status.Conditions,
+		string(bpfmaniov1alpha1.BpfProgramInventoryConditionPriorityConflict))
+	require.NotNil(t, conflict)
+	require.Equal(t, metav1.ConditionTrue, conflict.Status)
+	require.Equal(t, priorityConflictReason, conflict.Reason)
+	require.Equal(t, "ClusterBpfApplication firewall firewall and ClusterBpfApplication monitor monitor have priority 50 on xdp eth0; "+
+		"their order is decided by bpfman and may differ from the inferred positions",
+		conflict.Message)
+}
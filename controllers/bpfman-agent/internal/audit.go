@@ -0,0 +1,187 @@
+/*
+Copyright 2025 The bpfman Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	gobpfman "github.com/bpfman/bpfman/clients/gobpfman/v1"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// Audit operations recorded for each mutating bpfman call.
+const (
+	AuditOpLoad   = "Load"
+	AuditOpUnload = "Unload"
+	AuditOpAttach = "Attach"
+	AuditOpDetach = "Detach"
+)
+
+// AuditSubject identifies the application on whose behalf the agent calls
+// bpfman.
+type AuditSubject struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	UID       string `json:"uid"`
+}
+
+// AuditRecord is a single line in the audit log.
+type AuditRecord struct {
+	Time        time.Time     `json:"time"`
+	Node        string        `json:"node"`
+	Operation   string        `json:"operation"`
+	Application *AuditSubject `json:"application,omitempty"`
+	Request     any           `json:"request,omitempty"`
+	ProgramIds  []uint32      `json:"programIds,omitempty"`
+	LinkId      *uint32       `json:"linkId,omitempty"`
+	Error       string        `json:"error,omitempty"`
+}
+
+// AuditLogOptions configures the rotation of the audit log file.
+type AuditLogOptions struct {
+	// Path of the active audit log file.
+	Path string
+	// MaxSizeMB is the size in megabytes at which the file is rotated.
+	MaxSizeMB int
+	// MaxBackups is the number of rotated files to retain.
+	MaxBackups int
+	// MaxAgeDays is the number of days to retain rotated files.
+	MaxAgeDays int
+}
+
+// AuditLog writes a JSON lines record of every mutating call the agent
+// makes to bpfman. A nil *AuditLog is valid and discards all records, so
+// callers do not need to check whether auditing is enabled.
+type AuditLog struct {
+	mu   sync.Mutex
+	out  io.WriteCloser
+	enc  *json.Encoder
+	node string
+	now  func() time.Time
+}
+
+// NewAuditLog returns an AuditLog that writes to a size rotated file as
+// described by opts.
+func NewAuditLog(node string, opts AuditLogOptions) *AuditLog {
+	return newAuditLog(node, &lumberjack.Logger{
+		Filename:   opts.Path,
+		MaxSize:    opts.MaxSizeMB,
+		MaxBackups: opts.MaxBackups,
+		MaxAge:     opts.MaxAgeDays,
+		Compress:   true,
+	})
+}
+
+func newAuditLog(node string, out io.WriteCloser) *AuditLog {
+	return &AuditLog{
+		out:  out,
+		enc:  json.NewEncoder(out),
+		node: node,
+		now:  time.Now,
+	}
+}
+
+// Close flushes and closes the underlying file.
+func (a *AuditLog) Close() error {
+	if a == nil {
+		return nil
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.out.Close()
+}
+
+// record writes one audit record. Failing to write the audit log is logged
+// but never fails the bpfman call that is being audited.
+func (a *AuditLog) record(ctx context.Context, rec AuditRecord, err error) {
+	if a == nil {
+		return
+	}
+
+	rec.Time = a.now().UTC()
+	rec.Node = a.node
+	if subject, ok := ctx.Value(auditSubjectKey{}).(AuditSubject); ok {
+		rec.Application = &subject
+	}
+	if err != nil {
+		rec.Error = err.Error()
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if werr := a.enc.Encode(rec); werr != nil {
+		log.Error(werr, "failed to write audit record", "operation", rec.Operation)
+	}
+}
+
+type auditSubjectKey struct{}
+
+// WithAuditSubject returns a copy of ctx that attributes the bpfman calls
+// made with it to subject in the audit log.
+func WithAuditSubject(ctx context.Context, subject AuditSubject) context.Context {
+	return context.WithValue(ctx, auditSubjectKey{}, subject)
+}
+
+// loadSummary is the audited subset of a LoadRequest. Registry credentials
+// and global data are deliberately left out.
+type loadSummary struct {
+	Bytecode string            `json:"bytecode"`
+	Programs []loadProgram     `json:"programs"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+type loadProgram struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+func summarizeLoadRequest(req *gobpfman.LoadRequest) loadSummary {
+	summary := loadSummary{
+		Metadata: req.GetMetadata(),
+	}
+	switch {
+	case req.GetBytecode().GetImage() != nil:
+		summary.Bytecode = req.GetBytecode().GetImage().GetUrl()
+	default:
+		summary.Bytecode = req.GetBytecode().GetFile()
+	}
+	for _, info := range req.GetInfo() {
+		summary.Programs = append(summary.Programs, loadProgram{
+			Name: info.GetName(),
+			Type: info.GetProgramType().String(),
+		})
+	}
+	return summary
+}
+
+// attachSummary is the audited form of an AttachRequest.
+type attachSummary struct {
+	ProgramId uint32 `json:"programId"`
+	Attach    any    `json:"attach,omitempty"`
+}
+
+func summarizeAttachRequest(req *gobpfman.AttachRequest) attachSummary {
+	return attachSummary{
+		ProgramId: req.GetId(),
+		Attach:    req.GetAttach().GetInfo(),
+	}
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2025 The bpfman Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package internal
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	gobpfman "github.com/bpfman/bpfman/clients/gobpfman/v1"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+type nopCloser struct{ io.Writer }
+
+func (nopCloser) Close() error { return nil }
+
+// auditTestClient implements the mutating bpfman calls needed by the audit
+// tests.
+type auditTestClient struct {
+	gobpfman.BpfmanClient
+	detachErr error
+}
+
+func (c *auditTestClient) Load(_ context.Context, in *gobpfman.LoadRequest, _ ...grpc.CallOption) (*gobpfman.LoadResponse, error) {
+	res := &gobpfman.LoadResponse{}
+	for i, info := range in.Info {
+		res.Programs = append(res.Programs, &gobpfman.LoadResponseInfo{
+			Info:       &gobpfman.ProgramInfo{Name: info.Name},
+			KernelInfo: &gobpfman.KernelProgramInfo{Id: uint32(100 + i)},
+		})
+	}
+	return res, nil
+}
+
+func (c *auditTestClient) Attach(_ context.Context, _ *gobpfman.AttachRequest, _ ...grpc.CallOption) (*gobpfman.AttachResponse, error) {
+	return &gobpfman.AttachResponse{LinkId: 7}, nil
+}
+
+func (c *auditTestClient) Detach(_ context.Context, _ *gobpfman.DetachRequest, _ ...grpc.CallOption) (*gobpfman.DetachResponse, error) {
+	return &gobpfman.DetachResponse{}, c.detachErr
+}
+
+func TestAuditLog(t *testing.T) {
+	var buf bytes.Buffer
+	auditLog := newAuditLog("node-1", nopCloser{&buf})
+	auditLog.now = func() time.Time { return time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC) }
+
+	client := &auditTestClient{detachErr: fmt.Errorf("link not found")}
+	ctx := WithAuditSubject(context.Background(), AuditSubject{
+		Kind:      "BpfApplication",
+		Namespace: "ns",
+		Name:      "app",
+		UID:       "1234",
+	})
+
+	password := "secret"
+	_, err := LoadBpfmanProgram(ctx, client, auditLog, &gobpfman.LoadRequest{
+		Bytecode: &gobpfman.BytecodeLocation{
+			Location: &gobpfman.BytecodeLocation_Image{Image: &gobpfman.BytecodeImage{
+				Url:      "quay.io/bpfman-bytecode/xdp_pass:latest",
+				Password: &password,
+			}},
+		},
+		Info: []*gobpfman.LoadInfo{{Name: "pass", ProgramType: gobpfman.BpfmanProgramType_XDP}},
+	})
+	require.NoError(t, err)
+
+	linkId, err := AttachBpfmanProgram(ctx, client, auditLog, &gobpfman.AttachRequest{
+		Id: 100,
+		Attach: &gobpfman.AttachInfo{Info: &gobpfman.AttachInfo_XdpAttachInfo{
+			XdpAttachInfo: &gobpfman.XDPAttachInfo{Iface: "eth0"},
+		}},
+	})
+	require.NoError(t, err)
+
+	err = DetachBpfmanProgram(ctx, client, auditLog, *linkId)
+	require.Error(t, err)
+
+	require.NotContains(t, buf.String(), password, "registry credentials must not be audited")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 3)
+
+	records := make([]AuditRecord, len(lines))
+	for i, line := range lines {
+		require.NoError(t, json.Unmarshal([]byte(line), &records[i]))
+		require.Equal(t, "node-1", records[i].Node)
+		require.Equal(t, "app", records[i].Application.Name)
+		require.Equal(t, "1234", records[i].Application.UID)
+	}
+
+	require.Equal(t, AuditOpLoad, records[0].Operation)
+	require.Equal(t, []uint32{100}, records[0].ProgramIds)
+	require.Empty(t, records[0].Error)
+
+	require.Equal(t, AuditOpAttach, records[1].Operation)
+	require.Equal(t, uint32(7), *records[1].LinkId)
+	require.Contains(t, lines[1], "eth0")
+
+	require.Equal(t, AuditOpDetach, records[2].Operation)
+	require.Equal(t, uint32(7), *records[2].LinkId)
+	require.Equal(t, "link not found", records[2].Error)
+}
+
+func TestNilAuditLog(t *testing.T) {
+	var auditLog *AuditLog
+	_, err := AttachBpfmanProgram(context.Background(), &auditTestClient{}, auditLog, &gobpfman.AttachRequest{})
+	require.NoError(t, err)
+	require.NoError(t, auditLog.Close())
+}
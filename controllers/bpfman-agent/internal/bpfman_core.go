@@ -93,12 +93,25 @@ func GetBytecode(c client.Client, b *bpfmaniov1alpha1.ByteCodeSelector) (*gobpfm
 	}
 }
 
-func LoadBpfmanProgram(ctx context.Context, bpfmanClient gobpfman.BpfmanClient,
+// LoadBpfmanProgram loads the programs described by loadRequest and records
+// the call in auditLog. If the kernel verifier rejected the programs, the
+// returned error wraps a *VerifierError carrying the verifier log.
+func LoadBpfmanProgram(ctx context.Context, bpfmanClient gobpfman.BpfmanClient, auditLog *AuditLog,
 	loadRequest *gobpfman.LoadRequest) ([]*gobpfman.LoadResponseInfo, error) {
 	var res *gobpfman.LoadResponse
 
 	res, err := bpfmanClient.Load(ctx, loadRequest)
+
+	rec := AuditRecord{Operation: AuditOpLoad, Request: summarizeLoadRequest(loadRequest)}
+	for _, program := range res.GetPrograms() {
+		rec.ProgramIds = append(rec.ProgramIds, program.GetKernelInfo().GetId())
+	}
+	auditLog.record(ctx, rec, err)
+
 	if err != nil {
+		if verr := newVerifierError(err, loadRequest); verr != nil {
+			err = verr
+		}
 		return nil, fmt.Errorf("failed to load bpfProgram via bpfman: %w", err)
 	}
 
@@ -111,8 +124,12 @@ func buildBpfmanUnloadRequest(id uint32) *gobpfman.UnloadRequest {
 	}
 }
 
-func UnloadBpfmanProgram(ctx context.Context, bpfmanClient gobpfman.BpfmanClient, id uint32) error {
-	_, err := bpfmanClient.Unload(ctx, buildBpfmanUnloadRequest(id))
+// UnloadBpfmanProgram unloads the program with the given kernel ID and
+// records the call in auditLog.
+func UnloadBpfmanProgram(ctx context.Context, bpfmanClient gobpfman.BpfmanClient, auditLog *AuditLog, id uint32) error {
+	req := buildBpfmanUnloadRequest(id)
+	_, err := bpfmanClient.Unload(ctx, req)
+	auditLog.record(ctx, AuditRecord{Operation: AuditOpUnload, Request: req, ProgramIds: []uint32{id}}, err)
 	if err != nil {
 		return fmt.Errorf("failed to unload bpfProgram via bpfman: %v",
 			err)
@@ -120,11 +137,24 @@ func UnloadBpfmanProgram(ctx context.Context, bpfmanClient gobpfman.BpfmanClient
 	return nil
 }
 
-func AttachBpfmanProgram(ctx context.Context, bpfmanClient gobpfman.BpfmanClient,
+// AttachBpfmanProgram attaches a loaded program as described by
+// attachRequest and records the call in auditLog.
+func AttachBpfmanProgram(ctx context.Context, bpfmanClient gobpfman.BpfmanClient, auditLog *AuditLog,
 	attachRequest *gobpfman.AttachRequest) (*uint32, error) {
 	var res *gobpfman.AttachResponse
 
 	res, err := bpfmanClient.Attach(ctx, attachRequest)
+
+	rec := AuditRecord{
+		Operation:  AuditOpAttach,
+		Request:    summarizeAttachRequest(attachRequest),
+		ProgramIds: []uint32{attachRequest.GetId()},
+	}
+	if err == nil {
+		rec.LinkId = &res.LinkId
+	}
+	auditLog.record(ctx, rec, err)
+
 	if err != nil {
 		return nil, fmt.Errorf("failed to attach bpfProgram via bpfman: %w", err)
 	}
@@ -138,8 +168,12 @@ func buildBpfmanDetachRequest(id uint32) *gobpfman.DetachRequest {
 	}
 }
 
-func DetachBpfmanProgram(ctx context.Context, bpfmanClient gobpfman.BpfmanClient, id uint32) error {
-	_, err := bpfmanClient.Detach(ctx, buildBpfmanDetachRequest(id))
+// DetachBpfmanProgram detaches the link with the given ID and records the
+// call in auditLog.
+func DetachBpfmanProgram(ctx context.Context, bpfmanClient gobpfman.BpfmanClient, auditLog *AuditLog, id uint32) error {
+	req := buildBpfmanDetachRequest(id)
+	_, err := bpfmanClient.Detach(ctx, req)
+	auditLog.record(ctx, AuditRecord{Operation: AuditOpDetach, Request: req, LinkId: &id}, err)
 	if err != nil {
 		return fmt.Errorf("failed to unload bpfProgram via bpfman: %v",
 			err)
@@ -219,6 +253,48 @@ func ListAllPrograms(ctx context.Context, bpfmanClient gobpfman.BpfmanClient) ([
 	return listResponse.Results, nil
 }
 
+// ListBpfmanProgramsByUUID returns the programs loaded through bpfman with
+// the bpfman.io/uuid metadata set to uuid.
+func ListBpfmanProgramsByUUID(ctx context.Context, bpfmanClient gobpfman.BpfmanClient, uuid string) ([]*gobpfman.ListResponse_ListResult, error) {
+	listOnlyBpfmanPrograms := true
+	listReq := gobpfman.ListRequest{
+		BpfmanProgramsOnly: &listOnlyBpfmanPrograms,
+		MatchMetadata:      map[string]string{internal.UuidMetadataKey: uuid},
+	}
+
+	listResponse, err := bpfmanClient.List(ctx, &listReq)
+	if err != nil {
+		return nil, err
+	}
+
+	return listResponse.Results, nil
+}
+
+// ListBpfmanProgramsByOwner returns the programs loaded through bpfman with
+// bpfman.io/uuid metadata, grouped by UUID. Unlike ListBpfmanPrograms, it
+// keeps every program of an application and skips the programs without
+// the metadata.
+func ListBpfmanProgramsByOwner(ctx context.Context, bpfmanClient gobpfman.BpfmanClient) (map[string][]*gobpfman.ListResponse_ListResult, error) {
+	listOnlyBpfmanPrograms := true
+	listReq := gobpfman.ListRequest{
+		BpfmanProgramsOnly: &listOnlyBpfmanPrograms,
+	}
+
+	listResponse, err := bpfmanClient.List(ctx, &listReq)
+	if err != nil {
+		return nil, err
+	}
+
+	out := map[string][]*gobpfman.ListResponse_ListResult{}
+	for _, result := range listResponse.Results {
+		if uuid, ok := result.GetInfo().GetMetadata()[internal.UuidMetadataKey]; ok {
+			out[uuid] = append(out[uuid], result)
+		}
+	}
+
+	return out, nil
+}
+
 func ListBpfmanAttachments(ctx context.Context, bpfmanClient gobpfman.BpfmanClient, programType internal.ProgramType) (map[string]*gobpfman.ListResponse_ListResult, error) {
 	listOnlyBpfmanPrograms := true
 	listReq := gobpfman.ListRequest{
@@ -17,12 +17,27 @@ limitations under the License.
 package internal
 
 import (
+	"errors"
 	"fmt"
 	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
 
+	bpfmaniov1alpha1 "github.com/bpfman/bpfman-operator/apis/v1alpha1"
+	"github.com/safchain/ethtool"
+	"github.com/vishvananda/netlink"
+	"github.com/vishvananda/netlink/nl"
+	"github.com/vishvananda/netns"
 	v1 "k8s.io/api/core/v1"
 )
 
+// pciDevicesPath is where the PCI devices backing network interfaces are
+// described.
+const pciDevicesPath = "/sys/bus/pci/devices"
+
 func GetPrimaryNodeInterface(ourNode *v1.Node) (string, error) {
 	ifaces, err := net.Interfaces()
 	if err != nil {
@@ -61,3 +76,166 @@ func GetPrimaryNodeInterface(ourNode *v1.Node) (string, error) {
 
 	return "", fmt.Errorf("unable to find Node Interface")
 }
+
+// LinkProperties are the properties of a network interface that
+// InterfaceLinkSelectors match.
+type LinkProperties struct {
+	Name string
+	// Driver is the kernel driver reported by ethtool, or empty if the
+	// interface doesn't report one.
+	Driver string
+	// Type is empty for the types InterfaceLinkSelectors don't know.
+	Type bpfmaniov1alpha1.InterfaceLinkType
+	MAC  net.HardwareAddr
+	MTU  int
+	// SriovRole is empty if the interface is not backed by an SR-IOV
+	// function.
+	SriovRole bpfmaniov1alpha1.SriovRole
+	AltNames  []string
+	Alias     string
+}
+
+// ListLinkProperties returns the properties of the interfaces in the network
+// namespace netnsPath, or in the network namespace of the agent if netnsPath
+// is empty.
+func ListLinkProperties(netnsPath string) ([]LinkProperties, error) {
+	var props []LinkProperties
+	err := inNetns(netnsPath, func() error {
+		links, err := netlink.LinkList()
+		if err != nil {
+			return fmt.Errorf("failed to list links: %w", err)
+		}
+		e, err := ethtool.NewEthtool()
+		if err != nil {
+			return fmt.Errorf("failed to open ethtool: %w", err)
+		}
+		defer e.Close()
+
+		for _, link := range links {
+			attrs := link.Attrs()
+			p := LinkProperties{
+				Name:     attrs.Name,
+				MAC:      attrs.HardwareAddr,
+				MTU:      attrs.MTU,
+				AltNames: attrs.AltNames,
+				Alias:    attrs.Alias,
+			}
+			// Virtual interfaces without a driver, such as lo, fail here.
+			busInfo := ""
+			if info, err := e.DriverInfo(attrs.Name); err == nil {
+				p.Driver = info.Driver
+				busInfo = info.BusInfo
+			}
+			p.SriovRole = sriovRole(busInfo)
+			switch link.Type() {
+			case "veth":
+				p.Type = bpfmaniov1alpha1.InterfaceLinkTypeVeth
+			case "bond":
+				p.Type = bpfmaniov1alpha1.InterfaceLinkTypeBond
+			case "vlan":
+				p.Type = bpfmaniov1alpha1.InterfaceLinkTypeVlan
+			case "device":
+				if attrs.Flags&net.FlagLoopback == 0 && busInfo != "" {
+					p.Type = bpfmaniov1alpha1.InterfaceLinkTypePhysical
+				}
+			}
+			props = append(props, p)
+		}
+		return nil
+	})
+	return props, err
+}
+
+// XdpLinkState is the XDP state of a network interface.
+type XdpLinkState struct {
+	// AttachMode is the mode of the XDP programs of the interface, or empty if
+	// it has none.
+	AttachMode bpfmaniov1alpha1.XdpAttachMode
+	MTU        int
+}
+
+// GetXdpLinkState returns the XDP state of the interface name in the network
+// namespace netnsPath, or in the network namespace of the agent if netnsPath
+// is empty.
+func GetXdpLinkState(netnsPath string, name string) (XdpLinkState, error) {
+	var state XdpLinkState
+	err := inNetns(netnsPath, func() error {
+		link, err := netlink.LinkByName(name)
+		if err != nil {
+			return fmt.Errorf("failed to get link %s: %w", name, err)
+		}
+		attrs := link.Attrs()
+		state.MTU = attrs.MTU
+		if attrs.Xdp == nil {
+			return nil
+		}
+		switch attrs.Xdp.AttachMode {
+		case nl.XDP_ATTACHED_DRV:
+			state.AttachMode = bpfmaniov1alpha1.XdpAttachModeNative
+		case nl.XDP_ATTACHED_SKB:
+			state.AttachMode = bpfmaniov1alpha1.XdpAttachModeGeneric
+		case nl.XDP_ATTACHED_HW:
+			state.AttachMode = bpfmaniov1alpha1.XdpAttachModeOffload
+		}
+		return nil
+	})
+	return state, err
+}
+
+// sriovRole returns the SR-IOV role of the PCI device busInfo, if any. The
+// PCI devices are looked up in sysfs, which unlike /sys/class/net doesn't
+// depend on the network namespace.
+func sriovRole(busInfo string) bpfmaniov1alpha1.SriovRole {
+	if busInfo == "" {
+		return ""
+	}
+	device := filepath.Join(pciDevicesPath, busInfo)
+	if _, err := os.Stat(filepath.Join(device, "physfn")); err == nil {
+		return bpfmaniov1alpha1.SriovRoleVF
+	}
+	totalVfs, err := os.ReadFile(filepath.Join(device, "sriov_totalvfs"))
+	if err != nil {
+		return ""
+	}
+	if n, err := strconv.Atoi(strings.TrimSpace(string(totalVfs))); err == nil && n > 0 {
+		return bpfmaniov1alpha1.SriovRolePF
+	}
+	return ""
+}
+
+// inNetns runs fn in the network namespace netnsPath, or in the current one
+// if netnsPath is empty.
+func inNetns(netnsPath string, fn func() error) (err error) {
+	if netnsPath == "" {
+		return fn()
+	}
+	target, err := netns.GetFromPath(netnsPath)
+	if err != nil {
+		return fmt.Errorf("failed to open network namespace %s: %w", netnsPath, err)
+	}
+	defer target.Close()
+
+	// The network namespace is a property of the thread, so the goroutine
+	// must stay on it until the original namespace is restored.
+	runtime.LockOSThread()
+	origin, err := netns.Get()
+	if err != nil {
+		runtime.UnlockOSThread()
+		return fmt.Errorf("failed to get the current network namespace: %w", err)
+	}
+	defer origin.Close()
+	if err := netns.Set(target); err != nil {
+		runtime.UnlockOSThread()
+		return fmt.Errorf("failed to enter network namespace %s: %w", netnsPath, err)
+	}
+	defer func() {
+		if restoreErr := netns.Set(origin); restoreErr != nil {
+			// Leave the thread locked so that it is terminated with the
+			// goroutine instead of being reused in the wrong namespace.
+			err = errors.Join(err, fmt.Errorf("failed to restore the network namespace: %w", restoreErr))
+			return
+		}
+		runtime.UnlockOSThread()
+	}()
+	return fn()
+}
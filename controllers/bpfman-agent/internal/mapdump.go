@@ -0,0 +1,524 @@
+/*
+Copyright 2025 The bpfman Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package internal
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/cilium/ebpf"
+	"github.com/cilium/ebpf/btf"
+)
+
+const (
+	// DefaultMapEntriesLimit is the page size used when the caller does
+	// not ask for one.
+	DefaultMapEntriesLimit = 100
+	// MaxMapEntriesLimit caps the page size to bound the response size.
+	MaxMapEntriesLimit = 1000
+
+	// The kernel truncates map names to BPF_OBJ_NAME_LEN - 1 bytes.
+	maxMapNameLen = 15
+)
+
+// MapSummary describes a kernel map used by a loaded program.
+type MapSummary struct {
+	Id         uint32 `json:"id"`
+	Name       string `json:"name"`
+	Type       string `json:"type"`
+	KeySize    uint32 `json:"keySize"`
+	ValueSize  uint32 `json:"valueSize"`
+	MaxEntries uint32 `json:"maxEntries"`
+	Flags      uint32 `json:"flags"`
+	// KeyType and ValueType are the BTF type names of the key and value,
+	// when the map carries BTF.
+	KeyType   string `json:"keyType,omitempty"`
+	ValueType string `json:"valueType,omitempty"`
+}
+
+// MapEntry is a single map entry. Key and Value are decoded using the BTF
+// of the map when it is available and are otherwise the same hex strings
+// as RawKey and RawValue. For per-CPU maps Value holds one element per
+// possible CPU.
+type MapEntry struct {
+	Key      any    `json:"key"`
+	Value    any    `json:"value"`
+	RawKey   string `json:"rawKey"`
+	RawValue string `json:"rawValue"`
+}
+
+// MapEntriesPage is one page of entries of a map. Continue is set when
+// there are more entries and is passed back to DumpMapEntries to fetch the
+// next page.
+type MapEntriesPage struct {
+	Map      MapSummary `json:"map"`
+	Entries  []MapEntry `json:"entries"`
+	Continue string     `json:"continue,omitempty"`
+}
+
+// inspectedMap is an open map together with the BTF types used to decode
+// its entries.
+type inspectedMap struct {
+	*ebpf.Map
+	summary   MapSummary
+	keyType   btf.Type
+	valueType btf.Type
+}
+
+func openMap(id uint32) (*inspectedMap, error) {
+	m, err := ebpf.NewMapFromID(ebpf.MapID(id))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open map %d: %w", id, err)
+	}
+
+	info, err := m.Info()
+	if err != nil {
+		m.Close()
+		return nil, fmt.Errorf("failed to get info for map %d: %w", id, err)
+	}
+
+	im := &inspectedMap{
+		Map: m,
+		summary: MapSummary{
+			Id:         id,
+			Name:       info.Name,
+			Type:       info.Type.String(),
+			KeySize:    info.KeySize,
+			ValueSize:  info.ValueSize,
+			MaxEntries: info.MaxEntries,
+			Flags:      info.Flags,
+		},
+	}
+
+	if btfID, ok := info.BTFID(); ok {
+		// Maps without usable BTF are still dumped, just not decoded.
+		if keyType, valueType, err := mapBTFTypes(btfID, info.Name); err == nil {
+			im.keyType, im.valueType = keyType, valueType
+			im.summary.KeyType = typeName(keyType)
+			im.summary.ValueType = typeName(valueType)
+		} else {
+			log.V(1).Info("unable to load map BTF", "MapId", id, "error", err)
+		}
+	}
+
+	return im, nil
+}
+
+// DescribeMap returns the summary of the map with the given kernel ID.
+func DescribeMap(id uint32) (*MapSummary, error) {
+	m, err := openMap(id)
+	if err != nil {
+		return nil, err
+	}
+	defer m.Close()
+	return &m.summary, nil
+}
+
+// DumpMapEntries returns up to limit entries of the map with the given
+// kernel ID, starting after the key encoded in cont. Entries that are
+// deleted while the map is being walked are skipped.
+func DumpMapEntries(id uint32, cont string, limit int) (*MapEntriesPage, error) {
+	if limit <= 0 {
+		limit = DefaultMapEntriesLimit
+	}
+	limit = min(limit, MaxMapEntriesLimit)
+
+	// A nil interface, rather than a nil slice, asks the kernel for the
+	// first key.
+	var start any
+	if cont != "" {
+		prev, err := hex.DecodeString(cont)
+		if err != nil {
+			return nil, fmt.Errorf("invalid continue token: %w", err)
+		}
+		start = prev
+	}
+
+	m, err := openMap(id)
+	if err != nil {
+		return nil, err
+	}
+	defer m.Close()
+
+	if start != nil && len(start.([]byte)) != int(m.summary.KeySize) {
+		return nil, fmt.Errorf("invalid continue token: key must be %d bytes", m.summary.KeySize)
+	}
+
+	page := &MapEntriesPage{
+		Map:     m.summary,
+		Entries: []MapEntry{},
+	}
+
+	key, err := m.NextKeyBytes(start)
+	for err == nil && key != nil && len(page.Entries) < limit {
+		var value []byte
+		value, err = m.LookupBytes(key)
+		if err != nil {
+			break
+		}
+		if value != nil {
+			page.Entries = append(page.Entries, m.entry(key, value))
+		}
+		start = key
+		key, err = m.NextKeyBytes(key)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read entries of map %d: %w", id, err)
+	}
+
+	if key != nil {
+		page.Continue = hex.EncodeToString(start.([]byte))
+	}
+
+	return page, nil
+}
+
+func (m *inspectedMap) entry(key, value []byte) MapEntry {
+	entry := MapEntry{
+		RawKey:   hex.EncodeToString(key),
+		RawValue: hex.EncodeToString(value),
+	}
+	entry.Key = decodeOrHex(m.keyType, key)
+
+	valueSize := int(m.summary.ValueSize)
+	if valueSize == 0 || len(value) <= valueSize {
+		entry.Value = decodeOrHex(m.valueType, value)
+		return entry
+	}
+
+	// Per-CPU values are laid out one after the other, each rounded up to
+	// a multiple of eight bytes.
+	stride := (valueSize + 7) &^ 7
+	values := []any{}
+	for off := 0; off+valueSize <= len(value); off += stride {
+		values = append(values, decodeOrHex(m.valueType, value[off:off+valueSize]))
+	}
+	entry.Value = values
+	return entry
+}
+
+// mapBTFTypes finds the key and value types of the map called name in the
+// BTF object btfID. BTF-defined maps are described by a variable in the
+// ".maps" section whose type has "key" and "value" pointer members. Global
+// data maps, such as "prog.rodata", hold the data section of the same
+// name and are keyed by a single index.
+func mapBTFTypes(btfID btf.ID, name string) (btf.Type, btf.Type, error) {
+	handle, err := btf.NewHandleFromID(btfID)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer handle.Close()
+
+	spec, err := handle.Spec(nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for typ, err := range spec.All() {
+		if err != nil {
+			return nil, nil, err
+		}
+		ds, ok := typ.(*btf.Datasec)
+		if !ok {
+			continue
+		}
+
+		if ds.Name != ".maps" {
+			if strings.HasPrefix(ds.Name, ".") && strings.HasSuffix(name, ds.Name) {
+				return &btf.Int{Name: "__u32", Size: 4}, ds, nil
+			}
+			continue
+		}
+
+		for _, vsi := range ds.Vars {
+			v, ok := vsi.Type.(*btf.Var)
+			if !ok || !mapNameMatches(name, v.Name) {
+				continue
+			}
+			def, ok := btf.UnderlyingType(v.Type).(*btf.Struct)
+			if !ok {
+				continue
+			}
+			var keyType, valueType btf.Type
+			for _, member := range def.Members {
+				ptr, ok := member.Type.(*btf.Pointer)
+				if !ok {
+					continue
+				}
+				switch member.Name {
+				case "key":
+					keyType = ptr.Target
+				case "value":
+					valueType = ptr.Target
+				}
+			}
+			return keyType, valueType, nil
+		}
+	}
+
+	return nil, nil, fmt.Errorf("no BTF description found for map %q", name)
+}
+
+// mapNameMatches reports whether the kernel map name, which may have been
+// truncated, refers to the variable called varName.
+func mapNameMatches(name, varName string) bool {
+	if len(name) >= maxMapNameLen {
+		return strings.HasPrefix(varName, name)
+	}
+	return name == varName
+}
+
+func typeName(typ btf.Type) string {
+	if typ == nil {
+		return ""
+	}
+	if name := typ.TypeName(); name != "" {
+		return name
+	}
+	return fmt.Sprintf("%T", btf.UnderlyingType(typ))
+}
+
+// decodeOrHex decodes data as typ, falling back to a hex string when no type
+// is known or the data does not match it.
+func decodeOrHex(typ btf.Type, data []byte) any {
+	if typ != nil {
+		if v, err := decodeBTF(typ, data); err == nil {
+			return v
+		}
+	}
+	return hex.EncodeToString(data)
+}
+
+// decodeBTF converts data, laid out as the BTF type typ in native byte
+// order, into a value that marshals naturally to JSON. Structs, unions and
+// data sections become objects keyed by member name, arrays become lists
+// except for character arrays which become strings, and enums are decoded
+// to the name of their value when it has one.
+func decodeBTF(typ btf.Type, data []byte) (any, error) {
+	switch t := btf.UnderlyingType(typ).(type) {
+	case *btf.Int:
+		b, err := field(data, 0, t.Size)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case t.Encoding == btf.Bool:
+			return readUint(b) != 0, nil
+		case t.Size > 8:
+			return "0x" + hex.EncodeToString(b), nil
+		case t.Encoding == btf.Signed:
+			return readInt(b), nil
+		default:
+			return readUint(b), nil
+		}
+
+	case *btf.Enum:
+		b, err := field(data, 0, t.Size)
+		if err != nil {
+			return nil, err
+		}
+		v := readUint(b)
+		for _, ev := range t.Values {
+			if ev.Value == v {
+				return ev.Name, nil
+			}
+		}
+		if t.Signed {
+			return readInt(b), nil
+		}
+		return v, nil
+
+	case *btf.Pointer:
+		b, err := field(data, 0, 8)
+		if err != nil {
+			return nil, err
+		}
+		return fmt.Sprintf("0x%x", readUint(b)), nil
+
+	case *btf.Float:
+		b, err := field(data, 0, t.Size)
+		if err != nil {
+			return nil, err
+		}
+		switch t.Size {
+		case 4:
+			return math.Float32frombits(binary.NativeEndian.Uint32(b)), nil
+		case 8:
+			return math.Float64frombits(binary.NativeEndian.Uint64(b)), nil
+		default:
+			return "0x" + hex.EncodeToString(b), nil
+		}
+
+	case *btf.Array:
+		elemSize, err := btf.Sizeof(t.Type)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := field(data, 0, uint32(elemSize)*t.Nelems); err != nil {
+			return nil, err
+		}
+		if elem, ok := btf.UnderlyingType(t.Type).(*btf.Int); ok && elem.Size == 1 &&
+			(elem.Encoding == btf.Char || elem.Name == "char") {
+			s := data[:t.Nelems]
+			if i := bytes.IndexByte(s, 0); i >= 0 {
+				s = s[:i]
+			}
+			return string(s), nil
+		}
+		values := make([]any, 0, t.Nelems)
+		for i := uint32(0); i < t.Nelems; i++ {
+			v, err := decodeBTF(t.Type, data[i*uint32(elemSize):])
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, v)
+		}
+		return values, nil
+
+	case *btf.Struct:
+		return decodeMembers(t.Members, data)
+
+	case *btf.Union:
+		return decodeMembers(t.Members, data)
+
+	case *btf.Datasec:
+		out := map[string]any{}
+		for _, vsi := range t.Vars {
+			v, ok := vsi.Type.(*btf.Var)
+			if !ok {
+				continue
+			}
+			b, err := field(data, vsi.Offset, vsi.Size)
+			if err != nil {
+				return nil, err
+			}
+			if out[v.Name], err = decodeBTF(v.Type, b); err != nil {
+				return nil, err
+			}
+		}
+		return out, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported BTF type %T", t)
+	}
+}
+
+func decodeMembers(members []btf.Member, data []byte) (map[string]any, error) {
+	out := map[string]any{}
+	for _, member := range members {
+		var value any
+		if member.BitfieldSize > 0 {
+			v, err := readBitfield(data, uint32(member.Offset), uint32(member.BitfieldSize))
+			if err != nil {
+				return nil, err
+			}
+			value = v
+		} else {
+			if member.Offset%8 != 0 {
+				return nil, fmt.Errorf("member %q is not byte aligned", member.Name)
+			}
+			off := member.Offset.Bytes()
+			if off > uint32(len(data)) {
+				return nil, fmt.Errorf("member %q is out of bounds", member.Name)
+			}
+			v, err := decodeBTF(member.Type, data[off:])
+			if err != nil {
+				return nil, err
+			}
+			value = v
+		}
+
+		// Members of anonymous structs and unions are promoted to the
+		// enclosing object, as they are in C.
+		if member.Name == "" {
+			if nested, ok := value.(map[string]any); ok {
+				for k, v := range nested {
+					out[k] = v
+				}
+				continue
+			}
+		}
+		out[member.Name] = value
+	}
+	return out, nil
+}
+
+func field(data []byte, off, size uint32) ([]byte, error) {
+	if uint64(off)+uint64(size) > uint64(len(data)) {
+		return nil, fmt.Errorf("need %d bytes at offset %d, have %d", size, off, len(data))
+	}
+	return data[off : off+size], nil
+}
+
+func readUint(b []byte) uint64 {
+	switch len(b) {
+	case 1:
+		return uint64(b[0])
+	case 2:
+		return uint64(binary.NativeEndian.Uint16(b))
+	case 4:
+		return uint64(binary.NativeEndian.Uint32(b))
+	case 8:
+		return binary.NativeEndian.Uint64(b)
+	default:
+		var buf [8]byte
+		copy(buf[:], b)
+		return binary.NativeEndian.Uint64(buf[:])
+	}
+}
+
+func readInt(b []byte) int64 {
+	switch len(b) {
+	case 1:
+		return int64(int8(b[0]))
+	case 2:
+		return int64(int16(binary.NativeEndian.Uint16(b)))
+	case 4:
+		return int64(int32(binary.NativeEndian.Uint32(b)))
+	default:
+		return int64(readUint(b))
+	}
+}
+
+// readBitfield extracts size bits starting at bit offset off. Bitfields are
+// only decoded on little endian hosts, where bit offsets count from the
+// least significant bit of the containing bytes.
+func readBitfield(data []byte, off, size uint32) (uint64, error) {
+	if binary.NativeEndian.Uint16([]byte{1, 0}) != 1 {
+		return 0, fmt.Errorf("bitfields are not supported on big endian hosts")
+	}
+	if size > 64 {
+		return 0, fmt.Errorf("bitfield of %d bits is too large", size)
+	}
+	first, last := off/8, (off+size+7)/8
+	b, err := field(data, first, last-first)
+	if err != nil {
+		return 0, err
+	}
+	if len(b) > 8 {
+		return 0, fmt.Errorf("bitfield spans more than 8 bytes")
+	}
+	v := readUint(b) >> (off % 8)
+	if size < 64 {
+		v &= (1 << size) - 1
+	}
+	return v, nil
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2025 The bpfman Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package internal
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/cilium/ebpf/btf"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeBTF(t *testing.T) {
+	u8 := &btf.Int{Name: "__u8", Size: 1}
+	u32 := &btf.Int{Name: "__u32", Size: 4}
+	s16 := &btf.Int{Name: "__s16", Size: 2, Encoding: btf.Signed}
+	char := &btf.Int{Name: "char", Size: 1, Encoding: btf.Char}
+	action := &btf.Enum{Name: "action", Size: 4, Values: []btf.EnumValue{
+		{Name: "PASS", Value: 2},
+		{Name: "DROP", Value: 1},
+	}}
+
+	// struct stats {
+	//     __u32 packets;
+	//     __s16 delta;
+	//     __u8 flag_a : 1, flag_b : 3;
+	//     char comm[4];
+	//     enum action last;
+	//     union { __u32 raw; __u8 bytes[4]; };
+	// };
+	stats := &btf.Struct{
+		Name: "stats",
+		Size: 20,
+		Members: []btf.Member{
+			{Name: "packets", Type: u32, Offset: 0},
+			{Name: "delta", Type: s16, Offset: 32},
+			{Name: "flag_a", Type: u8, Offset: 48, BitfieldSize: 1},
+			{Name: "flag_b", Type: u8, Offset: 49, BitfieldSize: 3},
+			{Name: "comm", Type: &btf.Array{Type: char, Nelems: 4}, Offset: 56},
+			{Name: "last", Type: &btf.Typedef{Name: "action_t", Type: action}, Offset: 96},
+			{Name: "", Type: &btf.Union{Size: 4, Members: []btf.Member{
+				{Name: "raw", Type: u32},
+				{Name: "bytes", Type: &btf.Array{Type: u8, Nelems: 4}},
+			}}, Offset: 128},
+		},
+	}
+
+	data := make([]byte, 20)
+	binary.NativeEndian.PutUint32(data[0:], 42)
+	binary.NativeEndian.PutUint16(data[4:], uint16(0xfffe)) // -2
+	data[6] = 0b1011                                        // flag_a=1, flag_b=5
+	copy(data[7:], "ab\x00\x00")
+	binary.NativeEndian.PutUint32(data[12:], 2)
+	binary.NativeEndian.PutUint32(data[16:], 0x04030201)
+
+	_, err := decodeBTF(stats, data[:10])
+	require.Error(t, err, "short data must not decode")
+
+	v, err := decodeBTF(stats, data)
+	require.NoError(t, err)
+
+	if binary.NativeEndian.Uint16([]byte{1, 0}) != 1 {
+		t.Skip("bitfields are only decoded on little endian hosts")
+	}
+	require.Equal(t, map[string]any{
+		"packets": uint64(42),
+		"delta":   int64(-2),
+		"flag_a":  uint64(1),
+		"flag_b":  uint64(5),
+		"comm":    "ab",
+		"last":    "PASS",
+		"raw":     uint64(0x04030201),
+		"bytes":   []any{uint64(1), uint64(2), uint64(3), uint64(4)},
+	}, v)
+}
+
+func TestDecodeBTFDatasec(t *testing.T) {
+	u32 := &btf.Int{Name: "__u32", Size: 4}
+	ds := &btf.Datasec{Name: ".bss", Size: 8, Vars: []btf.VarSecinfo{
+		{Type: &btf.Var{Name: "counter", Type: u32}, Offset: 0, Size: 4},
+		{Type: &btf.Var{Name: "enabled", Type: &btf.Int{Name: "bool", Size: 1, Encoding: btf.Bool}}, Offset: 4, Size: 1},
+	}}
+
+	data := make([]byte, 8)
+	binary.NativeEndian.PutUint32(data, 7)
+	data[4] = 1
+
+	v, err := decodeBTF(ds, data)
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{"counter": uint64(7), "enabled": true}, v)
+}
+
+func TestPerCPUEntry(t *testing.T) {
+	m := &inspectedMap{
+		summary:   MapSummary{KeySize: 4, ValueSize: 4},
+		keyType:   &btf.Int{Name: "__u32", Size: 4},
+		valueType: &btf.Int{Name: "__u32", Size: 4},
+	}
+
+	key := make([]byte, 4)
+	binary.NativeEndian.PutUint32(key, 3)
+
+	// Two CPUs, each value padded to eight bytes.
+	value := make([]byte, 16)
+	binary.NativeEndian.PutUint32(value[0:], 10)
+	binary.NativeEndian.PutUint32(value[8:], 20)
+
+	entry := m.entry(key, value)
+	require.Equal(t, uint64(3), entry.Key)
+	require.Equal(t, []any{uint64(10), uint64(20)}, entry.Value)
+
+	// Without BTF the key and value are hex encoded.
+	m.keyType, m.valueType = nil, nil
+	entry = m.entry(key, value[:4])
+	require.Equal(t, entry.RawKey, entry.Key)
+	require.Equal(t, entry.RawValue, entry.Value)
+}
+
+func TestMapNameMatches(t *testing.T) {
+	require.True(t, mapNameMatches("xdp_stats_map", "xdp_stats_map"))
+	require.False(t, mapNameMatches("xdp_stats", "xdp_stats_map"))
+	// Kernel map names are truncated to 15 characters.
+	require.True(t, mapNameMatches("tc_ingress_coun", "tc_ingress_counters"))
+}
@@ -18,10 +18,12 @@ package testutils
 
 import (
 	"context"
-	"fmt"
+	"sort"
 
 	gobpfman "github.com/bpfman/bpfman/clients/gobpfman/v1"
 	grpc "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type BpfmanClientFake struct {
@@ -31,7 +33,14 @@ type BpfmanClientFake struct {
 	GetRequests          map[int]*gobpfman.GetRequest
 	Programs             map[int]*gobpfman.GetResponse
 	Links                map[int]bool
+	AttachRequests       map[int]*gobpfman.AttachRequest
 	PullBytecodeRequests map[int]*gobpfman.PullBytecodeRequest
+	// LoadErr, when set, is returned by Load instead of loading the
+	// programs.
+	LoadErr error
+	// GetErr, when set, is returned by Get, e.g., as if bpfman was
+	// unavailable.
+	GetErr error
 }
 
 func NewBpfmanClientFake() *BpfmanClientFake {
@@ -42,6 +51,7 @@ func NewBpfmanClientFake() *BpfmanClientFake {
 		GetRequests:          map[int]*gobpfman.GetRequest{},
 		Programs:             map[int]*gobpfman.GetResponse{},
 		Links:                map[int]bool{},
+		AttachRequests:       map[int]*gobpfman.AttachRequest{},
 		PullBytecodeRequests: map[int]*gobpfman.PullBytecodeRequest{},
 	}
 }
@@ -54,6 +64,7 @@ func NewBpfmanClientFakeWithPrograms(programs map[int]*gobpfman.GetResponse) *Bp
 		GetRequests:          map[int]*gobpfman.GetRequest{},
 		Programs:             programs,
 		Links:                map[int]bool{},
+		AttachRequests:       map[int]*gobpfman.AttachRequest{},
 		PullBytecodeRequests: map[int]*gobpfman.PullBytecodeRequest{},
 	}
 }
@@ -61,6 +72,9 @@ func NewBpfmanClientFakeWithPrograms(programs map[int]*gobpfman.GetResponse) *Bp
 var currentID = 1000
 
 func (b *BpfmanClientFake) Load(ctx context.Context, in *gobpfman.LoadRequest, opts ...grpc.CallOption) (*gobpfman.LoadResponse, error) {
+	if b.LoadErr != nil {
+		return nil, b.LoadErr
+	}
 
 	loadResponse := &gobpfman.LoadResponse{}
 	programs := make([]*gobpfman.LoadResponseInfo, 0)
@@ -71,7 +85,8 @@ func (b *BpfmanClientFake) Load(ctx context.Context, in *gobpfman.LoadRequest, o
 		progName := prog.Name
 		loadResponseInfo := &gobpfman.LoadResponseInfo{
 			Info: &gobpfman.ProgramInfo{
-				Name: progName,
+				Name:     progName,
+				Metadata: in.Metadata,
 			},
 			KernelInfo: &gobpfman.KernelProgramInfo{
 				Id:   uint32(currentID),
@@ -102,13 +117,16 @@ func loadRequestToGetResult(info *gobpfman.LoadResponseInfo) *gobpfman.GetRespon
 }
 
 func (b *BpfmanClientFake) Get(ctx context.Context, in *gobpfman.GetRequest, opts ...grpc.CallOption) (*gobpfman.GetResponse, error) {
+	if b.GetErr != nil {
+		return nil, b.GetErr
+	}
 	if b.Programs[int(in.Id)] != nil {
 		return &gobpfman.GetResponse{
 			Info:       b.Programs[int(in.Id)].Info,
 			KernelInfo: b.Programs[int(in.Id)].KernelInfo,
 		}, nil
 	} else {
-		return nil, fmt.Errorf("requested program does not exist")
+		return nil, status.Error(codes.NotFound, "requested program does not exist")
 	}
 }
 
@@ -116,8 +134,37 @@ func (b *BpfmanClientFake) PullBytecode(ctx context.Context, in *gobpfman.PullBy
 	return &gobpfman.PullBytecodeResponse{}, nil
 }
 
+// List returns the programs matching the metadata of the request, by ID.
+// Programs without Info are kernel programs not loaded through bpfman.
 func (b *BpfmanClientFake) List(ctx context.Context, in *gobpfman.ListRequest, opts ...grpc.CallOption) (*gobpfman.ListResponse, error) {
-	return &gobpfman.ListResponse{}, nil
+	b.ListRequests = append(b.ListRequests, in)
+
+	ids := make([]int, 0, len(b.Programs))
+	for id := range b.Programs {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	response := &gobpfman.ListResponse{}
+	for _, id := range ids {
+		program := b.Programs[id]
+		if program.Info == nil && (in.GetBpfmanProgramsOnly() || len(in.MatchMetadata) > 0) {
+			continue
+		}
+		matches := true
+		for k, v := range in.MatchMetadata {
+			if program.Info.GetMetadata()[k] != v {
+				matches = false
+			}
+		}
+		if matches {
+			response.Results = append(response.Results, &gobpfman.ListResponse_ListResult{
+				Info:       program.Info,
+				KernelInfo: program.KernelInfo,
+			})
+		}
+	}
+	return response, nil
 }
 
 var currentLinkID = 1000
@@ -125,6 +172,7 @@ var currentLinkID = 1000
 func (b *BpfmanClientFake) Attach(ctx context.Context, in *gobpfman.AttachRequest, opts ...grpc.CallOption) (*gobpfman.AttachResponse, error) {
 	currentLinkID++
 	b.Links[currentLinkID] = true
+	b.AttachRequests[currentLinkID] = in
 	b.Programs[int(in.Id)].Info.Links = append(b.Programs[int(in.Id)].Info.Links, uint32(currentLinkID))
 	return &gobpfman.AttachResponse{
 		LinkId: uint32(currentLinkID),
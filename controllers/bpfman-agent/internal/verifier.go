@@ -0,0 +1,108 @@
+/*
+Copyright 2025 The bpfman Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package internal
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	gobpfman "github.com/bpfman/bpfman/clients/gobpfman/v1"
+	"google.golang.org/grpc/status"
+)
+
+// verifierOutputMarker precedes the verifier log in the error returned by
+// bpfman when the BPF_PROG_LOAD syscall fails.
+const verifierOutputMarker = "Verifier output:"
+
+// VerifierError is returned by LoadBpfmanProgram when the kernel verifier
+// rejected the programs of a load request.
+type VerifierError struct {
+	// Programs are the names of the programs the verifier log applies to.
+	// bpfman does not always report which program was rejected and, because
+	// the load is all or nothing, every program of the request is listed in
+	// that case.
+	Programs []string
+	// Log is the verifier output reported by bpfman.
+	Log string
+
+	err error
+}
+
+func (e *VerifierError) Error() string {
+	return e.err.Error()
+}
+
+func (e *VerifierError) Unwrap() error {
+	return e.err
+}
+
+// newVerifierError returns a VerifierError if err, returned by the bpfman
+// Load call for loadRequest, carries a verifier log, and nil otherwise.
+func newVerifierError(err error, loadRequest *gobpfman.LoadRequest) *VerifierError {
+	st, ok := status.FromError(err)
+	if !ok {
+		return nil
+	}
+
+	// bpfman reports the verifier log in the status message. Include any
+	// debug details attached to the status as well.
+	detail := st.Message()
+	for _, d := range st.Details() {
+		if debug, ok := d.(interface{ GetDetail() string }); ok && debug.GetDetail() != "" {
+			detail += "\n" + debug.GetDetail()
+		}
+	}
+
+	prefix, verifierLog, found := strings.Cut(detail, verifierOutputMarker)
+	if !found {
+		return nil
+	}
+
+	verr := &VerifierError{
+		Log: strings.TrimSpace(verifierLog),
+		err: err,
+	}
+	for _, info := range loadRequest.GetInfo() {
+		if strings.Contains(prefix, info.GetName()) {
+			verr.Programs = append(verr.Programs, info.GetName())
+		}
+	}
+	if len(verr.Programs) == 0 {
+		for _, info := range loadRequest.GetInfo() {
+			verr.Programs = append(verr.Programs, info.GetName())
+		}
+	}
+	return verr
+}
+
+// TruncateVerifierLog returns at most limit bytes of verifierLog. The end of
+// the log, which explains why the program was rejected, is kept.
+func TruncateVerifierLog(verifierLog string, limit int) string {
+	const ellipsis = "...\n"
+	if len(verifierLog) <= limit {
+		return verifierLog
+	}
+	if limit <= len(ellipsis) {
+		return ""
+	}
+	tail := verifierLog[len(verifierLog)-(limit-len(ellipsis)):]
+	// Don't start in the middle of a multi-byte character.
+	for len(tail) > 0 && !utf8.RuneStart(tail[0]) {
+		tail = tail[1:]
+	}
+	return ellipsis + tail
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2025 The bpfman Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	gobpfman "github.com/bpfman/bpfman/clients/gobpfman/v1"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestNewVerifierError(t *testing.T) {
+	req := &gobpfman.LoadRequest{Info: []*gobpfman.LoadInfo{{Name: "xdp_pass"}, {Name: "xdp_drop"}}}
+
+	// Errors without a verifier log are not verifier errors.
+	require.Nil(t, newVerifierError(fmt.Errorf("connection refused"), req))
+	require.Nil(t, newVerifierError(status.Error(codes.Aborted, "image not found"), req))
+
+	// The rejected program is identified when bpfman names it.
+	verr := newVerifierError(status.Error(codes.Aborted,
+		"failed to load xdp_drop: the BPF_PROG_LOAD syscall failed. Verifier output: 0: R1=ctx()\ninvalid access\n"), req)
+	require.NotNil(t, verr)
+	require.Equal(t, []string{"xdp_drop"}, verr.Programs)
+	require.Equal(t, "0: R1=ctx()\ninvalid access", verr.Log)
+
+	// Otherwise the log applies to every program of the request.
+	verr = newVerifierError(status.Error(codes.Aborted,
+		"the BPF_PROG_LOAD syscall failed. Verifier output: invalid access"), req)
+	require.NotNil(t, verr)
+	require.Equal(t, []string{"xdp_pass", "xdp_drop"}, verr.Programs)
+
+	// LoadBpfmanProgram wraps the verifier error.
+	err := fmt.Errorf("failed to load bpfProgram via bpfman: %w", verr)
+	var target *VerifierError
+	require.True(t, errors.As(err, &target))
+	require.Equal(t, codes.Aborted, status.Code(errors.Unwrap(target)))
+}
+
+func TestTruncateVerifierLog(t *testing.T) {
+	require.Equal(t, "short", TruncateVerifierLog("short", 10))
+	require.Equal(t, "...\n6789", TruncateVerifierLog("0123456789", 8))
+	// Truncation doesn't split multi-byte characters.
+	require.Equal(t, "...\nb", TruncateVerifierLog("aaaaéb", 6))
+}
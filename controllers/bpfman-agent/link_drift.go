@@ -0,0 +1,373 @@
+/*
+Copyright 2025 The bpfman Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bpfmanagent
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	bpfmaniov1alpha1 "github.com/bpfman/bpfman-operator/apis/v1alpha1"
+	bpfmanagentinternal "github.com/bpfman/bpfman-operator/controllers/bpfman-agent/internal"
+	"github.com/bpfman/bpfman-operator/internal"
+	gobpfman "github.com/bpfman/bpfman/clients/gobpfman/v1"
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+
+const (
+	// linkDriftCorrectedReason is the reason of the events recorded by the
+	// application reconcilers when a missing link is re-attached.
+	linkDriftCorrectedReason = "DriftCorrected"
+	// linkDriftDetectedReason is the reason of the events recorded when
+	// a link is found missing.
+	linkDriftDetectedReason = "DriftDetected"
+
+	// linkDriftRepairQueue is the number of pending repairs of each kind of
+	// application. Reconciles cover all the applications of a kind, so
+	// further repairs are dropped while the queue is full.
+	linkDriftRepairQueue = 16
+)
+
+var linkDriftTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "bpfman_agent_link_drift_total",
+	Help: "Number of links found missing on the node, by application.",
+}, []string{"kind", "namespace", "name"})
+
+func init() {
+	metrics.Registry.MustRegister(linkDriftTotal)
+}
+
+// LinkDriftDetector periodically verifies that the links recorded in the
+// BpfApplicationStates of this node still exist in bpfman, e.g., after a
+// link was detached by another tool or an interface was recreated. In
+// repair mode, the application reconcilers are triggered so that they
+// re-attach the missing links.
+type LinkDriftDetector struct {
+	client.Client
+	BpfmanClient gobpfman.BpfmanClient
+	NodeName     string
+	Interval     time.Duration
+	Repair       bool
+	Recorder     record.EventRecorder
+	Logger       logr.Logger
+
+	clRepairs chan event.GenericEvent
+	nsRepairs chan event.GenericEvent
+	// reported holds the IDs of the links found missing by the last check,
+	// which are only counted and reported once.
+	reported map[uint32]bool
+}
+
+// NewLinkDriftDetector returns a LinkDriftDetector verifying the links of
+// nodeName every interval.
+func NewLinkDriftDetector(c client.Client, bpfmanClient gobpfman.BpfmanClient, recorder record.EventRecorder,
+	nodeName string, interval time.Duration, repair bool) *LinkDriftDetector {
+	return &LinkDriftDetector{
+		Client:       c,
+		BpfmanClient: bpfmanClient,
+		NodeName:     nodeName,
+		Interval:     interval,
+		Repair:       repair,
+		Recorder:     recorder,
+		Logger:       ctrl.Log.WithName("link-drift"),
+		clRepairs:    make(chan event.GenericEvent, linkDriftRepairQueue),
+		nsRepairs:    make(chan event.GenericEvent, linkDriftRepairQueue),
+		reported:     map[uint32]bool{},
+	}
+}
+
+// ClusterRepairs returns the ClusterBpfApplications whose links must be
+// re-attached.
+func (d *LinkDriftDetector) ClusterRepairs() <-chan event.GenericEvent {
+	return d.clRepairs
+}
+
+// NamespacedRepairs returns the BpfApplications whose links must be
+// re-attached.
+func (d *LinkDriftDetector) NamespacedRepairs() <-chan event.GenericEvent {
+	return d.nsRepairs
+}
+
+// Start verifies the links every interval until ctx is cancelled.
+func (d *LinkDriftDetector) Start(ctx context.Context) error {
+	ticker := time.NewTicker(d.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := d.Check(ctx); err != nil {
+				d.Logger.Error(err, "failed to verify links")
+			}
+		}
+	}
+}
+
+// NeedLeaderElection returns false because every agent verifies the links
+// of its own node.
+func (d *LinkDriftDetector) NeedLeaderElection() bool {
+	return false
+}
+
+// Check verifies the links of the BpfApplicationStates of this node once.
+// Links still missing since the previous check are not counted or reported
+// again, but their repair is triggered again.
+func (d *LinkDriftDetector) Check(ctx context.Context) error {
+	opts := []client.ListOption{client.MatchingLabels{internal.K8sHostLabel: d.NodeName}}
+	missingLinks := map[uint32]bool{}
+
+	clStates := &bpfmaniov1alpha1.ClusterBpfApplicationStateList{}
+	if err := d.List(ctx, clStates, opts...); err != nil {
+		return fmt.Errorf("failed to list ClusterBpfApplicationStates: %w", err)
+	}
+	nsStates := &bpfmaniov1alpha1.BpfApplicationStateList{}
+	if err := d.List(ctx, nsStates, opts...); err != nil {
+		return fmt.Errorf("failed to list BpfApplicationStates: %w", err)
+	}
+
+	// The links of all the programs are looked up before anything is
+	// reported, so that the check is skipped as a whole when bpfman can't be
+	// reached.
+	var programIds []uint32
+	for _, state := range clStates.Items {
+		for _, program := range state.Status.Programs {
+			if program.ProgramId != nil {
+				programIds = append(programIds, *program.ProgramId)
+			}
+		}
+	}
+	for _, state := range nsStates.Items {
+		for _, program := range state.Status.Programs {
+			if program.ProgramId != nil {
+				programIds = append(programIds, *program.ProgramId)
+			}
+		}
+	}
+	links, err := d.programLinks(ctx, programIds)
+	if err != nil {
+		return err
+	}
+
+	for i := range clStates.Items {
+		state := &clStates.Items[i]
+		app := appOwnerName(state)
+		missing, detected := 0, 0
+		for p := range state.Status.Programs {
+			program := &state.Status.Programs[p]
+			m, n := d.checkProgram(state, &program.BpfProgramStateCommon, clAttachInfoStates(program), links, missingLinks)
+			missing += m
+			detected += n
+		}
+		if detected > 0 {
+			linkDriftTotal.WithLabelValues(clusterBpfApplicationKind, "", app).Add(float64(detected))
+		}
+		if missing > 0 {
+			if d.Repair {
+				d.queueRepair(d.clRepairs, &bpfmaniov1alpha1.ClusterBpfApplication{
+					ObjectMeta: metav1.ObjectMeta{Name: app},
+				})
+			}
+		}
+	}
+
+	for i := range nsStates.Items {
+		state := &nsStates.Items[i]
+		app := appOwnerName(state)
+		missing, detected := 0, 0
+		for p := range state.Status.Programs {
+			program := &state.Status.Programs[p]
+			m, n := d.checkProgram(state, &program.BpfProgramStateCommon, nsAttachInfoStates(program), links, missingLinks)
+			missing += m
+			detected += n
+		}
+		if detected > 0 {
+			linkDriftTotal.WithLabelValues(bpfApplicationKind, state.Namespace, app).Add(float64(detected))
+		}
+		if missing > 0 {
+			if d.Repair {
+				d.queueRepair(d.nsRepairs, &bpfmaniov1alpha1.BpfApplication{
+					ObjectMeta: metav1.ObjectMeta{Namespace: state.Namespace, Name: app},
+				})
+			}
+		}
+	}
+	d.reported = missingLinks
+	return nil
+}
+
+// checkProgram adds the IDs of the attached links of program that are
+// missing in bpfman to missingLinks, and records an event on state for
+// those that were not missing at the previous check. It returns the number
+// of missing links, and of those newly detected.
+func (d *LinkDriftDetector) checkProgram(state client.Object,
+	program *bpfmaniov1alpha1.BpfProgramStateCommon, attachInfos []*bpfmaniov1alpha1.AttachInfoStateCommon,
+	links map[uint32]map[uint32]bool, missingLinks map[uint32]bool) (int, int) {
+	if program.ProgramId == nil {
+		return 0, 0
+	}
+	programLinks := links[*program.ProgramId]
+
+	missing, detected := 0, 0
+	for _, attachInfo := range attachInfos {
+		if !attachInfo.ShouldAttach || attachInfo.LinkId == nil || programLinks[*attachInfo.LinkId] {
+			continue
+		}
+		missing++
+		missingLinks[*attachInfo.LinkId] = true
+		if d.reported[*attachInfo.LinkId] {
+			continue
+		}
+		detected++
+		d.Logger.Info("Link is missing", "State", state.GetName(), "Program", program.Name,
+			"LinkId", *attachInfo.LinkId, "Repair", d.Repair)
+		message := fmt.Sprintf("Link %d of program %s is missing on node %s", *attachInfo.LinkId, program.Name, d.NodeName)
+		if d.Repair {
+			message += ", re-attaching it"
+		}
+		d.Recorder.Event(state, v1.EventTypeWarning, linkDriftDetectedReason, message)
+	}
+	return missing, detected
+}
+
+// programLinks returns the links bpfman reports for each of programIds. A
+// program that is gone has no links, and reloading it is left to the
+// reconcilers. Any other error is returned, e.g., while bpfman restarts, so
+// that its links are not reported as missing.
+func (d *LinkDriftDetector) programLinks(ctx context.Context, programIds []uint32) (map[uint32]map[uint32]bool, error) {
+	links := map[uint32]map[uint32]bool{}
+	for _, programId := range programIds {
+		if _, ok := links[programId]; ok {
+			continue
+		}
+		programLinks := map[uint32]bool{}
+		result, err := bpfmanagentinternal.GetBpfmanProgramById(ctx, d.BpfmanClient, programId)
+		if err != nil && status.Code(err) != codes.NotFound {
+			return nil, fmt.Errorf("failed to get program %d: %w", programId, err)
+		}
+		for _, id := range result.GetInfo().GetLinks() {
+			programLinks[id] = true
+		}
+		links[programId] = programLinks
+	}
+	return links, nil
+}
+
+// queueRepair triggers a reconcile of app unless enough are pending.
+func (d *LinkDriftDetector) queueRepair(repairs chan event.GenericEvent, app client.Object) {
+	select {
+	case repairs <- event.GenericEvent{Object: app}:
+	default:
+	}
+}
+
+// appOwnerName returns the name of the application owning state.
+func appOwnerName(state client.Object) string {
+	if owner := metav1.GetControllerOf(state); owner != nil {
+		return owner.Name
+	}
+	return state.GetName()
+}
+
+// clAttachInfoStates returns the attach points of a cluster scoped program.
+func clAttachInfoStates(program *bpfmaniov1alpha1.ClBpfApplicationProgramState) []*bpfmaniov1alpha1.AttachInfoStateCommon {
+	var attachInfos []*bpfmaniov1alpha1.AttachInfoStateCommon
+	switch {
+	case program.XDP != nil:
+		for i := range program.XDP.Links {
+			attachInfos = append(attachInfos, &program.XDP.Links[i].AttachInfoStateCommon)
+		}
+	case program.TC != nil:
+		for i := range program.TC.Links {
+			attachInfos = append(attachInfos, &program.TC.Links[i].AttachInfoStateCommon)
+		}
+	case program.TCX != nil:
+		for i := range program.TCX.Links {
+			attachInfos = append(attachInfos, &program.TCX.Links[i].AttachInfoStateCommon)
+		}
+	case program.FEntry != nil:
+		for i := range program.FEntry.Links {
+			attachInfos = append(attachInfos, &program.FEntry.Links[i].AttachInfoStateCommon)
+		}
+	case program.FExit != nil:
+		for i := range program.FExit.Links {
+			attachInfos = append(attachInfos, &program.FExit.Links[i].AttachInfoStateCommon)
+		}
+	case program.KProbe != nil:
+		for i := range program.KProbe.Links {
+			attachInfos = append(attachInfos, &program.KProbe.Links[i].AttachInfoStateCommon)
+		}
+	case program.KRetProbe != nil:
+		for i := range program.KRetProbe.Links {
+			attachInfos = append(attachInfos, &program.KRetProbe.Links[i].AttachInfoStateCommon)
+		}
+	case program.UProbe != nil:
+		for i := range program.UProbe.Links {
+			attachInfos = append(attachInfos, &program.UProbe.Links[i].AttachInfoStateCommon)
+		}
+	case program.URetProbe != nil:
+		for i := range program.URetProbe.Links {
+			attachInfos = append(attachInfos, &program.URetProbe.Links[i].AttachInfoStateCommon)
+		}
+	case program.TracePoint != nil:
+		for i := range program.TracePoint.Links {
+			attachInfos = append(attachInfos, &program.TracePoint.Links[i].AttachInfoStateCommon)
+		}
+	}
+	return attachInfos
+}
+
+// nsAttachInfoStates returns the attach points of a namespace scoped
+// program.
+func nsAttachInfoStates(program *bpfmaniov1alpha1.BpfApplicationProgramState) []*bpfmaniov1alpha1.AttachInfoStateCommon {
+	var attachInfos []*bpfmaniov1alpha1.AttachInfoStateCommon
+	switch {
+	case program.XDP != nil:
+		for i := range program.XDP.Links {
+			attachInfos = append(attachInfos, &program.XDP.Links[i].AttachInfoStateCommon)
+		}
+	case program.TC != nil:
+		for i := range program.TC.Links {
+			attachInfos = append(attachInfos, &program.TC.Links[i].AttachInfoStateCommon)
+		}
+	case program.TCX != nil:
+		for i := range program.TCX.Links {
+			attachInfos = append(attachInfos, &program.TCX.Links[i].AttachInfoStateCommon)
+		}
+	case program.UProbe != nil:
+		for i := range program.UProbe.Links {
+			attachInfos = append(attachInfos, &program.UProbe.Links[i].AttachInfoStateCommon)
+		}
+	case program.URetProbe != nil:
+		for i := range program.URetProbe.Links {
+			attachInfos = append(attachInfos, &program.URetProbe.Links[i].AttachInfoStateCommon)
+		}
+	}
+	return attachInfos
+}
@@ -0,0 +1,222 @@
+/*
+Copyright 2025 The bpfman Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bpfmanagent
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	bpfmaniov1alpha1 "github.com/bpfman/bpfman-operator/apis/v1alpha1"
+	agenttestutils "github.com/bpfman/bpfman-operator/controllers/bpfman-agent/internal/test-utils"
+	"github.com/bpfman/bpfman-operator/internal"
+	testutils "github.com/bpfman/bpfman-operator/internal/test-utils"
+	gobpfman "github.com/bpfman/bpfman/clients/gobpfman/v1"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func linkDriftCount(t *testing.T, kind, namespace, name string) float64 {
+	m := &dto.Metric{}
+	require.NoError(t, linkDriftTotal.WithLabelValues(kind, namespace, name).Write(m))
+	return m.GetCounter().GetValue()
+}
+
+func TestLinkDriftDetectorCheck(t *testing.T) {
+	ctx := context.TODO()
+	nodeName := "fake-control-plane"
+
+	s := scheme.Scheme
+	s.AddKnownTypes(bpfmaniov1alpha1.SchemeGroupVersion,
+		&bpfmaniov1alpha1.ClusterBpfApplicationState{},
+		&bpfmaniov1alpha1.ClusterBpfApplicationStateList{},
+		&bpfmaniov1alpha1.BpfApplicationState{},
+		&bpfmaniov1alpha1.BpfApplicationStateList{},
+	)
+
+	xdpLink := func(linkId uint32) bpfmaniov1alpha1.ClXdpAttachInfoState {
+		return bpfmaniov1alpha1.ClXdpAttachInfoState{
+			AttachInfoStateCommon: bpfmaniov1alpha1.AttachInfoStateCommon{
+				ShouldAttach: true,
+				LinkId:       ptr.To(linkId),
+				LinkStatus:   bpfmaniov1alpha1.ApAttachAttached,
+			},
+			InterfaceName: fakeInt0,
+		}
+	}
+	appState := &bpfmaniov1alpha1.ClusterBpfApplicationState{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "drift-app-state",
+			Labels: map[string]string{internal.K8sHostLabel: nodeName},
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: bpfmaniov1alpha1.SchemeGroupVersion.String(),
+				Kind:       clusterBpfApplicationKind,
+				Name:       "drift-app",
+				Controller: ptr.To(true),
+			}},
+		},
+		Status: bpfmaniov1alpha1.ClBpfApplicationStateStatus{
+			Programs: []bpfmaniov1alpha1.ClBpfApplicationProgramState{{
+				BpfProgramStateCommon: bpfmaniov1alpha1.BpfProgramStateCommon{
+					Name:      testXdpBpfFunctionName,
+					ProgramId: ptr.To(uint32(10)),
+				},
+				Type: bpfmaniov1alpha1.ProgTypeXDP,
+				XDP: &bpfmaniov1alpha1.ClXdpProgramInfoState{
+					Links: []bpfmaniov1alpha1.ClXdpAttachInfoState{xdpLink(1), xdpLink(2)},
+				},
+			}},
+		},
+	}
+
+	cl := fake.NewClientBuilder().WithScheme(s).WithObjects(appState).Build()
+	cli := agenttestutils.NewBpfmanClientFakeWithPrograms(map[int]*gobpfman.GetResponse{
+		// Link 2 was detached behind bpfman-operator's back.
+		10: {
+			Info:       &gobpfman.ProgramInfo{Name: testXdpBpfFunctionName, Links: []uint32{1}},
+			KernelInfo: &gobpfman.KernelProgramInfo{Id: 10},
+		},
+	})
+
+	// Report only.
+	recorder := record.NewFakeRecorder(10)
+	detector := NewLinkDriftDetector(cl, cli, recorder, nodeName, time.Minute, false)
+	before := linkDriftCount(t, clusterBpfApplicationKind, "", "drift-app")
+	require.NoError(t, detector.Check(ctx))
+	require.Equal(t, before+1, linkDriftCount(t, clusterBpfApplicationKind, "", "drift-app"))
+	require.Len(t, recorder.Events, 1)
+	require.Contains(t, <-recorder.Events, "Warning DriftDetected Link 2 of program XdpTest is missing")
+	require.Empty(t, detector.ClusterRepairs())
+
+	// A link still missing is only counted and reported once.
+	require.NoError(t, detector.Check(ctx))
+	require.Equal(t, before+1, linkDriftCount(t, clusterBpfApplicationKind, "", "drift-app"))
+	require.Empty(t, recorder.Events)
+
+	// Repair.
+	detector = NewLinkDriftDetector(cl, cli, recorder, nodeName, time.Minute, true)
+	require.NoError(t, detector.Check(ctx))
+	require.Equal(t, before+2, linkDriftCount(t, clusterBpfApplicationKind, "", "drift-app"))
+	require.Contains(t, <-recorder.Events,
+		"Warning DriftDetected Link 2 of program XdpTest is missing on node fake-control-plane, re-attaching it")
+	require.Len(t, detector.ClusterRepairs(), 1)
+	repair := <-detector.ClusterRepairs()
+	require.Equal(t, "drift-app", repair.Object.GetName())
+
+	// The repair is triggered again while the link is missing, without
+	// counting or reporting it again.
+	require.NoError(t, detector.Check(ctx))
+	require.Equal(t, before+2, linkDriftCount(t, clusterBpfApplicationKind, "", "drift-app"))
+	require.Empty(t, recorder.Events)
+	require.Len(t, detector.ClusterRepairs(), 1)
+	<-detector.ClusterRepairs()
+
+	// Nothing is reported once the link is re-attached.
+	cli.Programs[10].Info.Links = append(cli.Programs[10].Info.Links, 2)
+	require.NoError(t, detector.Check(ctx))
+	require.Empty(t, recorder.Events)
+	require.Empty(t, detector.ClusterRepairs())
+
+	// While bpfman can't be reached, e.g., while it restarts, the check is
+	// skipped instead of reporting every link as missing.
+	cli.GetErr = status.Error(codes.Unavailable, "connection refused")
+	require.Error(t, detector.Check(ctx))
+	require.Equal(t, before+2, linkDriftCount(t, clusterBpfApplicationKind, "", "drift-app"))
+	require.Empty(t, recorder.Events)
+	require.Empty(t, detector.ClusterRepairs())
+
+	// A program that is gone has no links.
+	cli.GetErr = nil
+	delete(cli.Programs, 10)
+	require.NoError(t, detector.Check(ctx))
+	require.Equal(t, before+4, linkDriftCount(t, clusterBpfApplicationKind, "", "drift-app"))
+	require.Len(t, recorder.Events, 2)
+	require.Len(t, detector.ClusterRepairs(), 1)
+}
+
+func TestLinkDriftCorrected(t *testing.T) {
+	var (
+		fakeNode = testutils.NewNode("fake-control-plane")
+		ctx      = context.TODO()
+	)
+
+	bpfApp := &bpfmaniov1alpha1.ClusterBpfApplication{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: testAppProgramName,
+		},
+		Spec: bpfmaniov1alpha1.ClBpfApplicationSpec{
+			BpfAppCommon: bpfmaniov1alpha1.BpfAppCommon{
+				NodeSelector: metav1.LabelSelector{},
+				ByteCode: bpfmaniov1alpha1.ByteCodeSelector{
+					Path: ptr.To(testBytecodePath),
+				},
+			},
+			Programs: []bpfmaniov1alpha1.ClBpfApplicationProgram{{
+				Name: testKprobeBpfFunctionName,
+				Type: bpfmaniov1alpha1.ProgTypeKprobe,
+				KProbe: &bpfmaniov1alpha1.ClKprobeProgramInfo{
+					Links: []bpfmaniov1alpha1.ClKprobeAttachInfo{{Function: testAttachName}},
+				},
+			}},
+		},
+	}
+
+	r := createFakeClusterReconciler([]runtime.Object{fakeNode, bpfApp}, bpfApp, fakeNode)
+	recorder := record.NewFakeRecorder(10)
+	r.Recorder = recorder
+	cli := r.BpfmanClient.(*agenttestutils.BpfmanClientFake)
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: testAppProgramName}}
+	getLink := func() bpfmaniov1alpha1.AttachInfoStateCommon {
+		bpfAppState, err := r.getBpfAppState(ctx)
+		require.NoError(t, err)
+		require.Len(t, bpfAppState.Status.Programs[0].KProbe.Links, 1)
+		return bpfAppState.Status.Programs[0].KProbe.Links[0].AttachInfoStateCommon
+	}
+
+	// A first attach isn't a correction.
+	runReconciler(t, ctx, r, req, r.Logger)
+	runReconciler(t, ctx, r, req, r.Logger)
+	link := getLink()
+	require.Equal(t, bpfmaniov1alpha1.ApAttachAttached, link.LinkStatus)
+	require.NotNil(t, link.LinkId)
+	require.Empty(t, recorder.Events)
+
+	// The link is detached behind bpfman-operator's back, and re-attached.
+	bpfAppState, err := r.getBpfAppState(ctx)
+	require.NoError(t, err)
+	program := cli.Programs[int(*bpfAppState.Status.Programs[0].ProgramId)]
+	program.Info.Links = slices.DeleteFunc(program.Info.Links, func(id uint32) bool { return id == *link.LinkId })
+	runReconciler(t, ctx, r, req, r.Logger)
+	relinked := getLink()
+	require.Equal(t, bpfmaniov1alpha1.ApAttachAttached, relinked.LinkStatus)
+	require.NotEqual(t, *link.LinkId, *relinked.LinkId)
+	require.Len(t, recorder.Events, 1)
+	require.Equal(t, fmt.Sprintf("Normal DriftCorrected Link %d of program %s was missing on node %s, re-attached it as link %d",
+		*link.LinkId, testKprobeBpfFunctionName, fakeNode.Name, *relinked.LinkId), <-recorder.Events)
+}
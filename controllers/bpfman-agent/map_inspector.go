@@ -0,0 +1,289 @@
+/*
+Copyright 2025 The bpfman Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bpfmanagent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+
+	bpfmaniov1alpha1 "github.com/bpfman/bpfman-operator/apis/v1alpha1"
+	bpfmanagentinternal "github.com/bpfman/bpfman-operator/controllers/bpfman-agent/internal"
+	"github.com/bpfman/bpfman-operator/internal"
+	gobpfman "github.com/bpfman/bpfman/clients/gobpfman/v1"
+	"github.com/go-logr/logr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	clusterBpfApplicationKind = "ClusterBpfApplication"
+	bpfApplicationKind        = "BpfApplication"
+)
+
+var (
+	// errNotFound is returned when the requested application or map does
+	// not exist on this node.
+	errNotFound = errors.New("not found")
+	// errInvalidRequest is returned when the request parameters are
+	// invalid.
+	errInvalidRequest = errors.New("invalid request")
+)
+
+// MapInspector is a read-only HTTP handler that lists the maps used by an
+// application loaded on this node and dumps their entries. It is served on
+// the agent's local socket and exposed by the metrics-proxy, which
+// authenticates every request and authorizes it against the requested
+// application, at /agent-maps.
+//
+// Requests take the following query parameters:
+//
+//	kind       ClusterBpfApplication or BpfApplication. Defaults to
+//	           BpfApplication when namespace is set.
+//	namespace  Namespace of a BpfApplication.
+//	name       Name of the application (required).
+//	map        Kernel ID of a map. When set, the entries of the map are
+//	           returned instead of the list of maps.
+//	limit      Maximum number of entries to return.
+//	continue   Token returned by the previous page of entries.
+//
+// Only maps referenced by the programs of the named application can be
+// dumped.
+type MapInspector struct {
+	client.Reader
+	BpfmanClient gobpfman.BpfmanClient
+	NodeName     string
+	Logger       logr.Logger
+
+	// describeMap and dumpMap read kernel maps and are replaced in tests.
+	describeMap func(id uint32) (*bpfmanagentinternal.MapSummary, error)
+	dumpMap     func(id uint32, cont string, limit int) (*bpfmanagentinternal.MapEntriesPage, error)
+}
+
+// NewMapInspector returns a MapInspector for the applications on nodeName.
+func NewMapInspector(reader client.Reader, bpfmanClient gobpfman.BpfmanClient, nodeName string) *MapInspector {
+	return &MapInspector{
+		Reader:       reader,
+		BpfmanClient: bpfmanClient,
+		NodeName:     nodeName,
+		Logger:       ctrl.Log.WithName("map-inspector"),
+		describeMap:  bpfmanagentinternal.DescribeMap,
+		dumpMap:      bpfmanagentinternal.DumpMapEntries,
+	}
+}
+
+// ApplicationRef identifies the application whose maps are inspected.
+type ApplicationRef struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+}
+
+// ApplicationMap is a map together with the programs of the application
+// that use it.
+type ApplicationMap struct {
+	bpfmanagentinternal.MapSummary `json:",inline"`
+	Programs                       []string `json:"programs"`
+}
+
+// ApplicationMapList is the response to a request for the maps of an
+// application.
+type ApplicationMapList struct {
+	Application ApplicationRef   `json:"application"`
+	Node        string           `json:"node"`
+	Maps        []ApplicationMap `json:"maps"`
+}
+
+// ApplicationMapEntries is the response to a request for the entries of a
+// map.
+type ApplicationMapEntries struct {
+	Application                         ApplicationRef `json:"application"`
+	Node                                string         `json:"node"`
+	*bpfmanagentinternal.MapEntriesPage `json:",inline"`
+}
+
+func (mi *MapInspector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		mi.writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+		return
+	}
+
+	query := r.URL.Query()
+	app := ApplicationRef{
+		Kind:      query.Get("kind"),
+		Namespace: query.Get("namespace"),
+		Name:      query.Get("name"),
+	}
+	if app.Kind == "" {
+		app.Kind = clusterBpfApplicationKind
+		if app.Namespace != "" {
+			app.Kind = bpfApplicationKind
+		}
+	}
+	if app.Name == "" {
+		mi.writeError(w, http.StatusBadRequest, fmt.Errorf("name is required"))
+		return
+	}
+
+	maps, err := mi.applicationMaps(r.Context(), app)
+	if err != nil {
+		mi.writeError(w, statusFor(err), err)
+		return
+	}
+
+	if query.Get("map") == "" {
+		list := ApplicationMapList{Application: app, Node: mi.NodeName, Maps: []ApplicationMap{}}
+		for _, id := range sortedKeys(maps) {
+			summary, err := mi.describeMap(id)
+			if err != nil {
+				mi.writeError(w, http.StatusInternalServerError, err)
+				return
+			}
+			list.Maps = append(list.Maps, ApplicationMap{MapSummary: *summary, Programs: maps[id]})
+		}
+		mi.writeJSON(w, list)
+		return
+	}
+
+	id, err := strconv.ParseUint(query.Get("map"), 10, 32)
+	if err != nil {
+		mi.writeError(w, http.StatusBadRequest, fmt.Errorf("invalid map id %q", query.Get("map")))
+		return
+	}
+	if _, ok := maps[uint32(id)]; !ok {
+		mi.writeError(w, http.StatusNotFound, fmt.Errorf("map %d is not used by %s %s: %w", id, app.Kind, app.Name, errNotFound))
+		return
+	}
+
+	limit := 0
+	if v := query.Get("limit"); v != "" {
+		if limit, err = strconv.Atoi(v); err != nil || limit <= 0 {
+			mi.writeError(w, http.StatusBadRequest, fmt.Errorf("invalid limit %q", v))
+			return
+		}
+	}
+
+	page, err := mi.dumpMap(uint32(id), query.Get("continue"), limit)
+	if err != nil {
+		mi.writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	mi.writeJSON(w, ApplicationMapEntries{Application: app, Node: mi.NodeName, MapEntriesPage: page})
+}
+
+// applicationMaps returns the IDs of the maps used by the programs of app on
+// this node, each with the names of the programs using it.
+func (mi *MapInspector) applicationMaps(ctx context.Context, app ApplicationRef) (map[uint32][]string, error) {
+	type loadedProgram struct {
+		name string
+		id   uint32
+	}
+	var programs []loadedProgram
+
+	opts := []client.ListOption{
+		client.MatchingLabels{
+			internal.BpfAppStateOwner: app.Name,
+			internal.K8sHostLabel:     mi.NodeName,
+		},
+	}
+
+	switch app.Kind {
+	case clusterBpfApplicationKind:
+		states := &bpfmaniov1alpha1.ClusterBpfApplicationStateList{}
+		if err := mi.List(ctx, states, opts...); err != nil {
+			return nil, err
+		}
+		if len(states.Items) == 0 {
+			return nil, fmt.Errorf("%s %s is not loaded on node %s: %w", app.Kind, app.Name, mi.NodeName, errNotFound)
+		}
+		for _, program := range states.Items[0].Status.Programs {
+			if program.ProgramId != nil {
+				programs = append(programs, loadedProgram{name: program.Name, id: *program.ProgramId})
+			}
+		}
+	case bpfApplicationKind:
+		if app.Namespace == "" {
+			return nil, fmt.Errorf("namespace is required for %s: %w", app.Kind, errInvalidRequest)
+		}
+		states := &bpfmaniov1alpha1.BpfApplicationStateList{}
+		if err := mi.List(ctx, states, append(opts, client.InNamespace(app.Namespace))...); err != nil {
+			return nil, err
+		}
+		if len(states.Items) == 0 {
+			return nil, fmt.Errorf("%s %s/%s is not loaded on node %s: %w", app.Kind, app.Namespace, app.Name, mi.NodeName, errNotFound)
+		}
+		for _, program := range states.Items[0].Status.Programs {
+			if program.ProgramId != nil {
+				programs = append(programs, loadedProgram{name: program.Name, id: *program.ProgramId})
+			}
+		}
+	default:
+		return nil, fmt.Errorf("unsupported kind %q: %w", app.Kind, errInvalidRequest)
+	}
+
+	maps := map[uint32][]string{}
+	for _, program := range programs {
+		res, err := bpfmanagentinternal.GetBpfmanProgramById(ctx, mi.BpfmanClient, program.id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get program %d from bpfman: %w", program.id, err)
+		}
+		for _, mapId := range res.GetKernelInfo().GetMapIds() {
+			maps[mapId] = append(maps[mapId], program.name)
+		}
+	}
+	return maps, nil
+}
+
+func (mi *MapInspector) writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		mi.Logger.Error(err, "failed to write response")
+	}
+}
+
+func (mi *MapInspector) writeError(w http.ResponseWriter, status int, err error) {
+	mi.Logger.V(1).Info("map inspection request failed", "status", status, "error", err)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+}
+
+// statusFor maps an error from looking up an application to an HTTP
+// status code.
+func statusFor(err error) int {
+	switch {
+	case errors.Is(err, errNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, errInvalidRequest):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func sortedKeys(m map[uint32][]string) []uint32 {
+	keys := make([]uint32, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
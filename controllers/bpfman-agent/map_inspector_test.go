@@ -0,0 +1,122 @@
+/*
+Copyright 2025 The bpfman Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bpfmanagent
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	bpfmaniov1alpha1 "github.com/bpfman/bpfman-operator/apis/v1alpha1"
+	bpfmanagentinternal "github.com/bpfman/bpfman-operator/controllers/bpfman-agent/internal"
+	agenttestutils "github.com/bpfman/bpfman-operator/controllers/bpfman-agent/internal/test-utils"
+	"github.com/bpfman/bpfman-operator/internal"
+	gobpfman "github.com/bpfman/bpfman/clients/gobpfman/v1"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestMapInspector(t *testing.T) {
+	appState := &bpfmaniov1alpha1.ClusterBpfApplicationState{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-app-state",
+			Labels: map[string]string{
+				internal.BpfAppStateOwner: "test-app",
+				internal.K8sHostLabel:     "test-node",
+			},
+		},
+		Status: bpfmaniov1alpha1.ClBpfApplicationStateStatus{
+			Programs: []bpfmaniov1alpha1.ClBpfApplicationProgramState{
+				{BpfProgramStateCommon: bpfmaniov1alpha1.BpfProgramStateCommon{Name: "prog-a", ProgramId: ptr.To(uint32(10))}},
+				{BpfProgramStateCommon: bpfmaniov1alpha1.BpfProgramStateCommon{Name: "prog-b", ProgramId: ptr.To(uint32(11))}},
+			},
+		},
+	}
+
+	s := scheme.Scheme
+	s.AddKnownTypes(bpfmaniov1alpha1.SchemeGroupVersion, &bpfmaniov1alpha1.ClusterBpfApplicationState{})
+	s.AddKnownTypes(bpfmaniov1alpha1.SchemeGroupVersion, &bpfmaniov1alpha1.ClusterBpfApplicationStateList{})
+	cl := fake.NewClientBuilder().WithRuntimeObjects([]runtime.Object{appState}...).Build()
+
+	cli := agenttestutils.NewBpfmanClientFakeWithPrograms(map[int]*gobpfman.GetResponse{
+		10: {KernelInfo: &gobpfman.KernelProgramInfo{Id: 10, MapIds: []uint32{5, 6}}},
+		11: {KernelInfo: &gobpfman.KernelProgramInfo{Id: 11, MapIds: []uint32{6}}},
+	})
+
+	mi := NewMapInspector(cl, cli, "test-node")
+	mi.describeMap = func(id uint32) (*bpfmanagentinternal.MapSummary, error) {
+		return &bpfmanagentinternal.MapSummary{Id: id, Name: "map", Type: "Hash"}, nil
+	}
+	var dumped uint32
+	mi.dumpMap = func(id uint32, cont string, limit int) (*bpfmanagentinternal.MapEntriesPage, error) {
+		dumped = id
+		require.Equal(t, "00000001", cont)
+		require.Equal(t, 2, limit)
+		return &bpfmanagentinternal.MapEntriesPage{
+			Map:     bpfmanagentinternal.MapSummary{Id: id},
+			Entries: []bpfmanagentinternal.MapEntry{{Key: uint64(2), Value: uint64(3)}},
+		}, nil
+	}
+
+	get := func(query string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		mi.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/maps?"+query, nil))
+		return rr
+	}
+
+	// List the maps of the application, shared maps report every program
+	// using them.
+	rr := get("name=test-app")
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	var list ApplicationMapList
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &list))
+	require.Equal(t, clusterBpfApplicationKind, list.Application.Kind)
+	require.Equal(t, "test-node", list.Node)
+	require.Len(t, list.Maps, 2)
+	require.Equal(t, uint32(5), list.Maps[0].Id)
+	require.Equal(t, []string{"prog-a"}, list.Maps[0].Programs)
+	require.Equal(t, uint32(6), list.Maps[1].Id)
+	require.Equal(t, []string{"prog-a", "prog-b"}, list.Maps[1].Programs)
+
+	// Dump the entries of one of the application's maps.
+	rr = get("name=test-app&map=6&limit=2&continue=00000001")
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	require.Equal(t, uint32(6), dumped)
+	var entries ApplicationMapEntries
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &entries))
+	require.Len(t, entries.Entries, 1)
+
+	// Maps that do not belong to the application cannot be dumped.
+	rr = get("name=test-app&map=7")
+	require.Equal(t, http.StatusNotFound, rr.Code)
+
+	// Unknown applications and invalid requests are rejected.
+	require.Equal(t, http.StatusNotFound, get("name=other-app").Code)
+	require.Equal(t, http.StatusBadRequest, get("").Code)
+	require.Equal(t, http.StatusBadRequest, get("kind=BpfApplication&name=test-app").Code)
+	require.Equal(t, http.StatusBadRequest, get("name=test-app&map=6&limit=-1").Code)
+
+	rr = httptest.NewRecorder()
+	mi.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/maps?name=test-app", nil))
+	require.Equal(t, http.StatusMethodNotAllowed, rr.Code)
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2025 The bpfman Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bpfmanagent
+
+import (
+	"encoding/json"
+	"fmt"
+
+	bpfmaniov1alpha1 "github.com/bpfman/bpfman-operator/apis/v1alpha1"
+)
+
+// networkStatusAnnotation is set by Multus on the pods it attaches to
+// networks. It lists the interfaces of each network in the pod.
+const networkStatusAnnotation = "k8s.v1.cni.cncf.io/network-status"
+
+// networkStatus is an entry of the network-status annotation.
+type networkStatus struct {
+	// Name is the namespace/name of the NetworkAttachmentDefinition, or the
+	// name of the cluster default network.
+	Name      string `json:"name"`
+	Interface string `json:"interface,omitempty"`
+}
+
+// networkAttachmentInterfaces returns the interfaces of the network
+// attachment ref in pod, as reported by the network-status annotation of the
+// pod. Pods that are not annotated yet have no interfaces.
+func networkAttachmentInterfaces(ref *bpfmaniov1alpha1.NetworkAttachmentReference, pod *PodNetnsInfo) ([]string, error) {
+	if pod.networkStatus == "" {
+		return nil, nil
+	}
+	var statuses []networkStatus
+	if err := json.Unmarshal([]byte(pod.networkStatus), &statuses); err != nil {
+		return nil, fmt.Errorf("failed to parse the %s annotation of pod %s/%s: %w",
+			networkStatusAnnotation, pod.podNamespace, pod.podName, err)
+	}
+
+	namespace := ref.Namespace
+	if namespace == "" {
+		namespace = pod.podNamespace
+	}
+	var interfaces []string
+	for _, status := range statuses {
+		if status.Interface == "" || (ref.Interface != "" && status.Interface != ref.Interface) {
+			continue
+		}
+		// Multus reports the namespace of the NetworkAttachmentDefinition,
+		// except in old versions for the ones in the namespace of the pod.
+		if status.Name == namespace+"/"+ref.Name || (status.Name == ref.Name && namespace == pod.podNamespace) {
+			interfaces = append(interfaces, status.Interface)
+		}
+	}
+	return interfaces, nil
+}
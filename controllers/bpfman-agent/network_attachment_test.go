@@ -0,0 +1,69 @@
+/*
+Copyright 2025 The bpfman Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bpfmanagent
+
+import (
+	"testing"
+
+	bpfmaniov1alpha1 "github.com/bpfman/bpfman-operator/apis/v1alpha1"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNetworkAttachmentInterfaces(t *testing.T) {
+	pod := &PodNetnsInfo{
+		podName:      "pod",
+		podNamespace: "team-a",
+		networkStatus: `[
+			{"name": "ovn-kubernetes", "interface": "eth0", "default": true},
+			{"name": "team-a/sriov-a", "interface": "net1"},
+			{"name": "team-a/sriov-a", "interface": "net2"},
+			{"name": "infra/macvlan", "interface": "net3"},
+			{"name": "sriov-b", "interface": "net4"},
+			{"name": "team-a/sriov-c"}
+		]`,
+	}
+
+	tests := []struct {
+		name string
+		ref  bpfmaniov1alpha1.NetworkAttachmentReference
+		want []string
+	}{
+		{name: "namespace of the pod", ref: bpfmaniov1alpha1.NetworkAttachmentReference{Name: "sriov-a"}, want: []string{"net1", "net2"}},
+		{name: "interface", ref: bpfmaniov1alpha1.NetworkAttachmentReference{Name: "sriov-a", Interface: "net2"}, want: []string{"net2"}},
+		{name: "other namespace", ref: bpfmaniov1alpha1.NetworkAttachmentReference{Name: "macvlan", Namespace: "infra"}, want: []string{"net3"}},
+		{name: "wrong namespace", ref: bpfmaniov1alpha1.NetworkAttachmentReference{Name: "macvlan"}},
+		{name: "name without namespace", ref: bpfmaniov1alpha1.NetworkAttachmentReference{Name: "sriov-b"}, want: []string{"net4"}},
+		{name: "no interface", ref: bpfmaniov1alpha1.NetworkAttachmentReference{Name: "sriov-c"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			interfaces, err := networkAttachmentInterfaces(&tt.ref, pod)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, interfaces)
+		})
+	}
+
+	// Pods are annotated once Multus attached them.
+	interfaces, err := networkAttachmentInterfaces(&bpfmaniov1alpha1.NetworkAttachmentReference{Name: "sriov-a"},
+		&PodNetnsInfo{podName: "pod", podNamespace: "team-a"})
+	require.NoError(t, err)
+	require.Empty(t, interfaces)
+
+	pod.networkStatus = "{"
+	_, err = networkAttachmentInterfaces(&bpfmaniov1alpha1.NetworkAttachmentReference{Name: "sriov-a"}, pod)
+	require.Error(t, err)
+}
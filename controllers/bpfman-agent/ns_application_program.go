@@ -36,8 +36,10 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
 //+kubebuilder:rbac:groups=bpfman.io,resources=bpfapplications,verbs=get;list;watch
@@ -52,6 +54,13 @@ import (
 
 type NsBpfApplicationReconciler struct {
 	ReconcilerCommon
+	// Repairs, when set, triggers reconciles to re-attach the links found
+	// missing on the node.
+	Repairs <-chan event.GenericEvent
+	// InterfaceEvents, when set, triggers reconciles when interfaces are
+	// created or deleted on the node, so that the programs attached with
+	// interface discovery follow them.
+	InterfaceEvents <-chan event.GenericEvent
 	currentApp      *bpfmaniov1alpha1.BpfApplication
 	currentAppState *bpfmaniov1alpha1.BpfApplicationState
 }
@@ -60,6 +69,20 @@ type NsProgramReconcilerCommon struct {
 	currentProgram      *bpfmaniov1alpha1.BpfApplicationProgram
 	currentProgramState *bpfmaniov1alpha1.BpfApplicationProgramState
 	namespace           string
+	appName             string
+	appState            *bpfmaniov1alpha1.BpfApplicationState
+}
+
+// attachMetadata returns the metadata of the attach request of the link uuid
+// of the current program, attached for pod if it isn't nil.
+func (r *NsProgramReconcilerCommon) attachMetadata(uuid string, pod *bpfmaniov1alpha1.AttachPodState) map[string]string {
+	return attachMetadata(uuid, r.namespace, r.appName, r.currentProgram.Name, pod)
+}
+
+// getAppState returns the application state the events of the links of the
+// current program are recorded on.
+func (r *NsProgramReconcilerCommon) getAppState() client.Object {
+	return r.appState
 }
 
 func (r *NsBpfApplicationReconciler) getAppStateName() string {
@@ -96,7 +119,7 @@ func (r *NsBpfApplicationReconciler) setAppLoadStatus(status bpfmaniov1alpha1.Ap
 // programs on the node via bpfman, and create or update a BpfNsApplicationState
 // object to reflect per node state information.
 func (r *NsBpfApplicationReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
+	b := ctrl.NewControllerManagedBy(mgr).
 		For(&bpfmaniov1alpha1.BpfApplication{}, builder.WithPredicates(predicate.And(predicate.GenerationChangedPredicate{}, predicate.ResourceVersionChangedPredicate{}))).
 		WithOptions(controller.Options{MaxConcurrentReconciles: 1}).
 		Owns(&bpfmaniov1alpha1.BpfApplicationState{},
@@ -117,7 +140,19 @@ func (r *NsBpfApplicationReconciler) SetupWithManager(mgr ctrl.Manager) error {
 			&handler.EnqueueRequestForObject{},
 			builder.WithPredicates(podOnNodePredicate(r.NodeName)),
 		).
-		Complete(r)
+		// Watch for changes in BpfPriorityClasses since they determine the
+		// priority of the links that reference them.
+		Watches(
+			&bpfmaniov1alpha1.BpfPriorityClass{},
+			&handler.EnqueueRequestForObject{},
+		)
+	if r.Repairs != nil {
+		b = b.WatchesRawSource(source.Channel(r.Repairs, &handler.EnqueueRequestForObject{}))
+	}
+	if r.InterfaceEvents != nil {
+		b = b.WatchesRawSource(source.Channel(r.InterfaceEvents, &handler.EnqueueRequestForObject{}))
+	}
+	return b.Complete(r)
 }
 
 func (r *NsBpfApplicationReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -151,6 +186,15 @@ func (r *NsBpfApplicationReconciler) Reconcile(ctx context.Context, req ctrl.Req
 	for appProgramIndex := range appPrograms.Items {
 		r.currentApp = &appPrograms.Items[appProgramIndex]
 
+		// Attribute the bpfman calls made for this application in the
+		// audit log.
+		ctx := bpfmanagentinternal.WithAuditSubject(ctx, bpfmanagentinternal.AuditSubject{
+			Kind:      bpfApplicationKind,
+			Namespace: r.currentApp.Namespace,
+			Name:      r.currentApp.Name,
+			UID:       string(r.currentApp.UID),
+		})
+
 		r.Logger.Info("Reconciling BpfApplication", "Name", r.currentApp.Name)
 
 		// Get the BpfApplicationState object for this node if it exists.
@@ -187,7 +231,7 @@ func (r *NsBpfApplicationReconciler) Reconcile(ctx context.Context, req ctrl.Req
 			// There's no point continuing to reconcile the links if we
 			// can't load the code.
 			r.Logger.Error(err, "failed to reconcileLoad")
-			r.updateBpfAppStateCondition(r, bpfmaniov1alpha1.BpfAppStateCondError)
+			r.updateBpfAppStateCondition(r, loadFailureCondition(err))
 			statusChanged, err := r.updateBpfAppStateStatus(ctx, nil)
 			if err != nil {
 				r.Logger.Error(err, "failed to update BpfApplicationState status", "Name", r.currentApp.Name)
@@ -316,6 +360,9 @@ func (r *NsBpfApplicationReconciler) getProgramReconciler(prog *bpfmaniov1alpha1
 			NsProgramReconcilerCommon: NsProgramReconcilerCommon{
 				currentProgram:      prog,
 				currentProgramState: progState,
+				namespace:           r.currentApp.Namespace,
+				appName:             r.currentApp.Name,
+				appState:            r.currentAppState,
 			},
 		}
 
@@ -325,6 +372,9 @@ func (r *NsBpfApplicationReconciler) getProgramReconciler(prog *bpfmaniov1alpha1
 			NsProgramReconcilerCommon: NsProgramReconcilerCommon{
 				currentProgram:      prog,
 				currentProgramState: progState,
+				namespace:           r.currentApp.Namespace,
+				appName:             r.currentApp.Name,
+				appState:            r.currentAppState,
 			},
 		}
 
@@ -334,6 +384,9 @@ func (r *NsBpfApplicationReconciler) getProgramReconciler(prog *bpfmaniov1alpha1
 			NsProgramReconcilerCommon: NsProgramReconcilerCommon{
 				currentProgram:      prog,
 				currentProgramState: progState,
+				namespace:           r.currentApp.Namespace,
+				appName:             r.currentApp.Name,
+				appState:            r.currentAppState,
 			},
 		}
 
@@ -343,6 +396,9 @@ func (r *NsBpfApplicationReconciler) getProgramReconciler(prog *bpfmaniov1alpha1
 			NsProgramReconcilerCommon: NsProgramReconcilerCommon{
 				currentProgram:      prog,
 				currentProgramState: progState,
+				namespace:           r.currentApp.Namespace,
+				appName:             r.currentApp.Name,
+				appState:            r.currentAppState,
 			},
 		}
 
@@ -368,6 +424,16 @@ func (r *NsBpfApplicationReconciler) checkProgramStatus() bpfmaniov1alpha1.BpfAp
 	return bpfmaniov1alpha1.BpfAppStateCondSuccess
 }
 
+// programStateCommons returns the fields common to all program states of the
+// current BpfApplicationState.
+func (r *NsBpfApplicationReconciler) programStateCommons() []*bpfmaniov1alpha1.BpfProgramStateCommon {
+	programs := make([]*bpfmaniov1alpha1.BpfProgramStateCommon, 0, len(r.currentAppState.Status.Programs))
+	for i := range r.currentAppState.Status.Programs {
+		programs = append(programs, &r.currentAppState.Status.Programs[i].BpfProgramStateCommon)
+	}
+	return programs
+}
+
 // getProgState returns the BpfNsApplicationProgramState object for the current node.
 func (r *NsBpfApplicationReconciler) getProgState(prog *bpfmaniov1alpha1.BpfApplicationProgram,
 	programs []bpfmaniov1alpha1.BpfApplicationProgramState) (*bpfmaniov1alpha1.BpfApplicationProgramState, error) {
@@ -661,12 +727,16 @@ func (r *NsBpfApplicationReconciler) load(ctx context.Context) error {
 		return fmt.Errorf("failed to get LoadRequest: %w", err)
 	}
 
-	loadResponse, err := r.BpfmanClient.Load(ctx, loadRequest)
+	programs, err := bpfmanagentinternal.LoadBpfmanProgram(ctx, r.BpfmanClient, r.AuditLog, loadRequest)
 	if err != nil {
-		return fmt.Errorf("failed to load eBPF Program: %v", err)
+		// The agent may only write ConfigMaps in its own namespace, so only
+		// the end of the verifier log is kept in the status.
+		r.recordVerifierLog(ctx, r.currentAppState, "", r.programStateCommons(), err)
+		return fmt.Errorf("failed to load eBPF Program: %w", err)
 	} else {
+		r.clearVerifierLog(ctx, r.currentAppState, "", r.programStateCommons())
 		for p, program := range r.currentAppState.Status.Programs {
-			id, err := bpfmanagentinternal.GetBpfProgramId(program.Name, loadResponse.Programs)
+			id, err := bpfmanagentinternal.GetBpfProgramId(program.Name, programs)
 			// This should never happen because the bpfman load is all or nothing,
 			// and we aren't allowing users to add or remove programs from an
 			// existing BpfApplication.  However, if it does happen, log an error.
@@ -687,7 +757,7 @@ func (r *NsBpfApplicationReconciler) unload(ctx context.Context) {
 	for i := len(r.currentAppState.Status.Programs) - 1; i >= 0; i-- {
 		program := r.currentAppState.Status.Programs[i]
 		if program.ProgramId != nil {
-			err := bpfmanagentinternal.UnloadBpfmanProgram(ctx, r.BpfmanClient, *program.ProgramId)
+			err := bpfmanagentinternal.UnloadBpfmanProgram(ctx, r.BpfmanClient, r.AuditLog, *program.ProgramId)
 			if err != nil {
 				// This should never happen under normal operations.  However,
 				// it is possible that someone unloaded the program manually. In
@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"strings"
 	"testing"
 
 	bpfmaniov1alpha1 "github.com/bpfman/bpfman-operator/apis/v1alpha1"
@@ -28,6 +29,9 @@ import (
 	testutils "github.com/bpfman/bpfman-operator/internal/test-utils"
 	"github.com/bpfman/bpfman-operator/pkg/helpers"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
@@ -365,6 +369,13 @@ func TestNsBpfApplicationControllerCreate(t *testing.T) {
 					pid:           fakePid,
 				},
 			},
+			podList: &[]PodNetnsInfo{
+				{
+					podName:      fakePodName,
+					podNamespace: testNamespace,
+					netnsPath:    netnsPathFromPID(fakePid),
+				},
+			},
 		}
 
 		// Objects to track in the fake client.
@@ -470,3 +481,303 @@ func verifyNamespaceBpfProgramState(t *testing.T, bpfAppState *bpfmaniov1alpha1.
 	}
 	require.Equal(t, len(programs), numMatches)
 }
+
+func TestGetLinkPriority(t *testing.T) {
+	ctx := context.TODO()
+	s := scheme.Scheme
+	s.AddKnownTypes(bpfmaniov1alpha1.SchemeGroupVersion,
+		&bpfmaniov1alpha1.BpfPriorityClass{},
+		&bpfmaniov1alpha1.BpfPriorityClassList{},
+	)
+	class := &bpfmaniov1alpha1.BpfPriorityClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "team"},
+		Spec: bpfmaniov1alpha1.BpfPriorityClassSpec{
+			MinPriority:       500,
+			MaxPriority:       799,
+			DefaultPriority:   ptr.To(int32(600)),
+			AllowedNamespaces: []string{"team-a"},
+		},
+	}
+	r := &ReconcilerCommon{Client: fake.NewClientBuilder().WithScheme(s).WithObjects(class).Build()}
+
+	tests := []struct {
+		name      string
+		namespace string
+		className string
+		priority  *int32
+		want      int32
+		wantErr   bool
+	}{
+		{name: "no class", namespace: "team-b", want: bpfmaniov1alpha1.DefaultAttachPriority},
+		{name: "no class with priority", namespace: "team-b", priority: ptr.To(int32(10)), want: 10},
+		{name: "priority reserved by a class", namespace: "team-b", priority: ptr.To(int32(600)), wantErr: true},
+		{name: "priority of an allowed class", namespace: "team-a", priority: ptr.To(int32(600)), want: 600},
+		{name: "cluster application with reserved priority", priority: ptr.To(int32(600)), want: 600},
+		{name: "default priority of the class", namespace: "team-a", className: "team", want: 600},
+		{name: "priority in range", namespace: "team-a", className: "team", priority: ptr.To(int32(700)), want: 700},
+		{name: "priority out of range", namespace: "team-a", className: "team", priority: ptr.To(int32(10)), wantErr: true},
+		{name: "namespace not allowed", namespace: "team-b", className: "team", wantErr: true},
+		{name: "cluster application", className: "team", want: 600},
+		{name: "missing class", namespace: "team-a", className: "missing", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := r.getLinkPriority(ctx, tt.namespace, tt.className, tt.priority)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+
+	// Links without a priority get the default one, which can be reserved
+	// too.
+	require.NoError(t, r.Create(ctx, &bpfmaniov1alpha1.BpfPriorityClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "platform"},
+		Spec: bpfmaniov1alpha1.BpfPriorityClassSpec{
+			MinPriority:       900,
+			MaxPriority:       1000,
+			AllowedNamespaces: []string{"team-a"},
+		},
+	}))
+	_, err := r.getLinkPriority(ctx, "team-b", "", nil)
+	require.EqualError(t, err, "default priority 1000 is reserved by BpfPriorityClass platform, "+
+		"which namespace team-b is not allowed to use")
+	got, err := r.getLinkPriority(ctx, "team-a", "", nil)
+	require.NoError(t, err)
+	require.Equal(t, bpfmaniov1alpha1.DefaultAttachPriority, got)
+	got, err = r.getLinkPriority(ctx, "", "", nil)
+	require.NoError(t, err)
+	require.Equal(t, bpfmaniov1alpha1.DefaultAttachPriority, got)
+}
+
+func TestNsBpfApplicationPodRecreated(t *testing.T) {
+	ctx := context.TODO()
+	fakeNode := testutils.NewNode("fake-control-plane")
+	bpfApp := &bpfmaniov1alpha1.BpfApplication{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      testAppProgramName,
+			Namespace: testNamespace,
+		},
+		Spec: bpfmaniov1alpha1.BpfApplicationSpec{
+			BpfAppCommon: bpfmaniov1alpha1.BpfAppCommon{
+				NodeSelector: metav1.LabelSelector{},
+				ByteCode: bpfmaniov1alpha1.ByteCodeSelector{
+					Path: ptr.To(testBytecodePath),
+				},
+			},
+			Programs: []bpfmaniov1alpha1.BpfApplicationProgram{{
+				Name: testXdpBpfFunctionName,
+				Type: bpfmaniov1alpha1.ProgTypeXDP,
+				XDP: &bpfmaniov1alpha1.XdpProgramInfo{
+					Links: []bpfmaniov1alpha1.XdpAttachInfo{{
+						InterfaceSelector: bpfmaniov1alpha1.InterfaceSelector{Interfaces: []string{fakeInt0}},
+						NetworkNamespaces: bpfmaniov1alpha1.NetworkNamespaceSelector{},
+						ProceedOn:         []bpfmaniov1alpha1.XdpProceedOnValue{"pass"},
+					}},
+				},
+			}},
+		},
+	}
+	pod := PodNetnsInfo{
+		podName:      fakePodName,
+		podNamespace: testNamespace,
+		podUID:       "pod-uid",
+		sandboxID:    "sandbox-1",
+		netnsPath:    netnsPathFromPID(1000),
+	}
+	testContainers := &FakeContainerGetter{podList: &[]PodNetnsInfo{pod}}
+	r := createFakeNamespaceReconciler([]runtime.Object{fakeNode, bpfApp}, bpfApp, fakeNode, testContainers)
+	req := reconcile.Request{
+		NamespacedName: types.NamespacedName{Name: testAppProgramName, Namespace: testNamespace},
+	}
+	getLinks := func() []bpfmaniov1alpha1.XdpAttachInfoState {
+		runReconciler(t, ctx, r, req, r.Logger)
+		runReconciler(t, ctx, r, req, r.Logger)
+		bpfAppState, err := r.getBpfAppState(ctx)
+		require.NoError(t, err)
+		return bpfAppState.Status.Programs[0].XDP.Links
+	}
+
+	links := getLinks()
+	require.Len(t, links, 1)
+	require.Equal(t, &bpfmaniov1alpha1.AttachPodState{
+		Name:      fakePodName,
+		Namespace: testNamespace,
+		UID:       "pod-uid",
+		SandboxID: "sandbox-1",
+	}, links[0].Pod)
+	require.Equal(t, bpfmaniov1alpha1.ApAttachAttached, links[0].LinkStatus)
+	oldLinkId := *links[0].LinkId
+
+	// The attach request identifies the Kubernetes objects of the link.
+	cli := r.BpfmanClient.(*agenttestutils.BpfmanClientFake)
+	require.Equal(t, map[string]string{
+		internal.UuidMetadataKey:         links[0].UUID,
+		internal.AppKindMetadataKey:      bpfApplicationKind,
+		internal.AppNamespaceMetadataKey: testNamespace,
+		internal.AppNameMetadataKey:      testAppProgramName,
+		internal.ProgramNameKey:          testXdpBpfFunctionName,
+		internal.PodNamespaceMetadataKey: testNamespace,
+		internal.PodNameMetadataKey:      fakePodName,
+		internal.PodUIDMetadataKey:       "pod-uid",
+	}, cli.AttachRequests[int(oldLinkId)].GetAttach().GetXdpAttachInfo().GetMetadata())
+
+	// The sandbox of the pod is recreated, so the link is attached again.
+	pod.sandboxID = "sandbox-2"
+	testContainers.podList = &[]PodNetnsInfo{pod}
+	links = getLinks()
+	require.Len(t, links, 1)
+	require.Equal(t, "sandbox-2", links[0].Pod.SandboxID)
+	require.Equal(t, bpfmaniov1alpha1.ApAttachAttached, links[0].LinkStatus)
+	require.NotEqual(t, oldLinkId, *links[0].LinkId)
+
+	// The pod is gone, so its link is removed.
+	testContainers.podList = &[]PodNetnsInfo{}
+	require.Empty(t, getLinks())
+}
+
+func TestNsBpfApplicationNetworkAttachment(t *testing.T) {
+	ctx := context.TODO()
+	fakeNode := testutils.NewNode("fake-control-plane")
+	bpfApp := &bpfmaniov1alpha1.BpfApplication{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      testAppProgramName,
+			Namespace: testNamespace,
+		},
+		Spec: bpfmaniov1alpha1.BpfApplicationSpec{
+			BpfAppCommon: bpfmaniov1alpha1.BpfAppCommon{
+				NodeSelector: metav1.LabelSelector{},
+				ByteCode: bpfmaniov1alpha1.ByteCodeSelector{
+					Path: ptr.To(testBytecodePath),
+				},
+			},
+			Programs: []bpfmaniov1alpha1.BpfApplicationProgram{{
+				Name: testXdpBpfFunctionName,
+				Type: bpfmaniov1alpha1.ProgTypeXDP,
+				XDP: &bpfmaniov1alpha1.XdpProgramInfo{
+					Links: []bpfmaniov1alpha1.XdpAttachInfo{{
+						// The interfaces come from the network attachment, so
+						// none are selected.
+						InterfaceSelector: bpfmaniov1alpha1.InterfaceSelector{},
+						NetworkNamespaces: bpfmaniov1alpha1.NetworkNamespaceSelector{
+							NetworkAttachment: &bpfmaniov1alpha1.NetworkAttachmentReference{Name: "sriov"},
+						},
+						ProceedOn: []bpfmaniov1alpha1.XdpProceedOnValue{"pass"},
+					}},
+				},
+			}},
+		},
+	}
+	testContainers := &FakeContainerGetter{podList: &[]PodNetnsInfo{{
+		podName:      fakePodName,
+		podNamespace: testNamespace,
+		podUID:       "pod-uid",
+		sandboxID:    "sandbox-1",
+		netnsPath:    netnsPathFromPID(1000),
+		networkStatus: `[
+			{"name": "ovn-kubernetes", "interface": "eth0", "default": true},
+			{"name": "` + testNamespace + `/sriov", "interface": "net1"},
+			{"name": "` + testNamespace + `/sriov", "interface": "net2"}
+		]`,
+	}}}
+	r := createFakeNamespaceReconciler([]runtime.Object{fakeNode, bpfApp}, bpfApp, fakeNode, testContainers)
+	req := reconcile.Request{
+		NamespacedName: types.NamespacedName{Name: testAppProgramName, Namespace: testNamespace},
+	}
+
+	runReconciler(t, ctx, r, req, r.Logger)
+	runReconciler(t, ctx, r, req, r.Logger)
+	bpfAppState, err := r.getBpfAppState(ctx)
+	require.NoError(t, err)
+
+	// The program is attached to the interfaces of the network attachment in
+	// the network namespace of the pod.
+	cli := r.BpfmanClient.(*agenttestutils.BpfmanClientFake)
+	links := bpfAppState.Status.Programs[0].XDP.Links
+	var interfaces []string
+	for _, link := range links {
+		require.Equal(t, bpfmaniov1alpha1.ApAttachAttached, link.LinkStatus)
+		require.Equal(t, netnsPathFromPID(1000), link.NetnsPath)
+		attachInfo := cli.AttachRequests[int(*link.LinkId)].GetAttach().GetXdpAttachInfo()
+		require.Equal(t, link.InterfaceName, attachInfo.GetIface())
+		interfaces = append(interfaces, link.InterfaceName)
+	}
+	require.ElementsMatch(t, []string{"net1", "net2"}, interfaces)
+}
+
+func TestNsBpfApplicationVerifierRejected(t *testing.T) {
+	ctx := context.TODO()
+	fakeNode := testutils.NewNode("fake-control-plane")
+	bpfApp := &bpfmaniov1alpha1.BpfApplication{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      testAppProgramName,
+			Namespace: testNamespace,
+		},
+		Spec: bpfmaniov1alpha1.BpfApplicationSpec{
+			BpfAppCommon: bpfmaniov1alpha1.BpfAppCommon{
+				NodeSelector: metav1.LabelSelector{},
+				ByteCode: bpfmaniov1alpha1.ByteCodeSelector{
+					Path: ptr.To(testBytecodePath),
+				},
+			},
+			Programs: []bpfmaniov1alpha1.BpfApplicationProgram{{
+				Name: testXdpBpfFunctionName,
+				Type: bpfmaniov1alpha1.ProgTypeXDP,
+				XDP: &bpfmaniov1alpha1.XdpProgramInfo{
+					Links: []bpfmaniov1alpha1.XdpAttachInfo{{
+						InterfaceSelector: bpfmaniov1alpha1.InterfaceSelector{Interfaces: []string{fakeInt0}},
+						NetworkNamespaces: bpfmaniov1alpha1.NetworkNamespaceSelector{},
+						ProceedOn:         []bpfmaniov1alpha1.XdpProceedOnValue{"pass"},
+					}},
+				},
+			}},
+		},
+	}
+	testContainers := &FakeContainerGetter{podList: &[]PodNetnsInfo{{
+		podName:      fakePodName,
+		podNamespace: testNamespace,
+		netnsPath:    netnsPathFromPID(1000),
+	}}}
+	r := createFakeNamespaceReconciler([]runtime.Object{fakeNode, bpfApp}, bpfApp, fakeNode, testContainers)
+	cli := r.BpfmanClient.(*agenttestutils.BpfmanClientFake)
+
+	verifierLog := strings.Repeat("0: (b7) r0 = 0\n", 400) + "R1 invalid mem access 'scalar'"
+	cli.LoadErr = status.Error(codes.Aborted,
+		"An error occurred. the BPF_PROG_LOAD syscall failed. Verifier output: "+verifierLog)
+
+	req := reconcile.Request{
+		NamespacedName: types.NamespacedName{Name: testAppProgramName, Namespace: testNamespace},
+	}
+
+	// The first reconcile creates the state, the second one fails to load.
+	runReconciler(t, ctx, r, req, r.Logger)
+	runReconciler(t, ctx, r, req, r.Logger)
+
+	bpfAppState, err := r.getBpfAppState(ctx)
+	require.NoError(t, err)
+	verifyBpfApplicationState(t, bpfAppState, fakeNode, testAppProgramName, bpfmaniov1alpha1.BpfAppStateCondVerifierRejected)
+
+	program := bpfAppState.Status.Programs[0]
+	require.Nil(t, program.ProgramId)
+	require.LessOrEqual(t, len(program.VerifierLog), verifierLogStatusLimit)
+	require.True(t, strings.HasSuffix(program.VerifierLog, "R1 invalid mem access 'scalar'"))
+
+	// The agent can't write ConfigMaps in the namespace of the application,
+	// so only the end of the log is kept.
+	require.Nil(t, program.VerifierLogRef)
+	cms := &v1.ConfigMapList{}
+	require.NoError(t, r.List(ctx, cms, client.InNamespace(testNamespace)))
+	require.Empty(t, cms.Items)
+
+	// Once the program loads the verifier log is cleared.
+	cli.LoadErr = nil
+	runReconciler(t, ctx, r, req, r.Logger)
+
+	bpfAppState, err = r.getBpfAppState(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, bpfAppState.Status.Programs[0].ProgramId)
+	require.Empty(t, bpfAppState.Status.Programs[0].VerifierLog)
+}
@@ -96,7 +96,7 @@ func (r *NsUprobeProgramReconciler) getAttachRequest() *gobpfman.AttachRequest {
 		Offset:   uint64(r.currentLink.Offset),
 		Target:   r.currentLink.Target,
 		Pid:      r.currentLink.Pid,
-		Metadata: map[string]string{internal.UuidMetadataKey: string(r.currentLink.UUID)},
+		Metadata: r.attachMetadata(r.currentLink.UUID, r.currentLink.Pod),
 	}
 
 	containerPid := int32(r.currentLink.ContainerPid)
@@ -140,8 +140,10 @@ func (r *NsUprobeProgramReconciler) updateLinks(ctx context.Context, isBeingDele
 		for _, link := range expectedLinks {
 			index := r.findLink(link, appStateLinks)
 			if index != nil {
-				// Link already exists, so set ShouldAttach to true.
+				// Link already exists, so set ShouldAttach to true and record
+				// its pod.
 				(*appStateLinks)[*index].AttachInfoStateCommon.ShouldAttach = true
+				(*appStateLinks)[*index].Pod = link.Pod
 			} else {
 				// Link doesn't exist, so add it.
 				r.Logger.Info("Link doesn't exist.  Adding it.")
@@ -162,11 +164,12 @@ func (r *NsUprobeProgramReconciler) findLink(attachInfoState bpfmaniov1alpha1.Up
 	links *[]bpfmaniov1alpha1.UprobeAttachInfoState) *int {
 	for i, a := range *links {
 		// attachInfoState is the same as a if the the following fields are the
-		// same: Function, Offset, Target, Pid, and ContainerPid.
+		// same: Function, Offset, Target, Pid, ContainerPid, and pod sandbox.
 		if a.Function == attachInfoState.Function && a.Offset == attachInfoState.Offset &&
 			a.Target == attachInfoState.Target &&
 			reflect.DeepEqual(a.Pid, attachInfoState.Pid) &&
-			a.ContainerPid == attachInfoState.ContainerPid {
+			a.ContainerPid == attachInfoState.ContainerPid &&
+			samePodSandbox(a.Pod, attachInfoState.Pod) {
 			return &i
 		}
 	}
@@ -301,6 +304,7 @@ func (r *NsUprobeProgramReconciler) getExpectedLinks(ctx context.Context, attach
 				Target:       attachInfo.Target,
 				Pid:          attachInfo.Pid,
 				ContainerPid: containerPid,
+				Pod:          container.podState(),
 			}
 			nodeLinks = append(nodeLinks, link)
 		}
@@ -23,7 +23,6 @@ import (
 
 	bpfmaniov1alpha1 "github.com/bpfman/bpfman-operator/apis/v1alpha1"
 	internal "github.com/bpfman/bpfman-operator/internal"
-	"github.com/bpfman/bpfman-operator/pkg/helpers"
 	gobpfman "github.com/bpfman/bpfman/clients/gobpfman/v1"
 	"github.com/google/uuid"
 )
@@ -100,7 +99,7 @@ func (r *NsXdpProgramReconciler) getAttachRequest() *gobpfman.AttachRequest {
 		Priority:  r.currentLink.Priority,
 		Iface:     r.currentLink.InterfaceName,
 		ProceedOn: xdpProceedOnToInt(r.currentLink.ProceedOn),
-		Metadata:  map[string]string{internal.UuidMetadataKey: string(r.currentLink.UUID)},
+		Metadata:  r.attachMetadata(r.currentLink.UUID, r.currentLink.Pod),
 		Netns:     &r.currentLink.NetnsPath,
 	}
 
@@ -144,8 +143,10 @@ func (r *NsXdpProgramReconciler) updateLinks(ctx context.Context, isBeingDeleted
 					continue
 				}
 				if index != nil {
-					// Link already exists, so set ShouldAttach to true.
+					// Link already exists, so set ShouldAttach to true and
+					// record its pod.
 					r.currentProgramState.XDP.Links[*index].AttachInfoStateCommon.ShouldAttach = true
+					r.currentProgramState.XDP.Links[*index].Pod = link.Pod
 				} else {
 					// Link doesn't exist, so add it.
 					r.currentProgramState.XDP.Links = append(r.currentProgramState.XDP.Links, link)
@@ -182,11 +183,13 @@ func (r *NsXdpProgramReconciler) findLink(attachInfoState bpfmaniov1alpha1.XdpAt
 	r.Logger.V(1).Info("findlink", "New Path", attachInfoState.NetnsPath, "NetnsId", newNetnsId)
 	for i, a := range r.currentProgramState.XDP.Links {
 		// attachInfoState is the same as a if the the following fields are the
-		// same: InterfaceName, Priority, ProceedOn, and network namespace.
+		// same: InterfaceName, Priority, ProceedOn, network namespace, and pod
+		// sandbox.
 		if a.InterfaceName == attachInfoState.InterfaceName &&
 			a.Priority == attachInfoState.Priority &&
 			reflect.DeepEqual(a.ProceedOn, attachInfoState.ProceedOn) &&
-			reflect.DeepEqual(r.NetNsCache.GetNetNsId(a.NetnsPath), newNetnsId) {
+			reflect.DeepEqual(r.NetNsCache.GetNetNsId(a.NetnsPath), newNetnsId) &&
+			samePodSandbox(a.Pod, attachInfoState.Pod) {
 			return &i, nil
 		}
 	}
@@ -257,47 +260,59 @@ func (r *NsXdpProgramReconciler) removeLinks(links []bpfmaniov1alpha1.XdpAttachI
 // points.
 func (r *NsXdpProgramReconciler) getExpectedLinks(ctx context.Context, attachInfo bpfmaniov1alpha1.XdpAttachInfo,
 ) ([]bpfmaniov1alpha1.XdpAttachInfoState, error) {
-	interfaces, err := getInterfaces(&attachInfo.InterfaceSelector, r.ourNode)
+	priority, err := r.getLinkPriority(ctx, r.getNamespace(), attachInfo.PriorityClassName, attachInfo.Priority)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get interfaces for XdpNsProgram: %v", err)
+		return nil, err
+	}
+
+	// With interface discovery, the interfaces are discovered in the network
+	// namespace of each pod instead, and with a network attachment, they come
+	// from the attachment in each pod.
+	var interfaces []string
+	if !isInterfacesDiscoveryEnabled(&attachInfo.InterfaceSelector) && attachInfo.NetworkNamespaces.NetworkAttachment == nil {
+		interfaces, err = getInterfaces(&attachInfo.InterfaceSelector, r.ourNode)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get interfaces for XdpNsProgram: %v", err)
+		}
 	}
 
 	nodeLinks := []bpfmaniov1alpha1.XdpAttachInfoState{}
 
 	// There is a network namespace selector, so see if there are any matching
 	// pods on this node.
-	containerInfo, err := r.Containers.GetContainers(
+	pods, err := r.Containers.GetPodNetworkNamespaces(
 		ctx,
 		r.getNamespace(),
 		attachInfo.NetworkNamespaces.Pods,
-		nil,
 		r.Logger,
 	)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get container pids: %v", err)
+		return nil, fmt.Errorf("failed to get pod network namespaces: %v", err)
 	}
 
-	if containerInfo != nil {
-		// Just use one container per pod to get the pod's network
-		// namespace.
-		containerInfo = GetOneContainerPerPod(containerInfo)
-		for _, container := range *containerInfo {
-			netnsPath := netnsPathFromPID(container.pid)
-			for _, iface := range interfaces {
-				link := bpfmaniov1alpha1.XdpAttachInfoState{
-					AttachInfoStateCommon: bpfmaniov1alpha1.AttachInfoStateCommon{
-						ShouldAttach: true,
-						UUID:         uuid.New().String(),
-						LinkId:       nil,
-						LinkStatus:   bpfmaniov1alpha1.ApAttachNotAttached,
-					},
-					InterfaceName: iface,
-					NetnsPath:     netnsPath,
-					Priority:      helpers.GetPriority(attachInfo.Priority),
-					ProceedOn:     attachInfo.ProceedOn,
-				}
-				nodeLinks = append(nodeLinks, link)
+	// Pods that use the network namespace of the node are skipped.
+	selectedPods := selectPodNetns(pods, bpfmaniov1alpha1.HostNetworkPodSkip)
+	podsInterfaces, err := r.getPodsInterfaces(&attachInfo.InterfaceSelector,
+		attachInfo.NetworkNamespaces.NetworkAttachment, interfaces, selectedPods)
+	if err != nil {
+		return nil, err
+	}
+	for i, pod := range selectedPods {
+		for _, iface := range podsInterfaces[i] {
+			link := bpfmaniov1alpha1.XdpAttachInfoState{
+				AttachInfoStateCommon: bpfmaniov1alpha1.AttachInfoStateCommon{
+					ShouldAttach: true,
+					UUID:         uuid.New().String(),
+					LinkId:       nil,
+					LinkStatus:   bpfmaniov1alpha1.ApAttachNotAttached,
+				},
+				InterfaceName: iface,
+				NetnsPath:     pod.netnsPath,
+				Pod:           pod.podState(),
+				Priority:      priority,
+				ProceedOn:     attachInfo.ProceedOn,
 			}
+			nodeLinks = append(nodeLinks, link)
 		}
 	}
 
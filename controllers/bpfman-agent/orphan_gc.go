@@ -0,0 +1,147 @@
+/*
+Copyright 2025 The bpfman Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bpfmanagent
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	bpfmaniov1alpha1 "github.com/bpfman/bpfman-operator/apis/v1alpha1"
+	bpfmanagentinternal "github.com/bpfman/bpfman-operator/controllers/bpfman-agent/internal"
+	"github.com/bpfman/bpfman-operator/internal"
+	gobpfman "github.com/bpfman/bpfman/clients/gobpfman/v1"
+	"github.com/go-logr/logr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// OrphanCollector unloads, once when the agent starts, the programs the
+// agent loaded through bpfman for BpfApplicationStates that no longer
+// exist, e.g., because a state was deleted while the agent was down.
+//
+// A program is an orphan when it has both the bpfman.io/uuid and the
+// bpfman.io/ProgramName metadata set by the agent, and neither its UUID nor
+// its ID is recorded in a BpfApplicationState of this node. Programs whose
+// UUID is the adoptUUID of a ClusterBpfApplication are left for it to adopt.
+type OrphanCollector struct {
+	client.Client
+	BpfmanClient gobpfman.BpfmanClient
+	AuditLog     *bpfmanagentinternal.AuditLog
+	NodeName     string
+	// DryRun only reports the orphans.
+	DryRun bool
+	Logger logr.Logger
+}
+
+// NewOrphanCollector returns an OrphanCollector for the programs of
+// nodeName.
+func NewOrphanCollector(c client.Client, bpfmanClient gobpfman.BpfmanClient, auditLog *bpfmanagentinternal.AuditLog,
+	nodeName string, dryRun bool) *OrphanCollector {
+	return &OrphanCollector{
+		Client:       c,
+		BpfmanClient: bpfmanClient,
+		AuditLog:     auditLog,
+		NodeName:     nodeName,
+		DryRun:       dryRun,
+		Logger:       ctrl.Log.WithName("orphan-gc"),
+	}
+}
+
+// Start collects the orphans once. It runs after the caches are synced,
+// and failures are logged rather than stopping the agent.
+func (o *OrphanCollector) Start(ctx context.Context) error {
+	orphans, err := o.Collect(ctx)
+	if err != nil {
+		o.Logger.Error(err, "failed to collect orphaned programs")
+		return nil
+	}
+	o.Logger.Info("Collected orphaned programs", "ProgramIds", orphans, "DryRun", o.DryRun)
+	return nil
+}
+
+// NeedLeaderElection returns false because every agent collects the
+// orphans of its own node.
+func (o *OrphanCollector) NeedLeaderElection() bool {
+	return false
+}
+
+// Collect unloads the orphaned programs, unless DryRun is set, and returns
+// their IDs.
+func (o *OrphanCollector) Collect(ctx context.Context) ([]uint32, error) {
+	// The programs are listed before the states so that the programs loaded
+	// in between, for states that are not listed, are not orphans.
+	programs, err := bpfmanagentinternal.ListBpfmanProgramsByOwner(ctx, o.BpfmanClient)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list programs: %w", err)
+	}
+
+	managedIDs, managedUIDs, err := managedPrograms(ctx, o.Client, o.NodeName)
+	if err != nil {
+		return nil, err
+	}
+	adoptUUIDs, err := o.adoptUUIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var orphans []uint32
+	for uuid, results := range programs {
+		if managedUIDs[uuid] || adoptUUIDs[uuid] {
+			continue
+		}
+		var ids []uint32
+		for _, result := range results {
+			id := result.GetKernelInfo().GetId()
+			if _, ok := result.GetInfo().GetMetadata()[internal.ProgramNameKey]; !ok || managedIDs[id] {
+				continue
+			}
+			ids = append(ids, id)
+		}
+		// Unload in reverse order because the first program is the map
+		// owner and subsequent programs may share its maps.
+		sort.Slice(ids, func(i, j int) bool { return ids[i] > ids[j] })
+		for _, id := range ids {
+			o.Logger.Info("Found orphaned program", "ProgramId", id, "UUID", uuid, "DryRun", o.DryRun)
+			if !o.DryRun {
+				if err := bpfmanagentinternal.UnloadBpfmanProgram(ctx, o.BpfmanClient, o.AuditLog, id); err != nil {
+					return orphans, err
+				}
+			}
+			orphans = append(orphans, id)
+		}
+	}
+	sort.Slice(orphans, func(i, j int) bool { return orphans[i] < orphans[j] })
+	return orphans, nil
+}
+
+// adoptUUIDs returns the UUIDs of the programs the ClusterBpfApplications
+// adopt.
+func (o *OrphanCollector) adoptUUIDs(ctx context.Context) (map[string]bool, error) {
+	uuids := map[string]bool{}
+
+	clApps := &bpfmaniov1alpha1.ClusterBpfApplicationList{}
+	if err := o.List(ctx, clApps); err != nil {
+		return nil, fmt.Errorf("failed to list ClusterBpfApplications: %w", err)
+	}
+	for _, app := range clApps.Items {
+		if app.Spec.AdoptUUID != "" {
+			uuids[app.Spec.AdoptUUID] = true
+		}
+	}
+	return uuids, nil
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2025 The bpfman Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bpfmanagent
+
+import (
+	"context"
+	"testing"
+
+	bpfmaniov1alpha1 "github.com/bpfman/bpfman-operator/apis/v1alpha1"
+	agenttestutils "github.com/bpfman/bpfman-operator/controllers/bpfman-agent/internal/test-utils"
+	"github.com/bpfman/bpfman-operator/internal"
+	gobpfman "github.com/bpfman/bpfman/clients/gobpfman/v1"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestOrphanCollectorCollect(t *testing.T) {
+	ctx := context.TODO()
+	nodeName := "fake-control-plane"
+
+	s := scheme.Scheme
+	s.AddKnownTypes(bpfmaniov1alpha1.SchemeGroupVersion,
+		&bpfmaniov1alpha1.ClusterBpfApplication{},
+		&bpfmaniov1alpha1.ClusterBpfApplicationList{},
+		&bpfmaniov1alpha1.BpfApplication{},
+		&bpfmaniov1alpha1.BpfApplicationList{},
+		&bpfmaniov1alpha1.ClusterBpfApplicationState{},
+		&bpfmaniov1alpha1.ClusterBpfApplicationStateList{},
+		&bpfmaniov1alpha1.BpfApplicationState{},
+		&bpfmaniov1alpha1.BpfApplicationStateList{},
+	)
+
+	appState := &bpfmaniov1alpha1.BpfApplicationState{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: testNamespace,
+			Name:      "app-state",
+			UID:       "app-state-uid",
+			Labels:    map[string]string{internal.K8sHostLabel: nodeName},
+		},
+	}
+	adoptingApp := &bpfmaniov1alpha1.ClusterBpfApplication{
+		ObjectMeta: metav1.ObjectMeta{Name: "adopting-app"},
+		Spec: bpfmaniov1alpha1.ClBpfApplicationSpec{
+			AdoptUUID: "adopted-uuid",
+		},
+	}
+	// The adopted program is recorded with its ID.
+	adoptingState := &bpfmaniov1alpha1.ClusterBpfApplicationState{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "adopting-app-state",
+			UID:    "adopting-app-state-uid",
+			Labels: map[string]string{internal.K8sHostLabel: nodeName},
+		},
+		Status: bpfmaniov1alpha1.ClBpfApplicationStateStatus{
+			Programs: []bpfmaniov1alpha1.ClBpfApplicationProgramState{{
+				BpfProgramStateCommon: bpfmaniov1alpha1.BpfProgramStateCommon{
+					Name:      "previously_adopted",
+					ProgramId: ptr.To(uint32(30)),
+				},
+			}},
+		},
+	}
+	cl := fake.NewClientBuilder().WithScheme(s).
+		WithObjects(appState, adoptingApp, adoptingState).Build()
+
+	program := func(id uint32, metadata map[string]string) *gobpfman.GetResponse {
+		return &gobpfman.GetResponse{
+			Info:       &gobpfman.ProgramInfo{Name: "prog", Metadata: metadata},
+			KernelInfo: &gobpfman.KernelProgramInfo{Id: id},
+		}
+	}
+	loadedBy := func(uuid string) map[string]string {
+		return map[string]string{internal.UuidMetadataKey: uuid, internal.ProgramNameKey: "app"}
+	}
+	cli := agenttestutils.NewBpfmanClientFakeWithPrograms(map[int]*gobpfman.GetResponse{
+		10: program(10, loadedBy("app-state-uid")),
+		// The programs of a state deleted while the agent was down.
+		20: program(20, loadedBy("deleted-state-uid")),
+		21: program(21, loadedBy("deleted-state-uid")),
+		// Programs loaded through bpfman by other tools, adopted or not.
+		30: program(30, map[string]string{internal.UuidMetadataKey: "other-tool-uuid", internal.ProgramNameKey: "other"}),
+		31: program(31, map[string]string{internal.UuidMetadataKey: "other-tool-uuid"}),
+		32: program(32, loadedBy("adopted-uuid")),
+		// Not loaded through bpfman.
+		40: {KernelInfo: &gobpfman.KernelProgramInfo{Id: 40}},
+	})
+
+	// A dry run only reports the orphans.
+	collector := NewOrphanCollector(cl, cli, nil, nodeName, true)
+	orphans, err := collector.Collect(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []uint32{20, 21}, orphans)
+	require.Empty(t, cli.UnloadRequests)
+
+	collector.DryRun = false
+	orphans, err = collector.Collect(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []uint32{20, 21}, orphans)
+	require.Len(t, cli.UnloadRequests, 2)
+	require.Contains(t, cli.UnloadRequests, 20)
+	require.Contains(t, cli.UnloadRequests, 21)
+	require.Len(t, cli.Programs, 5)
+
+	orphans, err = collector.Collect(ctx)
+	require.NoError(t, err)
+	require.Empty(t, orphans)
+}
@@ -0,0 +1,251 @@
+/*
+Copyright 2025 The bpfman Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bpfmanagent
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	bpfmaniov1alpha1 "github.com/bpfman/bpfman-operator/apis/v1alpha1"
+	bpfmanagentinternal "github.com/bpfman/bpfman-operator/controllers/bpfman-agent/internal"
+	"github.com/bpfman/bpfman-operator/internal"
+	gobpfman "github.com/bpfman/bpfman/clients/gobpfman/v1"
+	"github.com/go-logr/logr"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// +kubebuilder:rbac:groups=bpfman.io,resources=bpfprograminventories,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=bpfman.io,resources=bpfprograminventories/status,verbs=get;update;patch
+
+// dispatcherNames are the names of the dispatcher programs bpfman loads to
+// attach several XDP and TC programs to an interface. They are part of the
+// managed programs and not reported.
+var dispatcherNames = map[string]bool{
+	"xdp_dispatcher": true,
+	"tc_dispatcher":  true,
+}
+
+// ProgramInventory periodically publishes the eBPF programs loaded on this
+// node that don't belong to a ClusterBpfApplication or BpfApplication, and
+// the XDP and TC dispatcher chains of the node, in the BpfProgramInventory
+// named after the node.
+type ProgramInventory struct {
+	client.Client
+	BpfmanClient gobpfman.BpfmanClient
+	NodeName     string
+	Interval     time.Duration
+	Logger       logr.Logger
+}
+
+// NewProgramInventory returns a ProgramInventory refreshing the inventory of
+// nodeName every interval.
+func NewProgramInventory(c client.Client, bpfmanClient gobpfman.BpfmanClient, nodeName string, interval time.Duration) *ProgramInventory {
+	return &ProgramInventory{
+		Client:       c,
+		BpfmanClient: bpfmanClient,
+		NodeName:     nodeName,
+		Interval:     interval,
+		Logger:       ctrl.Log.WithName("program-inventory"),
+	}
+}
+
+// Start updates the inventory until ctx is cancelled. Failed updates are
+// logged and retried on the next interval.
+func (p *ProgramInventory) Start(ctx context.Context) error {
+	ticker := time.NewTicker(p.Interval)
+	defer ticker.Stop()
+	for {
+		if err := p.Update(ctx); err != nil {
+			p.Logger.Error(err, "failed to update program inventory")
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// NeedLeaderElection returns false because every agent publishes the
+// inventory of its own node.
+func (p *ProgramInventory) NeedLeaderElection() bool {
+	return false
+}
+
+// Update lists the programs loaded on the node and records the unmanaged
+// ones, and the dispatcher chains, in the node's BpfProgramInventory.
+func (p *ProgramInventory) Update(ctx context.Context) error {
+	managedIDs, managedUIDs, err := managedPrograms(ctx, p.Client, p.NodeName)
+	if err != nil {
+		return err
+	}
+
+	results, err := bpfmanagentinternal.ListAllPrograms(ctx, p.BpfmanClient)
+	if err != nil {
+		return fmt.Errorf("failed to list programs: %w", err)
+	}
+
+	status := bpfmaniov1alpha1.BpfProgramInventoryStatus{Node: p.NodeName}
+	for _, result := range results {
+		kernelInfo := result.GetKernelInfo()
+		info := result.GetInfo()
+		uuid := info.GetMetadata()[internal.UuidMetadataKey]
+		if managedIDs[kernelInfo.GetId()] || (uuid != "" && managedUIDs[uuid]) {
+			continue
+		}
+		if info == nil && dispatcherNames[kernelInfo.GetName()] {
+			continue
+		}
+		program := bpfmaniov1alpha1.UnmanagedProgram{
+			ID:             kernelInfo.GetId(),
+			Name:           kernelInfo.GetName(),
+			Type:           internal.ProgramType(kernelInfo.GetProgramType()).String(),
+			Tag:            kernelInfo.GetTag(),
+			LoadedAt:       kernelInfo.GetLoadedAt(),
+			MapIDs:         kernelInfo.GetMapIds(),
+			LoadedByBpfman: info != nil,
+			UUID:           uuid,
+			Metadata:       info.GetMetadata(),
+			LinkIDs:        info.GetLinks(),
+		}
+		if info.GetName() != "" {
+			program.Name = info.GetName()
+		}
+		status.Programs = append(status.Programs, program)
+	}
+	sort.Slice(status.Programs, func(i, j int) bool {
+		return status.Programs[i].ID < status.Programs[j].ID
+	})
+
+	status.Dispatchers, err = dispatcherChains(ctx, p.Client, p.NodeName)
+	if err != nil {
+		return err
+	}
+
+	inventory, err := p.getOrCreateInventory(ctx)
+	if err != nil {
+		return err
+	}
+	status.Conditions = append([]metav1.Condition(nil), inventory.Status.Conditions...)
+	conflict := metav1.Condition{
+		Type:    string(bpfmaniov1alpha1.BpfProgramInventoryConditionPriorityConflict),
+		Status:  metav1.ConditionFalse,
+		Reason:  noPriorityConflictReason,
+		Message: "No programs of different applications share a priority on a dispatcher",
+	}
+	if conflicts := priorityConflicts(status.Dispatchers); len(conflicts) > 0 {
+		conflict.Status = metav1.ConditionTrue
+		conflict.Reason = priorityConflictReason
+		conflict.Message = strings.Join(conflicts, "; ") +
+			"; their order is decided by bpfman and may differ from the inferred positions"
+		p.Logger.Info("Programs of different applications share a priority", "Conflicts", conflicts)
+	}
+	meta.SetStatusCondition(&status.Conditions, conflict)
+	if equality.Semantic.DeepEqual(inventory.Status, status) {
+		return nil
+	}
+	inventory.Status = status
+	if err := p.Status().Update(ctx, inventory); err != nil {
+		return fmt.Errorf("failed to update BpfProgramInventory %s: %w", inventory.Name, err)
+	}
+	p.Logger.V(1).Info("Updated program inventory", "Programs", len(status.Programs))
+	return nil
+}
+
+// managedPrograms returns the IDs of the programs recorded in the
+// BpfApplicationStates of nodeName, and the UIDs of these states, which
+// the programs loaded for them carry in their bpfman.io/uuid metadata.
+func managedPrograms(ctx context.Context, reader client.Reader, nodeName string) (map[uint32]bool, map[string]bool, error) {
+	ids := map[uint32]bool{}
+	uids := map[string]bool{}
+	addProgram := func(program bpfmaniov1alpha1.BpfProgramStateCommon) {
+		if program.ProgramId != nil {
+			ids[*program.ProgramId] = true
+		}
+	}
+	opts := []client.ListOption{client.MatchingLabels{internal.K8sHostLabel: nodeName}}
+
+	clStates := &bpfmaniov1alpha1.ClusterBpfApplicationStateList{}
+	if err := reader.List(ctx, clStates, opts...); err != nil {
+		return nil, nil, fmt.Errorf("failed to list ClusterBpfApplicationStates: %w", err)
+	}
+	for _, state := range clStates.Items {
+		uids[string(state.UID)] = true
+		for _, program := range state.Status.Programs {
+			addProgram(program.BpfProgramStateCommon)
+		}
+	}
+
+	nsStates := &bpfmaniov1alpha1.BpfApplicationStateList{}
+	if err := reader.List(ctx, nsStates, opts...); err != nil {
+		return nil, nil, fmt.Errorf("failed to list BpfApplicationStates: %w", err)
+	}
+	for _, state := range nsStates.Items {
+		uids[string(state.UID)] = true
+		for _, program := range state.Status.Programs {
+			addProgram(program.BpfProgramStateCommon)
+		}
+	}
+	return ids, uids, nil
+}
+
+// getOrCreateInventory returns the BpfProgramInventory of this node,
+// creating it owned by the Node so that it is deleted with it.
+func (p *ProgramInventory) getOrCreateInventory(ctx context.Context) (*bpfmaniov1alpha1.BpfProgramInventory, error) {
+	inventory := &bpfmaniov1alpha1.BpfProgramInventory{}
+	err := p.Get(ctx, types.NamespacedName{Name: p.NodeName}, inventory)
+	if err == nil {
+		return inventory, nil
+	}
+	if !errors.IsNotFound(err) {
+		return nil, fmt.Errorf("failed to get BpfProgramInventory %s: %w", p.NodeName, err)
+	}
+
+	node := &v1.Node{}
+	if err := p.Get(ctx, types.NamespacedName{Name: p.NodeName}, node); err != nil {
+		return nil, fmt.Errorf("failed to get node %s: %w", p.NodeName, err)
+	}
+	inventory = &bpfmaniov1alpha1.BpfProgramInventory{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: p.NodeName,
+			Labels: map[string]string{
+				internal.K8sHostLabel:    p.NodeName,
+				internal.DiscoveredLabel: "true",
+			},
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: "v1",
+				Kind:       "Node",
+				Name:       node.Name,
+				UID:        node.UID,
+			}},
+		},
+	}
+	if err := p.Create(ctx, inventory); err != nil {
+		return nil, fmt.Errorf("failed to create BpfProgramInventory %s: %w", p.NodeName, err)
+	}
+	return inventory, nil
+}
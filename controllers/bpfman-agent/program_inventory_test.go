@@ -0,0 +1,139 @@
+/*
+Copyright 2025 The bpfman Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bpfmanagent
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	bpfmaniov1alpha1 "github.com/bpfman/bpfman-operator/apis/v1alpha1"
+	agenttestutils "github.com/bpfman/bpfman-operator/controllers/bpfman-agent/internal/test-utils"
+	"github.com/bpfman/bpfman-operator/internal"
+	testutils "github.com/bpfman/bpfman-operator/internal/test-utils"
+	gobpfman "github.com/bpfman/bpfman/clients/gobpfman/v1"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestProgramInventoryUpdate(t *testing.T) {
+	ctx := context.TODO()
+	fakeNode := testutils.NewNode("fake-control-plane")
+
+	s := scheme.Scheme
+	s.AddKnownTypes(bpfmaniov1alpha1.SchemeGroupVersion,
+		&bpfmaniov1alpha1.BpfProgramInventory{},
+		&bpfmaniov1alpha1.BpfProgramInventoryList{},
+		&bpfmaniov1alpha1.ClusterBpfApplicationState{},
+		&bpfmaniov1alpha1.ClusterBpfApplicationStateList{},
+		&bpfmaniov1alpha1.BpfApplicationState{},
+		&bpfmaniov1alpha1.BpfApplicationStateList{},
+	)
+
+	appState := &bpfmaniov1alpha1.ClusterBpfApplicationState{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "app-state",
+			UID:    "app-state-uid",
+			Labels: map[string]string{internal.K8sHostLabel: fakeNode.Name},
+		},
+		Status: bpfmaniov1alpha1.ClBpfApplicationStateStatus{
+			Programs: []bpfmaniov1alpha1.ClBpfApplicationProgramState{{
+				BpfProgramStateCommon: bpfmaniov1alpha1.BpfProgramStateCommon{
+					Name:      "managed",
+					ProgramId: ptr.To(uint32(10)),
+				},
+			}},
+		},
+	}
+
+	cl := fake.NewClientBuilder().WithScheme(s).
+		WithStatusSubresource(&bpfmaniov1alpha1.BpfProgramInventory{}).
+		WithObjects(fakeNode, appState).Build()
+	cli := agenttestutils.NewBpfmanClientFakeWithPrograms(map[int]*gobpfman.GetResponse{
+		// Recorded in the ClusterBpfApplicationState.
+		10: {
+			Info:       &gobpfman.ProgramInfo{Name: "managed"},
+			KernelInfo: &gobpfman.KernelProgramInfo{Id: 10, Name: "managed"},
+		},
+		// Loaded for the ClusterBpfApplicationState but not recorded yet.
+		11: {
+			Info: &gobpfman.ProgramInfo{
+				Name:     "loading",
+				Metadata: map[string]string{internal.UuidMetadataKey: "app-state-uid"},
+			},
+			KernelInfo: &gobpfman.KernelProgramInfo{Id: 11, Name: "loading"},
+		},
+		12: {
+			KernelInfo: &gobpfman.KernelProgramInfo{Id: 12, Name: "xdp_dispatcher", ProgramType: uint32(internal.Xdp)},
+		},
+		21: {
+			Info: &gobpfman.ProgramInfo{
+				Name:     "external_program",
+				Metadata: map[string]string{internal.UuidMetadataKey: "external-uuid", "owner": "cli"},
+				Links:    []uint32{7},
+			},
+			KernelInfo: &gobpfman.KernelProgramInfo{
+				Id:          21,
+				Name:        "external_progra",
+				ProgramType: uint32(internal.Tc),
+				Tag:         "abcdef",
+				MapIds:      []uint32{3, 4},
+			},
+		},
+		20: {
+			KernelInfo: &gobpfman.KernelProgramInfo{Id: 20, Name: "cilium_prog", ProgramType: uint32(internal.SchedAct)},
+		},
+	})
+
+	inventory := NewProgramInventory(cl, cli, fakeNode.Name, time.Minute)
+	require.NoError(t, inventory.Update(ctx))
+
+	got := &bpfmaniov1alpha1.BpfProgramInventory{}
+	require.NoError(t, cl.Get(ctx, types.NamespacedName{Name: fakeNode.Name}, got))
+	require.Equal(t, fakeNode.Name, got.Labels[internal.K8sHostLabel])
+	require.Equal(t, "Node", got.OwnerReferences[0].Kind)
+	require.Equal(t, fakeNode.Name, got.Status.Node)
+	require.Equal(t, []bpfmaniov1alpha1.UnmanagedProgram{
+		{
+			ID:   20,
+			Name: "cilium_prog",
+			Type: "sched_act",
+		},
+		{
+			ID:             21,
+			Name:           "external_program",
+			Type:           "tc",
+			Tag:            "abcdef",
+			MapIDs:         []uint32{3, 4},
+			LoadedByBpfman: true,
+			UUID:           "external-uuid",
+			Metadata:       map[string]string{internal.UuidMetadataKey: "external-uuid", "owner": "cli"},
+			LinkIDs:        []uint32{7},
+		},
+	}, got.Status.Programs)
+
+	// Unloaded programs are removed from the inventory.
+	delete(cli.Programs, 20)
+	require.NoError(t, inventory.Update(ctx))
+	require.NoError(t, cl.Get(ctx, types.NamespacedName{Name: fakeNode.Name}, got))
+	require.Len(t, got.Status.Programs, 1)
+	require.Equal(t, uint32(21), got.Status.Programs[0].ID)
+}
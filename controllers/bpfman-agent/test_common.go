@@ -60,6 +60,7 @@ const (
 
 type FakeContainerGetter struct {
 	containerList *[]ContainerInfo
+	podList       *[]PodNetnsInfo
 }
 
 func (f *FakeContainerGetter) GetContainers(
@@ -72,6 +73,15 @@ func (f *FakeContainerGetter) GetContainers(
 	return f.containerList, nil
 }
 
+func (f *FakeContainerGetter) GetPodNetworkNamespaces(
+	ctx context.Context,
+	selectorNamespace string,
+	selectorPods metav1.LabelSelector,
+	logger logr.Logger,
+) (*[]PodNetnsInfo, error) {
+	return f.podList, nil
+}
+
 func TestGetPods(t *testing.T) {
 	ctx := context.TODO()
 
@@ -192,11 +202,15 @@ func registerBpfApplicationScheme(s *runtime.Scheme, isClusterScoped bool, bpfAp
 		s.AddKnownTypes(bpfmaniov1alpha1.SchemeGroupVersion, &bpfmaniov1alpha1.ClusterBpfApplicationList{})
 		s.AddKnownTypes(bpfmaniov1alpha1.SchemeGroupVersion, &bpfmaniov1alpha1.ClusterBpfApplicationState{})
 		s.AddKnownTypes(bpfmaniov1alpha1.SchemeGroupVersion, &bpfmaniov1alpha1.ClusterBpfApplicationStateList{})
+		// The namespaced states own programs that can't be adopted.
+		s.AddKnownTypes(bpfmaniov1alpha1.SchemeGroupVersion, &bpfmaniov1alpha1.BpfApplicationState{})
+		s.AddKnownTypes(bpfmaniov1alpha1.SchemeGroupVersion, &bpfmaniov1alpha1.BpfApplicationStateList{})
 	} else {
 		s.AddKnownTypes(bpfmaniov1alpha1.SchemeGroupVersion, &bpfmaniov1alpha1.BpfApplication{})
 		s.AddKnownTypes(bpfmaniov1alpha1.SchemeGroupVersion, &bpfmaniov1alpha1.BpfApplicationList{})
 		s.AddKnownTypes(bpfmaniov1alpha1.SchemeGroupVersion, &bpfmaniov1alpha1.BpfApplicationState{})
 		s.AddKnownTypes(bpfmaniov1alpha1.SchemeGroupVersion, &bpfmaniov1alpha1.BpfApplicationStateList{})
+		s.AddKnownTypes(bpfmaniov1alpha1.SchemeGroupVersion, &bpfmaniov1alpha1.BpfPriorityClassList{})
 	}
 	s.AddKnownTypes(bpfmaniov1alpha1.SchemeGroupVersion, bpfApp)
 }
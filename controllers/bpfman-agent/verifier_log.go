@@ -0,0 +1,175 @@
+/*
+Copyright 2025 The bpfman Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bpfmanagent
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	bpfmaniov1alpha1 "github.com/bpfman/bpfman-operator/apis/v1alpha1"
+	bpfmanagentinternal "github.com/bpfman/bpfman-operator/controllers/bpfman-agent/internal"
+	"github.com/bpfman/bpfman-operator/internal"
+)
+
+// +kubebuilder:rbac:groups=core,namespace=bpfman,resources=configmaps,verbs=get;create;update;delete
+
+const (
+	// verifierLogStatusLimit is the maximum length of the verifier log kept
+	// in the program status. It matches the validation of the verifierLog
+	// field.
+	verifierLogStatusLimit = 4096
+	// verifierLogConfigMapLimit bounds the total size of the verifier logs
+	// stored in a ConfigMap, which must stay below the 1MiB object limit.
+	verifierLogConfigMapLimit = 900 * 1024
+)
+
+// verifierLogConfigMapName returns the name of the ConfigMap holding the full
+// verifier logs of the programs of the given BpfApplicationState.
+func verifierLogConfigMapName(appStateName string) string {
+	return appStateName + "-verifier-log"
+}
+
+// recordVerifierLog records the verifier output carried by loadErr in the
+// given program states. The status keeps the end of the log, and logs longer
+// than verifierLogStatusLimit are stored in full in a ConfigMap in namespace
+// owned by appState. When namespace is empty, as for namespaced applications,
+// whose namespaces the agent can't write to, only the end of the log is kept.
+// If loadErr is not a verifier rejection, stale verifier logs are cleared
+// instead.
+func (r *ReconcilerCommon) recordVerifierLog(
+	ctx context.Context,
+	appState client.Object,
+	namespace string,
+	programs []*bpfmaniov1alpha1.BpfProgramStateCommon,
+	loadErr error,
+) {
+	var verr *bpfmanagentinternal.VerifierError
+	if !errors.As(loadErr, &verr) {
+		r.clearVerifierLog(ctx, appState, namespace, programs)
+		return
+	}
+
+	r.Logger.Info("Program rejected by the verifier", "Programs", verr.Programs)
+
+	stored := false
+	data := map[string]string{}
+	for _, program := range programs {
+		if program.VerifierLogRef != nil {
+			stored = true
+		}
+		if !slices.Contains(verr.Programs, program.Name) {
+			program.VerifierLog = ""
+			program.VerifierLogRef = nil
+			continue
+		}
+		program.VerifierLog = bpfmanagentinternal.TruncateVerifierLog(verr.Log, verifierLogStatusLimit)
+		program.VerifierLogRef = nil
+		if namespace != "" && len(verr.Log) > verifierLogStatusLimit {
+			data[program.Name] = verr.Log
+		}
+	}
+
+	if len(data) == 0 {
+		if stored && namespace != "" {
+			r.deleteVerifierLogConfigMap(ctx, appState, namespace)
+		}
+		return
+	}
+
+	for key, verifierLog := range data {
+		data[key] = bpfmanagentinternal.TruncateVerifierLog(verifierLog, verifierLogConfigMapLimit/len(data))
+	}
+
+	cm := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      verifierLogConfigMapName(appState.GetName()),
+			Namespace: namespace,
+		},
+	}
+	_, err := controllerutil.CreateOrUpdate(ctx, r.Client, cm, func() error {
+		if cm.Labels == nil {
+			cm.Labels = map[string]string{}
+		}
+		cm.Labels[internal.K8sHostLabel] = r.NodeName
+		cm.Data = data
+		return controllerutil.SetOwnerReference(appState, cm, r.Scheme)
+	})
+	if err != nil {
+		// The end of the log is still reported in the status.
+		r.Logger.Error(err, "failed to store verifier log", "ConfigMap", cm.Name, "Namespace", namespace)
+		return
+	}
+
+	for _, program := range programs {
+		if _, ok := data[program.Name]; ok {
+			program.VerifierLogRef = &bpfmaniov1alpha1.VerifierLogReference{
+				Namespace: namespace,
+				Name:      cm.Name,
+				Key:       program.Name,
+			}
+		}
+	}
+}
+
+// clearVerifierLog removes the verifier logs from the given program states
+// and deletes the ConfigMap holding the full logs, if any.
+func (r *ReconcilerCommon) clearVerifierLog(
+	ctx context.Context,
+	appState client.Object,
+	namespace string,
+	programs []*bpfmaniov1alpha1.BpfProgramStateCommon,
+) {
+	stored := false
+	for _, program := range programs {
+		if program.VerifierLogRef != nil {
+			stored = true
+		}
+		program.VerifierLog = ""
+		program.VerifierLogRef = nil
+	}
+	if stored && namespace != "" {
+		r.deleteVerifierLogConfigMap(ctx, appState, namespace)
+	}
+}
+
+func (r *ReconcilerCommon) deleteVerifierLogConfigMap(ctx context.Context, appState client.Object, namespace string) {
+	cm := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      verifierLogConfigMapName(appState.GetName()),
+			Namespace: namespace,
+		},
+	}
+	if err := r.Delete(ctx, cm); err != nil && !apierrors.IsNotFound(err) {
+		r.Logger.Error(err, "failed to delete verifier log", "ConfigMap", cm.Name, "Namespace", namespace)
+	}
+}
+
+// verifierLogNamespace returns the namespace in which the verifier logs of
+// cluster scoped applications are stored.
+func (r *ReconcilerCommon) verifierLogNamespace() string {
+	if r.Namespace != "" {
+		return r.Namespace
+	}
+	return internal.BpfmanNamespace
+}
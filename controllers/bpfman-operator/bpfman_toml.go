@@ -0,0 +1,143 @@
+/*
+Copyright 2025 The bpfman Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bpfmanoperator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bpfman/bpfman-operator/apis/v1alpha1"
+	"github.com/bpfman/bpfman-operator/internal/bpfmanconfig"
+)
+
+// invalidConfigurationError is returned when the bpfman configuration of a
+// Config cannot be rendered. It is a user error: retrying won't fix it.
+type invalidConfigurationError struct {
+	err error
+}
+
+func (e *invalidConfigurationError) Error() string {
+	return "invalid bpfman configuration: " + e.err.Error()
+}
+
+func (e *invalidConfigurationError) Unwrap() error {
+	return e.err
+}
+
+// renderBpfmanTOML returns the content of bpfman.toml for config: the raw
+// configuration with the typed daemon settings applied on top. The raw
+// configuration is returned unchanged when no typed setting is set, so that
+// existing deployments are not restarted by a reformatting.
+func renderBpfmanTOML(config *v1alpha1.Config) (string, error) {
+	doc, err := bpfmanconfig.Parse(config.Spec.Configuration)
+	if err != nil {
+		return "", &invalidConfigurationError{err: fmt.Errorf("configuration: %w", err)}
+	}
+
+	settings := daemonSettings(&config.Spec.Daemon)
+	bpfmanconfig.Merge(doc, settings)
+	if err := bpfmanconfig.Validate(doc); err != nil {
+		return "", &invalidConfigurationError{err: err}
+	}
+
+	if len(settings) == 0 {
+		return config.Spec.Configuration, nil
+	}
+	return bpfmanconfig.Render(doc)
+}
+
+// unknownBpfmanSettings returns the settings of the bpfman configuration of
+// config, and of the configuration of its node pools, that bpfman doesn't
+// know. They are only reported, since the raw configuration is how settings
+// that the typed fields don't cover are set.
+func unknownBpfmanSettings(config *v1alpha1.Config) []string {
+	var unknown []string
+	if doc, err := bpfmanconfig.Parse(config.Spec.Configuration); err == nil {
+		unknown = append(unknown, bpfmanconfig.UnknownSettings(doc)...)
+	}
+	for i := range config.Spec.NodePools {
+		pool := &config.Spec.NodePools[i]
+		doc, err := bpfmanconfig.Parse(pool.Configuration)
+		if err != nil {
+			continue
+		}
+		for _, setting := range bpfmanconfig.UnknownSettings(doc) {
+			unknown = append(unknown, fmt.Sprintf("node pool %s: %s", pool.Name, setting))
+		}
+	}
+	return unknown
+}
+
+// daemonSettings returns the bpfman.toml settings set by the typed fields of
+// daemon.
+func daemonSettings(daemon *v1alpha1.DaemonSpec) bpfmanconfig.Document {
+	doc := bpfmanconfig.Document{}
+
+	if db := daemon.Database; db != nil {
+		table := bpfmanconfig.Document{}
+		if db.MaxRetries != nil {
+			table["max_retries"] = int64(*db.MaxRetries)
+		}
+		if db.MillisecDelay != nil {
+			table["millisec_delay"] = *db.MillisecDelay
+		}
+		setTable(doc, "database", table)
+	}
+
+	if signing := daemon.Signing; signing != nil {
+		table := bpfmanconfig.Document{}
+		if signing.AllowUnsigned != nil {
+			table["allow_unsigned"] = *signing.AllowUnsigned
+		}
+		if signing.VerifyEnabled != nil {
+			table["verify_enabled"] = *signing.VerifyEnabled
+		}
+		setTable(doc, "signing", table)
+	}
+
+	if registry := daemon.Registry; registry != nil {
+		table := bpfmanconfig.Document{}
+		if registry.XdpDispatcherImage != "" {
+			table["xdp_dispatcher_image"] = registry.XdpDispatcherImage
+		}
+		if registry.TcDispatcherImage != "" {
+			table["tc_dispatcher_image"] = registry.TcDispatcherImage
+		}
+		setTable(doc, "registry", table)
+	}
+
+	return doc
+}
+
+func setTable(doc bpfmanconfig.Document, name string, table bpfmanconfig.Document) {
+	if len(table) > 0 {
+		doc[name] = table
+	}
+}
+
+// bpfmanLogFilter returns the RUST_LOG filter of the bpfman daemon: the daemon
+// log level followed by the level of each log target.
+func bpfmanLogFilter(daemon *v1alpha1.DaemonSpec) string {
+	var directives []string
+	if daemon.LogLevel != "" {
+		directives = append(directives, daemon.LogLevel)
+	}
+	for _, target := range daemon.LogTargets {
+		directives = append(directives, target.Target+"="+target.Level)
+	}
+	return strings.Join(directives, ",")
+}
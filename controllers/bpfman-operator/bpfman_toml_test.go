@@ -0,0 +1,139 @@
+/*
+Copyright 2025 The bpfman Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bpfmanoperator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+
+	"github.com/bpfman/bpfman-operator/apis/v1alpha1"
+	"github.com/bpfman/bpfman-operator/internal"
+)
+
+func TestRenderBpfmanTOML(t *testing.T) {
+	config := &v1alpha1.Config{Spec: v1alpha1.ConfigSpec{Configuration: internal.DefaultConfiguration}}
+
+	// The raw configuration is kept verbatim without typed settings.
+	rendered, err := renderBpfmanTOML(config)
+	require.NoError(t, err)
+	require.Equal(t, internal.DefaultConfiguration, rendered)
+
+	// Typed settings take precedence over the raw configuration.
+	config.Spec.Daemon.Database = &v1alpha1.DatabaseConfig{MaxRetries: ptr.To(int32(5))}
+	config.Spec.Daemon.Signing = &v1alpha1.SigningConfig{AllowUnsigned: ptr.To(false)}
+	config.Spec.Daemon.Registry = &v1alpha1.RegistryConfig{XdpDispatcherImage: "quay.io/bpfman/xdp-dispatcher:v0.6.0"}
+	rendered, err = renderBpfmanTOML(config)
+	require.NoError(t, err)
+	require.Equal(t, `[database]
+max_retries = 5
+millisec_delay = 10000
+
+[registry]
+xdp_dispatcher_image = 'quay.io/bpfman/xdp-dispatcher:v0.6.0'
+
+[signing]
+allow_unsigned = false
+verify_enabled = true
+`, rendered)
+
+	// Malformed TOML and mistyped settings are rejected.
+	config.Spec.Daemon = v1alpha1.DaemonSpec{}
+	config.Spec.Configuration = "[database]\nmax_retries = thirty\n"
+	_, err = renderBpfmanTOML(config)
+	require.ErrorContains(t, err, "configuration: line 2,")
+	config.Spec.Configuration = "[database]\nmax_retries = \"thirty\"\n"
+	_, err = renderBpfmanTOML(config)
+	require.ErrorContains(t, err, "database.max_retries: must be an integer")
+
+	// Unknown settings are passed through and reported.
+	config.Spec.Configuration = "[signing]\nallow_unsinged = true\n"
+	rendered, err = renderBpfmanTOML(config)
+	require.NoError(t, err)
+	require.Contains(t, rendered, "allow_unsinged = true\n")
+	config.Spec.NodePools = []v1alpha1.NodePool{{Name: "edge", Configuration: "[dtabase]\n"}}
+	require.Equal(t, []string{"signing.allow_unsinged", "node pool edge: dtabase"}, unknownBpfmanSettings(config))
+}
+
+func TestBpfmanLogFilter(t *testing.T) {
+	daemon := &v1alpha1.DaemonSpec{
+		LogLevel: "info",
+		LogTargets: []v1alpha1.LogTarget{
+			{Target: "bpfman::oci_utils", Level: "debug"},
+			{Target: "aya", Level: "off"},
+		},
+	}
+	require.Equal(t, "info,bpfman::oci_utils=debug,aya=off", bpfmanLogFilter(daemon))
+	require.Equal(t, "", bpfmanLogFilter(&v1alpha1.DaemonSpec{}))
+}
+
+func TestConfigInvalidConfiguration(t *testing.T) {
+	r, bpfmanConfig, req, ctx, cl := setupTestEnvironment(false, false)
+
+	bpfmanConfig = getConfig(t, ctx, cl)
+	bpfmanConfig.Spec.Configuration = "[database\nmax_retries = 30\n"
+	require.NoError(t, cl.Update(ctx, bpfmanConfig))
+
+	// The first reconcile adds the finalizer.
+	_, err := r.Reconcile(ctx, req)
+	require.NoError(t, err)
+
+	// An invalid configuration is reported and not retried, and nothing is
+	// rolled out.
+	result, err := r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	require.Zero(t, result.RequeueAfter)
+
+	config := getConfig(t, ctx, cl)
+	degraded := requireConfigCondition(t, config, v1alpha1.ConfigConditionDegraded, metav1.ConditionTrue)
+	require.Equal(t, configReasonInvalidConfig, degraded.Reason)
+	require.Contains(t, degraded.Message, "configuration: line 1,")
+	err = cl.Get(ctx, types.NamespacedName{Namespace: config.Spec.Namespace, Name: internal.BpfmanCmName}, &corev1.ConfigMap{})
+	require.Error(t, err)
+
+	// Once fixed, the rendered configuration and log filter are rolled out.
+	config.Spec.Configuration = internal.DefaultConfiguration
+	config.Spec.Daemon.Database = &v1alpha1.DatabaseConfig{MillisecDelay: ptr.To(int64(500))}
+	config.Spec.Daemon.LogTargets = []v1alpha1.LogTarget{{Target: "bpfman", Level: "debug"}}
+	require.NoError(t, cl.Update(ctx, config))
+	_, err = r.Reconcile(ctx, req)
+	require.NoError(t, err)
+
+	cm := &corev1.ConfigMap{}
+	require.NoError(t, cl.Get(ctx, types.NamespacedName{Namespace: config.Spec.Namespace, Name: internal.BpfmanCmName}, cm))
+	require.Contains(t, cm.Data[internal.BpfmanTOML], "millisec_delay = 500\n")
+	require.Equal(t, logLevel+",bpfman=debug", cm.Data[internal.BpfmanLogLevel])
+	config = getConfig(t, ctx, cl)
+	requireConfigCondition(t, config, v1alpha1.ConfigConditionDegraded, metav1.ConditionFalse)
+
+	// Unknown settings are rolled out and reported without degrading.
+	config.Spec.Configuration = internal.DefaultConfiguration + "[dtabase]\nmax_retries = 1\n"
+	require.NoError(t, cl.Update(ctx, config))
+	_, err = r.Reconcile(ctx, req)
+	require.NoError(t, err)
+
+	require.NoError(t, cl.Get(ctx, types.NamespacedName{Namespace: config.Spec.Namespace, Name: internal.BpfmanCmName}, cm))
+	require.Contains(t, cm.Data[internal.BpfmanTOML], "[dtabase]\n")
+	config = getConfig(t, ctx, cl)
+	degraded = requireConfigCondition(t, config, v1alpha1.ConfigConditionDegraded, metav1.ConditionFalse)
+	require.Equal(t, configReasonUnknownSettings, degraded.Reason)
+	require.Equal(t, "bpfman ignores unknown settings: dtabase", degraded.Message)
+}
@@ -18,13 +18,17 @@ package bpfmanoperator
 
 import (
 	"context"
+	goerrors "errors"
 	"fmt"
 	"io"
 	"maps"
 	"os"
+	"slices"
 	"strings"
+	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	storagev1 "k8s.io/api/storage/v1"
@@ -87,6 +91,9 @@ func (r *BpfmanConfigReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		Owns(
 			&appsv1.DaemonSet{},
 			builder.WithPredicates(resourcePredicate(internal.BpfmanDsName))).
+		Owns(&corev1.ConfigMap{}, builder.WithPredicates(nodePoolPredicate())).
+		Owns(&appsv1.DaemonSet{}, builder.WithPredicates(nodePoolPredicate())).
+		Owns(&batchv1.Job{}, builder.WithPredicates(cleanupJobPredicate())).
 		Owns(
 			&storagev1.CSIDriver{},
 			builder.WithPredicates(resourcePredicate(internal.BpfmanCsiDriverName)))
@@ -184,63 +191,121 @@ func (r *BpfmanConfigReconciler) Reconcile(ctx context.Context, req ctrl.Request
 	}
 
 	// Normal reconciliation - safe to create/update resources.
-	if err := r.reconcileCM(ctx, bpfmanConfig); err != nil {
+	reconcileErr := r.reconcileResources(ctx, bpfmanConfig)
+
+	// Report the outcome and the readiness of the components in the
+	// Config status.
+	available, err := r.updateConfigStatus(ctx, bpfmanConfig, reconcileErr)
+	var invalidErr *invalidConfigurationError
+	if goerrors.As(reconcileErr, &invalidErr) {
+		// Nothing to retry until the Config is fixed, which triggers a
+		// new reconcile.
+		r.Logger.Info("Invalid bpfman configuration", "error", reconcileErr.Error())
+		return ctrl.Result{}, err
+	}
+	if reconcileErr != nil {
+		return ctrl.Result{}, reconcileErr
+	}
+	if err != nil {
 		return ctrl.Result{}, err
 	}
+	if !available {
+		return ctrl.Result{RequeueAfter: configStatusRequeue}, nil
+	}
+	if len(bpfmanConfig.Status.DaemonUpgrades) > 0 {
+		// Nodes are still being verified. Their pods settling changes no
+		// watched object, so check them again once they may have.
+		return ctrl.Result{RequeueAfter: upgradeSettleTime}, nil
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// reconcileResources creates or updates every resource deployed for
+// bpfmanConfig, stopping at the first failure.
+func (r *BpfmanConfigReconciler) reconcileResources(ctx context.Context, bpfmanConfig *v1alpha1.Config) error {
+	// Don't roll out a configuration bpfman would reject, or silently
+	// ignore, to every node.
+	if _, err := renderBpfmanTOML(bpfmanConfig); err != nil {
+		return err
+	}
+	if err := validateNodePools(bpfmanConfig); err != nil {
+		return err
+	}
+	if err := validateOverridePatches(bpfmanConfig.Spec.Overrides); err != nil {
+		return err
+	}
+
+	if err := r.reconcileCM(ctx, bpfmanConfig); err != nil {
+		return fmt.Errorf("reconcile ConfigMap: %w", err)
+	}
 
 	if err := r.reconcileCSIDriver(ctx, bpfmanConfig); err != nil {
-		return ctrl.Result{}, err
+		return fmt.Errorf("reconcile CSIDriver: %w", err)
 	}
 
 	if err := r.reconcileSCC(ctx, bpfmanConfig); err != nil {
-		return ctrl.Result{}, err
+		return fmt.Errorf("reconcile SecurityContextConstraints: %w", err)
 	}
 
 	if err := r.reconcileStandardDS(ctx, bpfmanConfig); err != nil {
-		return ctrl.Result{}, err
+		return fmt.Errorf("reconcile bpfman DaemonSet: %w", err)
+	}
+
+	if err := r.reconcileNodePools(ctx, bpfmanConfig); err != nil {
+		return fmt.Errorf("reconcile node pools: %w", err)
+	}
+
+	if err := r.reconcileDaemonUpgrades(ctx, bpfmanConfig); err != nil {
+		return fmt.Errorf("reconcile bpfman daemon upgrade: %w", err)
 	}
 
 	if err := r.reconcileMetricsProxyDS(ctx, bpfmanConfig); err != nil {
-		return ctrl.Result{}, err
+		return fmt.Errorf("reconcile metrics-proxy DaemonSet: %w", err)
 	}
 
 	if err := r.reconcileMetricsServices(ctx, bpfmanConfig); err != nil {
-		return ctrl.Result{}, err
+		return fmt.Errorf("reconcile metrics Services: %w", err)
 	}
 
 	if err := r.reconcileServiceMonitors(ctx, bpfmanConfig); err != nil {
-		return ctrl.Result{}, err
+		return fmt.Errorf("reconcile ServiceMonitors: %w", err)
 	}
 
 	if err := r.reconcileAgentMetricsServiceAnnotation(ctx, bpfmanConfig); err != nil {
-		return ctrl.Result{}, err
+		return fmt.Errorf("reconcile agent metrics Service annotation: %w", err)
 	}
 
 	if err := r.reconcileOpenShiftRBAC(ctx, bpfmanConfig); err != nil {
-		return ctrl.Result{}, err
+		return fmt.Errorf("reconcile OpenShift RBAC: %w", err)
 	}
 
 	if err := r.reconcilePrometheusRBAC(ctx, bpfmanConfig); err != nil {
-		return ctrl.Result{}, err
+		return fmt.Errorf("reconcile Prometheus RBAC: %w", err)
 	}
 
 	if err := r.reconcileNamespace(ctx, bpfmanConfig); err != nil {
-		return ctrl.Result{}, err
+		return fmt.Errorf("reconcile namespace: %w", err)
 	}
 
-	return ctrl.Result{}, nil
+	return nil
 }
 
 func (r *BpfmanConfigReconciler) reconcileCM(ctx context.Context, bpfmanConfig *v1alpha1.Config) error {
+	bpfmanTOML, err := renderBpfmanTOML(bpfmanConfig)
+	if err != nil {
+		return err
+	}
+
 	cm := &corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      internal.BpfmanCmName,
 			Namespace: bpfmanConfig.Spec.Namespace,
 		},
 		Data: map[string]string{
-			internal.BpfmanTOML:          bpfmanConfig.Spec.Configuration,
+			internal.BpfmanTOML:          bpfmanTOML,
 			internal.BpfmanAgentLogLevel: bpfmanConfig.Spec.Agent.LogLevel,
-			internal.BpfmanLogLevel:      bpfmanConfig.Spec.Daemon.LogLevel,
+			internal.BpfmanLogLevel:      bpfmanLogFilter(&bpfmanConfig.Spec.Daemon),
 		},
 	}
 
@@ -318,6 +383,7 @@ func (r *BpfmanConfigReconciler) reconcileStandardDS(ctx context.Context, bpfman
 		return err
 	}
 	configureBpfmanDs(bpfmanDS, bpfmanConfig)
+	excludeNodePools(&bpfmanDS.Spec.Template.Spec, bpfmanConfig.Spec.NodePools)
 	return assureResource(ctx, r, bpfmanConfig, bpfmanDS, func(existing, desired *appsv1.DaemonSet) bool {
 		copyImagePullPolicy(&existing.Spec.Template.Spec, &desired.Spec.Template.Spec)
 		return !equality.Semantic.DeepEqual(existing.Spec, desired.Spec)
@@ -840,10 +906,17 @@ func configureBpfmanDs(staticBpfmanDS *appsv1.DaemonSet, config *v1alpha1.Config
 	// Runtime Configurable fields
 	bpfmanHealthProbeAddr := healthProbeAddress(config.Spec.Agent.HealthProbePort)
 
+	// The configuration is validated before the DaemonSet is reconciled,
+	// fall back to the raw configuration otherwise.
+	bpfmanTOML, err := renderBpfmanTOML(config)
+	if err != nil {
+		bpfmanTOML = config.Spec.Configuration
+	}
+
 	newAnnotations := map[string]string{
-		fmt.Sprintf("%s.%s", internal.APIPrefix, internal.BpfmanLogLevel):      config.Spec.Daemon.LogLevel,
+		fmt.Sprintf("%s.%s", internal.APIPrefix, internal.BpfmanLogLevel):      bpfmanLogFilter(&config.Spec.Daemon),
 		fmt.Sprintf("%s.%s", internal.APIPrefix, internal.BpfmanAgentLogLevel): config.Spec.Agent.LogLevel,
-		fmt.Sprintf("%s.%s", internal.APIPrefix, internal.BpfmanTOML):          config.Spec.Configuration,
+		fmt.Sprintf("%s.%s", internal.APIPrefix, internal.BpfmanTOML):          bpfmanTOML,
 	}
 
 	// Annotate configuration values on the DaemonSet to trigger automatic pod restarts on changes
@@ -878,6 +951,8 @@ func configureBpfmanDs(staticBpfmanDS *appsv1.DaemonSet, config *v1alpha1.Config
 					}
 				}
 			}
+			staticBpfmanDS.Spec.Template.Spec.Containers[cindex].Args =
+				linkDriftArgs(staticBpfmanDS.Spec.Template.Spec.Containers[cindex].Args, &config.Spec.Agent.LinkDrift)
 		case internal.BpfmanCsiDriverRegistrarName:
 			if config.Spec.Daemon.CsiRegistrarImage != "" {
 				staticBpfmanDS.Spec.Template.Spec.Containers[cindex].Image = config.Spec.Daemon.CsiRegistrarImage
@@ -887,6 +962,12 @@ func configureBpfmanDs(staticBpfmanDS *appsv1.DaemonSet, config *v1alpha1.Config
 		}
 	}
 
+	configurePodScheduling(&staticBpfmanDS.Spec.Template.Spec, config.Spec.Daemon.Tolerations,
+		config.Spec.Daemon.NodeSelector, config.Spec.Daemon.PriorityClassName)
+	setContainerResources(&staticBpfmanDS.Spec.Template.Spec, internal.BpfmanContainerName, config.Spec.Daemon.Resources)
+	setContainerResources(&staticBpfmanDS.Spec.Template.Spec, internal.BpfmanAgentContainerName, config.Spec.Agent.Resources)
+	configureUpgradeStrategy(staticBpfmanDS, config)
+
 	if p := corev1.PullPolicy(os.Getenv("BPFMAN_IMAGE_PULL_POLICY")); p != "" {
 		for i := range staticBpfmanDS.Spec.Template.Spec.InitContainers {
 			staticBpfmanDS.Spec.Template.Spec.InitContainers[i].ImagePullPolicy = p
@@ -897,6 +978,37 @@ func configureBpfmanDs(staticBpfmanDS *appsv1.DaemonSet, config *v1alpha1.Config
 	}
 }
 
+// defaultLinkDriftInterval is how often the bpfman agent verifies the
+// links of its node when the Config doesn't set it.
+const defaultLinkDriftInterval = 5 * time.Minute
+
+// linkDriftArgs returns the bpfman agent arguments with the link drift
+// settings replaced or appended.
+func linkDriftArgs(args []string, linkDrift *v1alpha1.LinkDriftSpec) []string {
+	interval := defaultLinkDriftInterval
+	if linkDrift.Interval != nil {
+		interval = linkDrift.Interval.Duration
+	}
+	mode := linkDrift.Mode
+	if mode == "" {
+		mode = v1alpha1.LinkDriftRepair
+	}
+	args = setArg(args, "--link-drift-interval", interval.String())
+	return setArg(args, "--link-drift-mode", string(mode))
+}
+
+// setArg sets the value of the command-line flag name in args.
+func setArg(args []string, name, value string) []string {
+	arg := name + "=" + value
+	for i := range args {
+		if args[i] == name || strings.HasPrefix(args[i], name+"=") {
+			args[i] = arg
+			return args
+		}
+	}
+	return append(args, arg)
+}
+
 // configureMetricsProxyDs configures the metrics-proxy DaemonSet with runtime values.
 // Sets up container images and adds OpenShift-specific TLS configuration when applicable.
 func configureMetricsProxyDs(staticMetricsProxyDS *appsv1.DaemonSet, config *v1alpha1.Config, isOpenshift bool) {
@@ -953,6 +1065,12 @@ func configureMetricsProxyDs(staticMetricsProxyDS *appsv1.DaemonSet, config *v1a
 		)
 	}
 
+	metricsProxy := &config.Spec.MetricsProxy
+	configurePodScheduling(&staticMetricsProxyDS.Spec.Template.Spec, metricsProxy.Tolerations,
+		metricsProxy.NodeSelector, metricsProxy.PriorityClassName)
+	setContainerResources(&staticMetricsProxyDS.Spec.Template.Spec, internal.BpfmanMetricsProxyContainer,
+		metricsProxy.Resources)
+
 	if p := corev1.PullPolicy(os.Getenv("BPFMAN_IMAGE_PULL_POLICY")); p != "" {
 		for i := range staticMetricsProxyDS.Spec.Template.Spec.Containers {
 			staticMetricsProxyDS.Spec.Template.Spec.Containers[i].ImagePullPolicy = p
@@ -960,6 +1078,43 @@ func configureMetricsProxyDs(staticMetricsProxyDS *appsv1.DaemonSet, config *v1a
 	}
 }
 
+// configurePodScheduling adds tolerations and nodeSelector to the ones of
+// podSpec, and sets its priority class if priorityClassName is not empty.
+func configurePodScheduling(podSpec *corev1.PodSpec, tolerations []corev1.Toleration,
+	nodeSelector map[string]string, priorityClassName string) {
+	for _, toleration := range tolerations {
+		if !slices.ContainsFunc(podSpec.Tolerations, func(t corev1.Toleration) bool {
+			return equality.Semantic.DeepEqual(t, toleration)
+		}) {
+			podSpec.Tolerations = append(podSpec.Tolerations, toleration)
+		}
+	}
+
+	if len(nodeSelector) > 0 {
+		if podSpec.NodeSelector == nil {
+			podSpec.NodeSelector = make(map[string]string)
+		}
+		maps.Copy(podSpec.NodeSelector, nodeSelector)
+	}
+
+	if priorityClassName != "" {
+		podSpec.PriorityClassName = priorityClassName
+	}
+}
+
+// setContainerResources sets the resources of the container name of podSpec
+// to resources, unless resources is nil.
+func setContainerResources(podSpec *corev1.PodSpec, name string, resources *corev1.ResourceRequirements) {
+	if resources == nil {
+		return
+	}
+	for i := range podSpec.Containers {
+		if podSpec.Containers[i].Name == name {
+			podSpec.Containers[i].Resources = *resources.DeepCopy()
+		}
+	}
+}
+
 // load reads a Kubernetes resource manifest from the specified file path and deserializes it.
 // Sets the resource name and returns the loaded object.
 func load[T client.Object](t T, path, name string) (T, error) {
@@ -1003,6 +1158,14 @@ func assureResource[T client.Object](ctx context.Context, r *BpfmanConfigReconci
 		return nil
 	}
 
+	if override := overrideFor(resource, bpfmanConfig.Spec.Overrides, r.Scheme); override != nil && override.Patch != nil {
+		r.Logger.Info("Patching object (override in place)",
+			"namespace", resource.GetNamespace(), "name", resource.GetName(), "patchType", override.Patch.Type)
+		if err := applyOverridePatch(resource, override); err != nil {
+			return err
+		}
+	}
+
 	if err := ctrl.SetControllerReference(bpfmanConfig, resource, r.Scheme); err != nil {
 		return err
 	}
@@ -1043,23 +1206,27 @@ func assureResource[T client.Object](ctx context.Context, r *BpfmanConfigReconci
 }
 
 // isOverridden determines if a given object shall be unmanaged by checking
-// against the configured overrides. It uses the scheme to reliably resolve
-// the GVK for the resource, rather than relying on the object's TypeMeta
-// which may not be populated for in-memory objects.
+// against the configured overrides.
 func isOverridden(resource client.Object, overrides []v1alpha1.ComponentOverride, scheme *runtime.Scheme) bool {
+	override := overrideFor(resource, overrides, scheme)
+	return override != nil && override.Unmanaged
+}
+
+// overrideFor returns the override configured for a given object, or nil. It
+// uses the scheme to reliably resolve the GVK for the resource, rather than
+// relying on the object's TypeMeta which may not be populated for in-memory
+// objects.
+func overrideFor(resource client.Object, overrides []v1alpha1.ComponentOverride,
+	scheme *runtime.Scheme) *v1alpha1.ComponentOverride {
 	// Resolve GVK via the scheme - this is reliable even for objects
 	// constructed in code that don't have TypeMeta populated.
 	gvks, _, err := scheme.ObjectKinds(resource)
 	if err != nil || len(gvks) == 0 {
-		return false
+		return nil
 	}
 	resourceGVK := gvks[0]
 
-	for _, override := range overrides {
-		if !override.Unmanaged {
-			continue
-		}
-
+	for i, override := range overrides {
 		// Parse the apiVersion from the override (e.g., "apps/v1" or "v1")
 		overrideGV, err := schema.ParseGroupVersion(override.APIVersion)
 		if err != nil {
@@ -1070,30 +1237,10 @@ func isOverridden(resource client.Object, overrides []v1alpha1.ComponentOverride
 			overrideGV.Group == resourceGVK.Group &&
 			override.Namespace == resource.GetNamespace() &&
 			override.Name == resource.GetName() {
-			return true
+			return &overrides[i]
 		}
 	}
-	return false
-}
-
-// handleDeletion manages the safe deletion of Config resources by
-// removing the finalizer to allow Kubernetes garbage collection to
-// proceed via owner references.
-func (r *BpfmanConfigReconciler) handleDeletion(ctx context.Context, config *v1alpha1.Config) (ctrl.Result, error) {
-	r.Logger.Info("Config deletion requested, allowing Kubernetes garbage collection to proceed",
-		"name", config.Name)
-
-	// No custom cleanup needed - all resources are managed via
-	// owner references. Remove finalizer to trigger cascading
-	// deletion of owned resources.
-	controllerutil.RemoveFinalizer(config, internal.BpfmanConfigFinalizer)
-	if err := r.Update(ctx, config); err != nil {
-		r.Logger.Error(err, "Failed to remove finalizer from Config during deletion", "name", config.Name)
-		return ctrl.Result{}, err
-	}
-
-	r.Logger.Info("Finalizer removed from Config, deletion will proceed", "name", config.Name)
-	return ctrl.Result{}, nil
+	return nil
 }
 
 func healthProbeAddress(healthProbePort int) string {
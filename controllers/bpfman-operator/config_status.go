@@ -0,0 +1,270 @@
+/*
+Copyright 2025 The bpfman Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bpfmanoperator
+
+import (
+	"context"
+	goerrors "errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+
+	"github.com/bpfman/bpfman-operator/apis/v1alpha1"
+	"github.com/bpfman/bpfman-operator/internal"
+)
+
+// +kubebuilder:rbac:groups=bpfman.io,resources=configs/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=storage.k8s.io,resources=csinodes,verbs=get;list;watch
+
+// configStatusRequeue is how often the Config status is refreshed while the
+// components are not all ready. Node registration of the CSI driver is not
+// watched, so the status would otherwise only be refreshed on DaemonSet
+// changes.
+const configStatusRequeue = 30 * time.Second
+
+const (
+	configReasonAsExpected         = "AsExpected"
+	configReasonReconcileFailed    = "ReconcileFailed"
+	configReasonInvalidConfig      = "InvalidConfiguration"
+	configReasonComponentsReady    = "ComponentsReady"
+	configReasonComponentsNotReady = "ComponentsNotReady"
+	configReasonRollingOut         = "RollingOut"
+	configReasonUnknownSettings    = "UnknownSettings"
+)
+
+// updateConfigStatus reports the readiness of the components deployed for
+// bpfmanConfig, and the error returned while reconciling them if any, in
+// the Config status. It returns whether every component is ready.
+func (r *BpfmanConfigReconciler) updateConfigStatus(ctx context.Context, bpfmanConfig *v1alpha1.Config,
+	reconcileErr error) (bool, error) {
+	status := bpfmanConfig.Status.DeepCopy()
+	status.ObservedGeneration = bpfmanConfig.Generation
+
+	components, rollingOut, err := r.componentStatuses(ctx, bpfmanConfig)
+	if err != nil {
+		return false, err
+	}
+	status.Components = components
+
+	var notReady, failed []string
+	for _, component := range components {
+		if component.Ready {
+			continue
+		}
+		notReady = append(notReady, component.Name)
+		if !slices.Contains(rollingOut, component.Name) {
+			failed = append(failed, fmt.Sprintf("%s: %s", component.Name, component.Message))
+		}
+	}
+
+	available := metav1.Condition{
+		Type:    string(v1alpha1.ConfigConditionAvailable),
+		Status:  metav1.ConditionTrue,
+		Reason:  configReasonComponentsReady,
+		Message: "All components are ready",
+	}
+	if len(notReady) > 0 {
+		available.Status = metav1.ConditionFalse
+		available.Reason = configReasonComponentsNotReady
+		available.Message = "Components not ready: " + strings.Join(notReady, ", ")
+	}
+
+	progressing := metav1.Condition{
+		Type:   string(v1alpha1.ConfigConditionProgressing),
+		Status: metav1.ConditionFalse,
+		Reason: configReasonAsExpected,
+	}
+	if len(rollingOut) > 0 {
+		progressing.Status = metav1.ConditionTrue
+		progressing.Reason = configReasonRollingOut
+		progressing.Message = "Rolling out: " + strings.Join(rollingOut, ", ")
+	}
+
+	degraded := metav1.Condition{
+		Type:   string(v1alpha1.ConfigConditionDegraded),
+		Status: metav1.ConditionFalse,
+		Reason: configReasonAsExpected,
+	}
+	var invalidErr *invalidConfigurationError
+	if goerrors.As(reconcileErr, &invalidErr) {
+		degraded.Status = metav1.ConditionTrue
+		degraded.Reason = configReasonInvalidConfig
+		degraded.Message = invalidErr.Error()
+	} else if reconcileErr != nil {
+		degraded.Status = metav1.ConditionTrue
+		degraded.Reason = configReasonReconcileFailed
+		degraded.Message = reconcileErr.Error()
+	} else if len(failed) > 0 {
+		degraded.Status = metav1.ConditionTrue
+		degraded.Reason = configReasonComponentsNotReady
+		degraded.Message = strings.Join(failed, "; ")
+	} else if unknown := unknownBpfmanSettings(bpfmanConfig); len(unknown) > 0 {
+		// bpfman ignores these settings, which doesn't degrade it, but they
+		// are likely misspelt.
+		degraded.Reason = configReasonUnknownSettings
+		degraded.Message = "bpfman ignores unknown settings: " + strings.Join(unknown, ", ")
+	}
+
+	for _, condition := range []metav1.Condition{available, progressing, degraded} {
+		condition.ObservedGeneration = bpfmanConfig.Generation
+		meta.SetStatusCondition(&status.Conditions, condition)
+	}
+
+	if equality.Semantic.DeepEqual(&bpfmanConfig.Status, status) {
+		return len(notReady) == 0, nil
+	}
+
+	bpfmanConfig.Status = *status
+	if err := r.Status().Update(ctx, bpfmanConfig); err != nil {
+		return false, fmt.Errorf("update Config status: %w", err)
+	}
+	return len(notReady) == 0, nil
+}
+
+// componentStatuses returns the status of each component deployed for
+// bpfmanConfig, and the names of the components that are being rolled out.
+func (r *BpfmanConfigReconciler) componentStatuses(ctx context.Context,
+	bpfmanConfig *v1alpha1.Config) ([]v1alpha1.ComponentStatus, []string, error) {
+	var components []v1alpha1.ComponentStatus
+	var rollingOut []string
+
+	bpfmanDaemonSets := []string{internal.BpfmanDsName}
+	for i := range bpfmanConfig.Spec.NodePools {
+		bpfmanDaemonSets = append(bpfmanDaemonSets, nodePoolDsName(&bpfmanConfig.Spec.NodePools[i]))
+	}
+	daemonSets := slices.Clone(bpfmanDaemonSets)
+	if r.HasMonitoring {
+		daemonSets = append(daemonSets, internal.BpfmanMetricsProxyDsName)
+	}
+
+	var daemonDesired int32
+	for _, name := range daemonSets {
+		ds := &appsv1.DaemonSet{}
+		err := r.Get(ctx, types.NamespacedName{Namespace: bpfmanConfig.Spec.Namespace, Name: name}, ds)
+		if err != nil && !errors.IsNotFound(err) {
+			return nil, nil, fmt.Errorf("get DaemonSet %s: %w", name, err)
+		}
+		if errors.IsNotFound(err) {
+			ds = nil
+		}
+		component, progressing := daemonSetStatus(name, ds)
+		if progressing {
+			rollingOut = append(rollingOut, name)
+		}
+		if slices.Contains(bpfmanDaemonSets, name) {
+			daemonDesired += component.Desired
+		}
+		components = append(components, component)
+	}
+
+	csiDriver, err := r.csiDriverStatus(ctx, daemonDesired)
+	if err != nil {
+		return nil, nil, err
+	}
+	components = append(components, csiDriver)
+
+	if r.HasMonitoring {
+		for _, name := range []string{internal.BpfmanAgentServiceMonitorName, internal.BpfmanControllerServiceMonitorName} {
+			component := v1alpha1.ComponentStatus{Name: name, Kind: "ServiceMonitor", Desired: 1}
+			err := r.Get(ctx, types.NamespacedName{Namespace: bpfmanConfig.Spec.Namespace, Name: name},
+				&monitoringv1.ServiceMonitor{})
+			switch {
+			case err == nil:
+				component.Ready = true
+				component.ReadyCount = 1
+			case errors.IsNotFound(err):
+				component.Message = "ServiceMonitor not found"
+			default:
+				return nil, nil, fmt.Errorf("get ServiceMonitor %s: %w", name, err)
+			}
+			components = append(components, component)
+		}
+	}
+
+	return components, rollingOut, nil
+}
+
+// daemonSetStatus returns the status of the DaemonSet component name, and
+// whether the DaemonSet is being rolled out. ds is nil if the DaemonSet
+// doesn't exist.
+func daemonSetStatus(name string, ds *appsv1.DaemonSet) (v1alpha1.ComponentStatus, bool) {
+	component := v1alpha1.ComponentStatus{Name: name, Kind: "DaemonSet"}
+	if ds == nil {
+		component.Message = "DaemonSet not found"
+		return component, false
+	}
+
+	component.Desired = ds.Status.DesiredNumberScheduled
+	component.ReadyCount = ds.Status.NumberReady
+	component.Updated = ds.Status.UpdatedNumberScheduled
+
+	progressing := ds.Status.ObservedGeneration < ds.Generation || component.Updated < component.Desired
+	switch {
+	case progressing:
+		component.Message = fmt.Sprintf("%d of %d nodes updated", component.Updated, component.Desired)
+	case component.ReadyCount < component.Desired:
+		component.Message = fmt.Sprintf("%d of %d nodes not ready",
+			component.Desired-component.ReadyCount, component.Desired)
+	default:
+		component.Ready = true
+	}
+	return component, progressing
+}
+
+// csiDriverStatus returns the status of the bpfman CSI driver, which is
+// ready once it is registered on every node running the bpfman daemon.
+func (r *BpfmanConfigReconciler) csiDriverStatus(ctx context.Context, desired int32) (v1alpha1.ComponentStatus, error) {
+	component := v1alpha1.ComponentStatus{Name: internal.BpfmanCsiDriverName, Kind: "CSIDriver", Desired: desired}
+
+	if err := r.Get(ctx, types.NamespacedName{Name: internal.BpfmanCsiDriverName}, &storagev1.CSIDriver{}); err != nil {
+		if errors.IsNotFound(err) {
+			component.Message = "CSIDriver not found"
+			return component, nil
+		}
+		return component, fmt.Errorf("get CSIDriver %s: %w", internal.BpfmanCsiDriverName, err)
+	}
+
+	csiNodes := &storagev1.CSINodeList{}
+	if err := r.List(ctx, csiNodes); err != nil {
+		return component, fmt.Errorf("list CSINodes: %w", err)
+	}
+	for _, csiNode := range csiNodes.Items {
+		if slices.ContainsFunc(csiNode.Spec.Drivers, func(d storagev1.CSINodeDriver) bool {
+			return d.Name == internal.BpfmanCsiDriverName
+		}) {
+			component.ReadyCount++
+		}
+	}
+
+	if component.ReadyCount < desired {
+		component.Message = fmt.Sprintf("registered on %d of %d nodes", component.ReadyCount, desired)
+	} else {
+		component.Ready = true
+	}
+	return component, nil
+}
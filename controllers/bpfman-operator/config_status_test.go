@@ -0,0 +1,132 @@
+/*
+Copyright 2025 The bpfman Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bpfmanoperator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/bpfman/bpfman-operator/apis/v1alpha1"
+	"github.com/bpfman/bpfman-operator/internal"
+)
+
+func TestConfigStatus(t *testing.T) {
+	r, _, req, ctx, cl := setupTestEnvironment(false, true)
+
+	// The first reconcile adds the finalizer, the second one creates the
+	// resources and reports their status.
+	_, err := r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	_, err = r.Reconcile(ctx, req)
+	require.NoError(t, err)
+
+	config := getConfig(t, ctx, cl)
+	require.Equal(t, config.Generation, config.Status.ObservedGeneration)
+	requireConfigCondition(t, config, v1alpha1.ConfigConditionAvailable, metav1.ConditionTrue)
+	requireConfigCondition(t, config, v1alpha1.ConfigConditionProgressing, metav1.ConditionFalse)
+	requireConfigCondition(t, config, v1alpha1.ConfigConditionDegraded, metav1.ConditionFalse)
+	require.Len(t, config.Status.Components, 5)
+
+	// Pods crash-looping on an up to date DaemonSet degrade the Config.
+	setDaemonSetStatus(t, ctx, cl, internal.BpfmanDsName, 3, 3, 1)
+	setDaemonSetStatus(t, ctx, cl, internal.BpfmanMetricsProxyDsName, 3, 3, 3)
+	result, err := r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	require.Equal(t, configStatusRequeue, result.RequeueAfter)
+
+	config = getConfig(t, ctx, cl)
+	requireConfigCondition(t, config, v1alpha1.ConfigConditionAvailable, metav1.ConditionFalse)
+	requireConfigCondition(t, config, v1alpha1.ConfigConditionProgressing, metav1.ConditionFalse)
+	degraded := requireConfigCondition(t, config, v1alpha1.ConfigConditionDegraded, metav1.ConditionTrue)
+	require.Contains(t, degraded.Message, "bpfman-daemon: 2 of 3 nodes not ready")
+	daemon := config.Status.Components[0]
+	require.Equal(t, internal.BpfmanDsName, daemon.Name)
+	require.False(t, daemon.Ready)
+	require.Equal(t, int32(3), daemon.Desired)
+	require.Equal(t, int32(1), daemon.ReadyCount)
+	// The CSI driver is not registered on any node yet.
+	csiDriver := config.Status.Components[2]
+	require.Equal(t, internal.BpfmanCsiDriverName, csiDriver.Name)
+	require.Equal(t, "registered on 0 of 3 nodes", csiDriver.Message)
+
+	// A rollout in progress is reported as progressing, not degraded.
+	setDaemonSetStatus(t, ctx, cl, internal.BpfmanDsName, 3, 1, 1)
+	for _, node := range []string{"node-1", "node-2", "node-3"} {
+		require.NoError(t, cl.Create(ctx, &storagev1.CSINode{
+			ObjectMeta: metav1.ObjectMeta{Name: node},
+			Spec: storagev1.CSINodeSpec{Drivers: []storagev1.CSINodeDriver{
+				{Name: internal.BpfmanCsiDriverName, NodeID: node},
+			}},
+		}))
+	}
+	_, err = r.Reconcile(ctx, req)
+	require.NoError(t, err)
+
+	config = getConfig(t, ctx, cl)
+	requireConfigCondition(t, config, v1alpha1.ConfigConditionAvailable, metav1.ConditionFalse)
+	requireConfigCondition(t, config, v1alpha1.ConfigConditionProgressing, metav1.ConditionTrue)
+	requireConfigCondition(t, config, v1alpha1.ConfigConditionDegraded, metav1.ConditionFalse)
+	require.True(t, config.Status.Components[2].Ready)
+
+	// Once the rollout completes the Config is available again.
+	setDaemonSetStatus(t, ctx, cl, internal.BpfmanDsName, 3, 3, 3)
+	result, err = r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	require.Zero(t, result.RequeueAfter)
+	config = getConfig(t, ctx, cl)
+	requireConfigCondition(t, config, v1alpha1.ConfigConditionAvailable, metav1.ConditionTrue)
+
+	// Reconcile errors are reported as degraded.
+	r.BpfmanStandardDS = "/does/not/exist.yaml"
+	_, err = r.Reconcile(ctx, req)
+	require.Error(t, err)
+	config = getConfig(t, ctx, cl)
+	degraded = requireConfigCondition(t, config, v1alpha1.ConfigConditionDegraded, metav1.ConditionTrue)
+	require.Equal(t, configReasonReconcileFailed, degraded.Reason)
+	require.Contains(t, degraded.Message, "reconcile bpfman DaemonSet")
+}
+
+func getConfig(t *testing.T, ctx context.Context, cl client.Client) *v1alpha1.Config {
+	config := &v1alpha1.Config{}
+	require.NoError(t, cl.Get(ctx, types.NamespacedName{Name: internal.BpfmanConfigName}, config))
+	return config
+}
+
+func requireConfigCondition(t *testing.T, config *v1alpha1.Config, conditionType v1alpha1.ConfigConditionType,
+	status metav1.ConditionStatus) *metav1.Condition {
+	condition := meta.FindStatusCondition(config.Status.Conditions, string(conditionType))
+	require.NotNil(t, condition, "condition %s not found", conditionType)
+	require.Equal(t, status, condition.Status, "condition %s: %s", conditionType, condition.Message)
+	return condition
+}
+
+func setDaemonSetStatus(t *testing.T, ctx context.Context, cl client.Client, name string, desired, updated, ready int32) {
+	ds := &appsv1.DaemonSet{}
+	require.NoError(t, cl.Get(ctx, types.NamespacedName{Namespace: internal.BpfmanNamespace, Name: name}, ds))
+	ds.Status.DesiredNumberScheduled = desired
+	ds.Status.UpdatedNumberScheduled = updated
+	ds.Status.NumberReady = ready
+	require.NoError(t, cl.Status().Update(ctx, ds))
+}
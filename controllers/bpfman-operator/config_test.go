@@ -32,6 +32,7 @@ import (
 	rbacv1 "k8s.io/api/rbac/v1"
 	storagev1 "k8s.io/api/storage/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -308,6 +309,10 @@ func setupTestEnvironment(isOpenShift, hasMonitoring bool) (*BpfmanConfigReconci
 	// Register operator types with the runtime scheme.
 	s := scheme.Scheme
 	s.AddKnownTypes(v1alpha1.SchemeGroupVersion, &v1alpha1.Config{})
+	s.AddKnownTypes(v1alpha1.SchemeGroupVersion, &v1alpha1.ClusterBpfApplicationState{},
+		&v1alpha1.ClusterBpfApplicationStateList{}, &v1alpha1.BpfApplicationState{}, &v1alpha1.BpfApplicationStateList{},
+		&v1alpha1.ClusterBpfApplication{}, &v1alpha1.ClusterBpfApplicationList{}, &v1alpha1.BpfApplication{},
+		&v1alpha1.BpfApplicationList{})
 	s.AddKnownTypes(corev1.SchemeGroupVersion, &corev1.ConfigMap{})
 	s.AddKnownTypes(appsv1.SchemeGroupVersion, &appsv1.DaemonSet{})
 	s.AddKnownTypes(storagev1.SchemeGroupVersion, &storagev1.CSIDriver{})
@@ -316,7 +321,7 @@ func setupTestEnvironment(isOpenShift, hasMonitoring bool) (*BpfmanConfigReconci
 	s.AddKnownTypes(monitoringv1.SchemeGroupVersion, &monitoringv1.ServiceMonitor{}, &monitoringv1.ServiceMonitorList{})
 
 	// Create a fake client to mock API calls.
-	cl := fake.NewClientBuilder().WithRuntimeObjects(objs...).Build()
+	cl := fake.NewClientBuilder().WithStatusSubresource(bpfmanConfig, &appsv1.DaemonSet{}).WithRuntimeObjects(objs...).Build()
 
 	// Set development Logger so we can see all logs in tests.
 	logf.SetLogger(zap.New(zap.UseFlagOptions(&zap.Options{Development: true})))
@@ -1096,6 +1101,80 @@ func TestConfigureBpfmanDsCsiRegistrarImageOverride(t *testing.T) {
 	}
 }
 
+func TestConfigureDsScheduling(t *testing.T) {
+	daemonResources := &corev1.ResourceRequirements{
+		Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+	}
+	agentResources := &corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("50m")},
+	}
+	proxyResources := &corev1.ResourceRequirements{
+		Limits: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")},
+	}
+	edgeToleration := corev1.Toleration{Key: "edge", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule}
+	controlPlaneToleration := corev1.Toleration{
+		Key:      "node-role.kubernetes.io/control-plane",
+		Operator: corev1.TolerationOpExists,
+		Effect:   corev1.TaintEffectNoSchedule,
+	}
+
+	config := &v1alpha1.Config{
+		Spec: v1alpha1.ConfigSpec{
+			Agent: v1alpha1.AgentSpec{
+				Image:     "quay.io/bpfman/bpfman-agent:latest",
+				Resources: agentResources,
+			},
+			Daemon: v1alpha1.DaemonSpec{
+				Image:             "quay.io/bpfman/bpfman:latest",
+				Resources:         daemonResources,
+				Tolerations:       []corev1.Toleration{edgeToleration, controlPlaneToleration},
+				NodeSelector:      map[string]string{"bpfman": "enabled"},
+				PriorityClassName: "system-node-critical",
+			},
+			MetricsProxy: v1alpha1.MetricsProxySpec{
+				Resources:         proxyResources,
+				Tolerations:       []corev1.Toleration{edgeToleration},
+				NodeSelector:      map[string]string{"bpfman": "enabled"},
+				PriorityClassName: "system-cluster-critical",
+			},
+		},
+	}
+
+	ds := &appsv1.DaemonSet{}
+	ds, err := load(ds, resolveConfigPath(internal.BpfmanDaemonManifestPath), internal.BpfmanDsName)
+	require.NoError(t, err)
+	staticTolerations := len(ds.Spec.Template.Spec.Tolerations)
+	configureBpfmanDs(ds, config)
+
+	podSpec := ds.Spec.Template.Spec
+	// Tolerations already in the manifest are not duplicated.
+	require.Len(t, podSpec.Tolerations, staticTolerations+1)
+	require.Contains(t, podSpec.Tolerations, edgeToleration)
+	require.Equal(t, map[string]string{"bpfman": "enabled"}, podSpec.NodeSelector)
+	require.Equal(t, "system-node-critical", podSpec.PriorityClassName)
+	for _, c := range podSpec.Containers {
+		switch c.Name {
+		case internal.BpfmanContainerName:
+			require.Equal(t, *daemonResources, c.Resources)
+		case internal.BpfmanAgentContainerName:
+			require.Equal(t, *agentResources, c.Resources)
+		default:
+			require.Empty(t, c.Resources)
+		}
+	}
+
+	proxyDs := &appsv1.DaemonSet{}
+	proxyDs, err = load(proxyDs, resolveConfigPath(internal.BpfmanMetricsProxyPath), internal.BpfmanMetricsProxyDsName)
+	require.NoError(t, err)
+	configureMetricsProxyDs(proxyDs, config, false)
+
+	podSpec = proxyDs.Spec.Template.Spec
+	require.Contains(t, podSpec.Tolerations, edgeToleration)
+	require.Equal(t, map[string]string{"bpfman": "enabled"}, podSpec.NodeSelector)
+	require.Equal(t, "system-cluster-critical", podSpec.PriorityClassName)
+	require.Equal(t, *proxyResources, podSpec.Containers[0].Resources)
+}
+
 func setOverrides(ctx context.Context, cl client.Client) error {
 	bpfmanConfig := &v1alpha1.Config{}
 	if err := cl.Get(ctx, types.NamespacedName{Name: internal.BpfmanConfigName}, bpfmanConfig); err != nil {
@@ -1651,3 +1730,23 @@ func TestImagePullPolicyEnvVar(t *testing.T) {
 		})
 	}
 }
+
+func TestLinkDriftArgs(t *testing.T) {
+	args := linkDriftArgs([]string{"--health-probe-bind-address=:8175"}, &v1alpha1.LinkDriftSpec{})
+	require.Equal(t, []string{
+		"--health-probe-bind-address=:8175",
+		"--link-drift-interval=5m0s",
+		"--link-drift-mode=Repair",
+	}, args)
+
+	// The arguments are replaced on later reconciles.
+	args = linkDriftArgs(args, &v1alpha1.LinkDriftSpec{
+		Interval: &metav1.Duration{Duration: 0},
+		Mode:     v1alpha1.LinkDriftReport,
+	})
+	require.Equal(t, []string{
+		"--health-probe-bind-address=:8175",
+		"--link-drift-interval=0s",
+		"--link-drift-mode=Report",
+	}, args)
+}
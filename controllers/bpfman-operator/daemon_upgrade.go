@@ -0,0 +1,456 @@
+/*
+Copyright 2025 The bpfman Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bpfmanoperator
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/bpfman/bpfman-operator/apis/v1alpha1"
+	"github.com/bpfman/bpfman-operator/internal"
+	"github.com/bpfman/bpfman-operator/pkg/helpers"
+)
+
+// +kubebuilder:rbac:groups=core,resources=pods,verbs=delete
+// +kubebuilder:rbac:groups=apps,resources=controllerrevisions,verbs=get;list;watch
+
+// upgradeSettleTime is how long a replaced bpfman pod must have been ready
+// before the programs of its node are verified, so that the agent had time
+// to reconcile the applications of the node.
+const upgradeSettleTime = 30 * time.Second
+
+const (
+	configReasonUpgradeVerified = "UpgradeVerified"
+	configReasonProgramsLost    = "ProgramsLost"
+)
+
+// controlledUpgrade returns whether the bpfman DaemonSets of config are
+// upgraded by the operator.
+func controlledUpgrade(config *v1alpha1.Config) bool {
+	return config.Spec.Daemon.Upgrade.Strategy == v1alpha1.DaemonUpgradeControlled
+}
+
+// configureUpgradeStrategy lets the operator replace the pods of ds when the
+// upgrade is controlled.
+func configureUpgradeStrategy(ds *appsv1.DaemonSet, config *v1alpha1.Config) {
+	if controlledUpgrade(config) {
+		ds.Spec.UpdateStrategy = appsv1.DaemonSetUpdateStrategy{Type: appsv1.OnDeleteDaemonSetStrategyType}
+	}
+}
+
+// bpfmanDaemonSetNames returns the names of the bpfman DaemonSets of config.
+func bpfmanDaemonSetNames(config *v1alpha1.Config) []string {
+	names := []string{internal.BpfmanDsName}
+	for i := range config.Spec.NodePools {
+		names = append(names, nodePoolDsName(&config.Spec.NodePools[i]))
+	}
+	return names
+}
+
+// reconcileDaemonUpgrades drives the controlled upgrade of each bpfman
+// DaemonSet and reports it in the Config status.
+func (r *BpfmanConfigReconciler) reconcileDaemonUpgrades(ctx context.Context, bpfmanConfig *v1alpha1.Config) error {
+	status := bpfmanConfig.Status.DeepCopy()
+	paused := metav1.Condition{
+		Type:   string(v1alpha1.ConfigConditionUpgradePaused),
+		Status: metav1.ConditionFalse,
+		Reason: configReasonAsExpected,
+	}
+
+	if !controlledUpgrade(bpfmanConfig) {
+		status.DaemonUpgrades = nil
+		meta.RemoveStatusCondition(&status.Conditions, paused.Type)
+		return r.updateUpgradeStatus(ctx, bpfmanConfig, status)
+	}
+
+	var upgrades []v1alpha1.DaemonUpgradeStatus
+	var failures []string
+	for _, name := range bpfmanDaemonSetNames(bpfmanConfig) {
+		var upgrade *v1alpha1.DaemonUpgradeStatus
+		if i := slices.IndexFunc(status.DaemonUpgrades, func(u v1alpha1.DaemonUpgradeStatus) bool {
+			return u.DaemonSet == name
+		}); i >= 0 {
+			upgrade = &status.DaemonUpgrades[i]
+		}
+
+		upgrade, failed, err := r.upgradeDaemonSet(ctx, bpfmanConfig, status, name, upgrade)
+		if err != nil {
+			return fmt.Errorf("upgrade DaemonSet %s: %w", name, err)
+		}
+		if upgrade != nil {
+			upgrades = append(upgrades, *upgrade)
+		}
+		failures = append(failures, failed...)
+	}
+	status.DaemonUpgrades = upgrades
+
+	if len(failures) > 0 {
+		paused.Status = metav1.ConditionTrue
+		paused.Reason = configReasonProgramsLost
+		paused.Message = strings.Join(failures, "; ")
+	} else if len(upgrades) > 0 {
+		paused.Reason = configReasonUpgradeVerified
+	}
+	paused.ObservedGeneration = bpfmanConfig.Generation
+	meta.SetStatusCondition(&status.Conditions, paused)
+
+	return r.updateUpgradeStatus(ctx, bpfmanConfig, status)
+}
+
+// upgradeDaemonSet verifies the nodes of the DaemonSet name being upgraded,
+// and replaces the outdated pods of the next nodes unless the upgrade is
+// paused. Before replacing a pod, the programs of its node are recorded in
+// status, which is persisted first so that they are not lost if the operator
+// restarts. It returns the upgrade status of the DaemonSet, nil once every
+// pod is up to date, and why the upgrade is paused, if it is.
+func (r *BpfmanConfigReconciler) upgradeDaemonSet(ctx context.Context, bpfmanConfig *v1alpha1.Config,
+	status *v1alpha1.ConfigStatus, name string,
+	upgrade *v1alpha1.DaemonUpgradeStatus) (*v1alpha1.DaemonUpgradeStatus, []string, error) {
+	ds := &appsv1.DaemonSet{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: bpfmanConfig.Spec.Namespace, Name: name}, ds); err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil, nil
+		}
+		return nil, nil, err
+	}
+
+	revision, err := r.currentRevision(ctx, ds)
+	if err != nil || revision == "" {
+		return upgrade, nil, err
+	}
+
+	pods, err := r.daemonSetPods(ctx, ds)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if upgrade == nil || upgrade.Revision != revision {
+		// Nodes being verified for a previous revision are verified again
+		// once they run the new one.
+		var nodes []v1alpha1.DaemonUpgradeNode
+		if upgrade != nil {
+			nodes = upgrade.Nodes
+		}
+		upgrade = &v1alpha1.DaemonUpgradeStatus{DaemonSet: name, Revision: revision, Nodes: nodes}
+	}
+
+	// Verify the nodes whose pod was replaced.
+	var failures []string
+	var verifying []v1alpha1.DaemonUpgradeNode
+	for _, node := range upgrade.Nodes {
+		pod := podOnNode(pods, node.Node)
+		if pod == nil {
+			// The node is gone, or its pod is not created yet.
+			if r.nodeExists(ctx, node.Node) {
+				verifying = append(verifying, node)
+			}
+			continue
+		}
+		if pod.Labels[appsv1.ControllerRevisionHashLabelKey] != revision || !podSettled(pod) {
+			verifying = append(verifying, node)
+			continue
+		}
+
+		done, failure, err := r.verifyNodePrograms(ctx, &node)
+		if err != nil {
+			return nil, nil, err
+		}
+		switch {
+		case failure != "":
+			failures = append(failures, fmt.Sprintf("node %s: %s", node.Node, failure))
+			verifying = append(verifying, node)
+		case !done:
+			verifying = append(verifying, node)
+		default:
+			r.Logger.Info("Programs survived the bpfman upgrade", "DaemonSet", name, "node", node.Node)
+		}
+	}
+	upgrade.Nodes = verifying
+
+	// Replace the outdated pods of the next nodes.
+	unavailable := len(verifying)
+	var outdated []*corev1.Pod
+	for i := range pods {
+		pod := &pods[i]
+		if slices.ContainsFunc(verifying, func(n v1alpha1.DaemonUpgradeNode) bool { return n.Node == pod.Spec.NodeName }) {
+			continue
+		}
+		if !podReady(pod) {
+			unavailable++
+		}
+		if pod.Labels[appsv1.ControllerRevisionHashLabelKey] != revision && pod.DeletionTimestamp.IsZero() {
+			outdated = append(outdated, pod)
+		}
+	}
+	if len(outdated) == 0 && len(verifying) == 0 {
+		return nil, failures, nil
+	}
+	if len(failures) > 0 {
+		return upgrade, failures, nil
+	}
+
+	maxUnavailable := max(int(bpfmanConfig.Spec.Daemon.Upgrade.MaxUnavailable), 1)
+	slices.SortFunc(outdated, func(a, b *corev1.Pod) int { return strings.Compare(a.Spec.NodeName, b.Spec.NodeName) })
+	for _, pod := range outdated {
+		if unavailable >= maxUnavailable {
+			break
+		}
+		applications, err := r.snapshotNodePrograms(ctx, pod.Spec.NodeName)
+		if err != nil {
+			return nil, nil, err
+		}
+		upgrade.Nodes = append(upgrade.Nodes, v1alpha1.DaemonUpgradeNode{
+			Node:         pod.Spec.NodeName,
+			Applications: applications,
+		})
+		setDaemonUpgrade(status, *upgrade)
+		if err := r.updateUpgradeStatus(ctx, bpfmanConfig, status); err != nil {
+			return nil, nil, err
+		}
+
+		r.Logger.Info("Replacing bpfman pod", "DaemonSet", name, "node", pod.Spec.NodeName, "pod", pod.Name)
+		if err := r.Delete(ctx, pod); err != nil && !errors.IsNotFound(err) {
+			return nil, nil, fmt.Errorf("delete pod %s: %w", pod.Name, err)
+		}
+		unavailable++
+	}
+
+	return upgrade, nil, nil
+}
+
+func setDaemonUpgrade(status *v1alpha1.ConfigStatus, upgrade v1alpha1.DaemonUpgradeStatus) {
+	for i := range status.DaemonUpgrades {
+		if status.DaemonUpgrades[i].DaemonSet == upgrade.DaemonSet {
+			status.DaemonUpgrades[i] = upgrade
+			return
+		}
+	}
+	status.DaemonUpgrades = append(status.DaemonUpgrades, upgrade)
+}
+
+// updateUpgradeStatus persists status as the status of bpfmanConfig if it
+// changed.
+func (r *BpfmanConfigReconciler) updateUpgradeStatus(ctx context.Context, bpfmanConfig *v1alpha1.Config,
+	status *v1alpha1.ConfigStatus) error {
+	if equality.Semantic.DeepEqual(&bpfmanConfig.Status, status) {
+		return nil
+	}
+	bpfmanConfig.Status = *status.DeepCopy()
+	if err := r.Status().Update(ctx, bpfmanConfig); err != nil {
+		return fmt.Errorf("update Config status: %w", err)
+	}
+	return nil
+}
+
+// currentRevision returns the controller revision hash of the current pod
+// template of ds, or an empty string if the DaemonSet controller has not
+// recorded it yet.
+func (r *BpfmanConfigReconciler) currentRevision(ctx context.Context, ds *appsv1.DaemonSet) (string, error) {
+	revisions := &appsv1.ControllerRevisionList{}
+	if err := r.List(ctx, revisions, client.InNamespace(ds.Namespace)); err != nil {
+		return "", fmt.Errorf("list ControllerRevisions: %w", err)
+	}
+
+	var current *appsv1.ControllerRevision
+	for i := range revisions.Items {
+		revision := &revisions.Items[i]
+		if !metav1.IsControlledBy(revision, ds) {
+			continue
+		}
+		if current == nil || revision.Revision > current.Revision {
+			current = revision
+		}
+	}
+	if current == nil {
+		return "", nil
+	}
+	return current.Labels[appsv1.ControllerRevisionHashLabelKey], nil
+}
+
+// daemonSetPods returns the pods of ds.
+func (r *BpfmanConfigReconciler) daemonSetPods(ctx context.Context, ds *appsv1.DaemonSet) ([]corev1.Pod, error) {
+	selector, err := metav1.LabelSelectorAsSelector(ds.Spec.Selector)
+	if err != nil {
+		return nil, err
+	}
+	podList := &corev1.PodList{}
+	if err := r.List(ctx, podList, client.InNamespace(ds.Namespace),
+		client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return nil, fmt.Errorf("list pods: %w", err)
+	}
+
+	var pods []corev1.Pod
+	for _, pod := range podList.Items {
+		if metav1.IsControlledBy(&pod, ds) {
+			pods = append(pods, pod)
+		}
+	}
+	return pods, nil
+}
+
+func (r *BpfmanConfigReconciler) nodeExists(ctx context.Context, name string) bool {
+	err := r.Get(ctx, types.NamespacedName{Name: name}, &corev1.Node{})
+	return !errors.IsNotFound(err)
+}
+
+func podOnNode(pods []corev1.Pod, node string) *corev1.Pod {
+	for i := range pods {
+		if pods[i].Spec.NodeName == node && pods[i].DeletionTimestamp.IsZero() {
+			return &pods[i]
+		}
+	}
+	return nil
+}
+
+func podReady(pod *corev1.Pod) bool {
+	return podReadyCondition(pod) != nil
+}
+
+// podSettled returns whether pod has been ready for upgradeSettleTime.
+func podSettled(pod *corev1.Pod) bool {
+	ready := podReadyCondition(pod)
+	return ready != nil && time.Since(ready.LastTransitionTime.Time) >= upgradeSettleTime
+}
+
+func podReadyCondition(pod *corev1.Pod) *corev1.PodCondition {
+	for i, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady && condition.Status == corev1.ConditionTrue {
+			return &pod.Status.Conditions[i]
+		}
+	}
+	return nil
+}
+
+// applicationState is the part of an application state checked by a
+// controlled upgrade.
+type applicationState struct {
+	snapshot   v1alpha1.ApplicationStateSnapshot
+	conditions []metav1.Condition
+}
+
+// nodeApplicationStates returns the application states of node.
+func (r *BpfmanConfigReconciler) nodeApplicationStates(ctx context.Context, node string) ([]applicationState, error) {
+	onNode := client.MatchingLabels{internal.K8sHostLabel: node}
+	var states []applicationState
+
+	clusterStates := &v1alpha1.ClusterBpfApplicationStateList{}
+	if err := r.List(ctx, clusterStates, onNode); err != nil {
+		return nil, fmt.Errorf("list ClusterBpfApplicationStates: %w", err)
+	}
+	for _, state := range clusterStates.Items {
+		snapshot := v1alpha1.ApplicationStateSnapshot{Kind: "ClusterBpfApplicationState", Name: state.Name}
+		for _, program := range state.Status.Programs {
+			if program.ProgramId != nil {
+				snapshot.ProgramIDs = append(snapshot.ProgramIDs, *program.ProgramId)
+			}
+		}
+		states = append(states, applicationState{snapshot: snapshot, conditions: state.Status.Conditions})
+	}
+
+	nsStates := &v1alpha1.BpfApplicationStateList{}
+	if err := r.List(ctx, nsStates, onNode); err != nil {
+		return nil, fmt.Errorf("list BpfApplicationStates: %w", err)
+	}
+	for _, state := range nsStates.Items {
+		snapshot := v1alpha1.ApplicationStateSnapshot{
+			Kind:      "BpfApplicationState",
+			Namespace: state.Namespace,
+			Name:      state.Name,
+		}
+		for _, program := range state.Status.Programs {
+			if program.ProgramId != nil {
+				snapshot.ProgramIDs = append(snapshot.ProgramIDs, *program.ProgramId)
+			}
+		}
+		states = append(states, applicationState{snapshot: snapshot, conditions: state.Status.Conditions})
+	}
+
+	for i := range states {
+		slices.Sort(states[i].snapshot.ProgramIDs)
+	}
+	return states, nil
+}
+
+// snapshotNodePrograms returns the program IDs of the application states of
+// node that are loaded successfully.
+func (r *BpfmanConfigReconciler) snapshotNodePrograms(ctx context.Context,
+	node string) ([]v1alpha1.ApplicationStateSnapshot, error) {
+	states, err := r.nodeApplicationStates(ctx, node)
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshots []v1alpha1.ApplicationStateSnapshot
+	for _, state := range states {
+		if meta.IsStatusConditionTrue(state.conditions, string(v1alpha1.BpfAppStateCondSuccess)) {
+			snapshots = append(snapshots, state.snapshot)
+		}
+	}
+	return snapshots, nil
+}
+
+// verifyNodePrograms checks that the application states recorded in node
+// are back to Success with the same program IDs. It returns whether they
+// are, or why they are not if they failed. Applications deleted during the
+// upgrade are ignored.
+func (r *BpfmanConfigReconciler) verifyNodePrograms(ctx context.Context,
+	node *v1alpha1.DaemonUpgradeNode) (bool, string, error) {
+	states, err := r.nodeApplicationStates(ctx, node.Node)
+	if err != nil {
+		return false, "", err
+	}
+
+	done := true
+	var failures []string
+	for _, snapshot := range node.Applications {
+		i := slices.IndexFunc(states, func(s applicationState) bool {
+			return s.snapshot.Kind == snapshot.Kind && s.snapshot.Namespace == snapshot.Namespace &&
+				s.snapshot.Name == snapshot.Name
+		})
+		if i < 0 {
+			continue
+		}
+		state := states[i]
+		name := snapshot.Name
+		if snapshot.Namespace != "" {
+			name = snapshot.Namespace + "/" + name
+		}
+
+		switch {
+		case helpers.IsBpfAppStateConditionPending(state.conditions):
+			done = false
+		case !meta.IsStatusConditionTrue(state.conditions, string(v1alpha1.BpfAppStateCondSuccess)):
+			failures = append(failures, fmt.Sprintf("%s %s is %s", snapshot.Kind, name, state.conditions[0].Type))
+		case !slices.Equal(state.snapshot.ProgramIDs, snapshot.ProgramIDs):
+			failures = append(failures, fmt.Sprintf("%s %s programs were reloaded: IDs %v, were %v",
+				snapshot.Kind, name, state.snapshot.ProgramIDs, snapshot.ProgramIDs))
+		}
+	}
+	return done && len(failures) == 0, strings.Join(failures, ", "), nil
+}
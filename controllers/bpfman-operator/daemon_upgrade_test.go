@@ -0,0 +1,255 @@
+/*
+Copyright 2025 The bpfman Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bpfmanoperator
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/bpfman/bpfman-operator/apis/v1alpha1"
+	"github.com/bpfman/bpfman-operator/internal"
+)
+
+func TestControlledDaemonUpgrade(t *testing.T) {
+	r, _, req, ctx, cl := setupTestEnvironment(false, false)
+
+	config := getConfig(t, ctx, cl)
+	config.Spec.Daemon.Upgrade = v1alpha1.DaemonUpgradeSpec{
+		Strategy:       v1alpha1.DaemonUpgradeControlled,
+		MaxUnavailable: 1,
+	}
+	require.NoError(t, cl.Update(ctx, config))
+
+	// The first reconcile adds the finalizer.
+	_, err := r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	_, err = r.Reconcile(ctx, req)
+	require.NoError(t, err)
+
+	// The operator replaces the pods itself.
+	ds := &appsv1.DaemonSet{}
+	require.NoError(t, cl.Get(ctx, types.NamespacedName{Namespace: config.Spec.Namespace, Name: internal.BpfmanDsName}, ds))
+	require.Equal(t, appsv1.OnDeleteDaemonSetStrategyType, ds.Spec.UpdateStrategy.Type)
+
+	createRevision(t, ctx, cl, ds, "rev1", 1)
+	for _, node := range []string{"node-1", "node-2"} {
+		require.NoError(t, cl.Create(ctx, &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: node}}))
+		createDaemonPod(t, ctx, cl, ds, node, "rev1")
+	}
+	createAppState(t, ctx, cl, "app-node-1", "node-1", 10)
+	createAppState(t, ctx, cl, "app-node-2", "node-2", 20)
+
+	// Nothing to do while every pod is up to date.
+	_, err = r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	config = getConfig(t, ctx, cl)
+	require.Empty(t, config.Status.DaemonUpgrades)
+	requireConfigCondition(t, config, v1alpha1.ConfigConditionUpgradePaused, metav1.ConditionFalse)
+
+	// A new revision replaces the pod of one node at a time, after
+	// recording its programs.
+	createRevision(t, ctx, cl, ds, "rev2", 2)
+	_, err = r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	require.True(t, errors.IsNotFound(getDaemonPod(ctx, cl, "node-1")))
+	require.NoError(t, getDaemonPod(ctx, cl, "node-2"))
+	config = getConfig(t, ctx, cl)
+	require.Equal(t, []v1alpha1.DaemonUpgradeStatus{{
+		DaemonSet: internal.BpfmanDsName,
+		Revision:  "rev2",
+		Nodes: []v1alpha1.DaemonUpgradeNode{{
+			Node: "node-1",
+			Applications: []v1alpha1.ApplicationStateSnapshot{
+				{Kind: "ClusterBpfApplicationState", Name: "app-node-1", ProgramIDs: []uint32{10}},
+			},
+		}},
+	}}, config.Status.DaemonUpgrades)
+
+	// The next node waits until the programs of the first one are verified.
+	_, err = r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	require.NoError(t, getDaemonPod(ctx, cl, "node-2"))
+
+	createDaemonPod(t, ctx, cl, ds, "node-1", "rev2")
+	_, err = r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	require.True(t, errors.IsNotFound(getDaemonPod(ctx, cl, "node-2")))
+	config = getConfig(t, ctx, cl)
+	require.Len(t, config.Status.DaemonUpgrades[0].Nodes, 1)
+	require.Equal(t, "node-2", config.Status.DaemonUpgrades[0].Nodes[0].Node)
+
+	// Programs reloaded with new IDs pause the upgrade.
+	createDaemonPod(t, ctx, cl, ds, "node-2", "rev2")
+	state := &v1alpha1.ClusterBpfApplicationState{}
+	require.NoError(t, cl.Get(ctx, types.NamespacedName{Name: "app-node-2"}, state))
+	state.Status.Programs[0].ProgramId = ptr.To(uint32(21))
+	require.NoError(t, cl.Update(ctx, state))
+	_, err = r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	config = getConfig(t, ctx, cl)
+	paused := requireConfigCondition(t, config, v1alpha1.ConfigConditionUpgradePaused, metav1.ConditionTrue)
+	require.Equal(t, configReasonProgramsLost, paused.Reason)
+	require.Contains(t, paused.Message, "node node-2: ClusterBpfApplicationState app-node-2 programs were reloaded")
+
+	// The upgrade completes once the programs are back.
+	require.NoError(t, cl.Get(ctx, types.NamespacedName{Name: "app-node-2"}, state))
+	state.Status.Programs[0].ProgramId = ptr.To(uint32(20))
+	require.NoError(t, cl.Update(ctx, state))
+	_, err = r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	config = getConfig(t, ctx, cl)
+	require.Empty(t, config.Status.DaemonUpgrades)
+	requireConfigCondition(t, config, v1alpha1.ConfigConditionUpgradePaused, metav1.ConditionFalse)
+}
+
+func TestControlledDaemonUpgradeRequeue(t *testing.T) {
+	r, _, req, ctx, cl := setupTestEnvironment(false, false)
+
+	config := getConfig(t, ctx, cl)
+	config.Spec.Daemon.Upgrade = v1alpha1.DaemonUpgradeSpec{Strategy: v1alpha1.DaemonUpgradeControlled}
+	require.NoError(t, cl.Update(ctx, config))
+
+	// The first reconcile adds the finalizer.
+	_, err := r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	_, err = r.Reconcile(ctx, req)
+	require.NoError(t, err)
+
+	ds := &appsv1.DaemonSet{}
+	require.NoError(t, cl.Get(ctx, types.NamespacedName{Namespace: config.Spec.Namespace, Name: internal.BpfmanDsName}, ds))
+	createRevision(t, ctx, cl, ds, "rev1", 1)
+	require.NoError(t, cl.Create(ctx, &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}}))
+	createDaemonPod(t, ctx, cl, ds, "node-1", "rev1")
+	createAppState(t, ctx, cl, "app-node-1", "node-1", 10)
+
+	createRevision(t, ctx, cl, ds, "rev2", 2)
+	_, err = r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	require.True(t, errors.IsNotFound(getDaemonPod(ctx, cl, "node-1")))
+
+	// The replaced pod just became ready, and the DaemonSet reports every
+	// pod updated and ready: the node is verified again once it settled.
+	createDaemonPod(t, ctx, cl, ds, "node-1", "rev2")
+	pod := &corev1.Pod{}
+	podName := types.NamespacedName{Namespace: ds.Namespace, Name: internal.BpfmanDsName + "-node-1"}
+	require.NoError(t, cl.Get(ctx, podName, pod))
+	pod.Status.Conditions[0].LastTransitionTime = metav1.Now()
+	require.NoError(t, cl.Status().Update(ctx, pod))
+	setDaemonSetStatus(t, ctx, cl, internal.BpfmanDsName, 1, 1, 1)
+	require.NoError(t, cl.Create(ctx, &storagev1.CSINode{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+		Spec: storagev1.CSINodeSpec{Drivers: []storagev1.CSINodeDriver{
+			{Name: internal.BpfmanCsiDriverName, NodeID: "node-1"},
+		}},
+	}))
+
+	result, err := r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	require.Equal(t, upgradeSettleTime, result.RequeueAfter)
+	config = getConfig(t, ctx, cl)
+	requireConfigCondition(t, config, v1alpha1.ConfigConditionAvailable, metav1.ConditionTrue)
+	require.Len(t, config.Status.DaemonUpgrades, 1)
+
+	// Once the pod settled, the upgrade completes and nothing is requeued.
+	require.NoError(t, cl.Get(ctx, podName, pod))
+	pod.Status.Conditions[0].LastTransitionTime = metav1.NewTime(time.Now().Add(-upgradeSettleTime))
+	require.NoError(t, cl.Status().Update(ctx, pod))
+	result, err = r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	require.Zero(t, result.RequeueAfter)
+	config = getConfig(t, ctx, cl)
+	require.Empty(t, config.Status.DaemonUpgrades)
+}
+
+func createRevision(t *testing.T, ctx context.Context, cl client.Client, ds *appsv1.DaemonSet, hash string,
+	revision int64) {
+	cr := &appsv1.ControllerRevision{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            ds.Name + "-" + hash,
+			Namespace:       ds.Namespace,
+			Labels:          map[string]string{appsv1.ControllerRevisionHashLabelKey: hash},
+			OwnerReferences: []metav1.OwnerReference{daemonSetOwnerReference(ds)},
+		},
+		Revision: revision,
+	}
+	require.NoError(t, cl.Create(ctx, cr))
+}
+
+func createDaemonPod(t *testing.T, ctx context.Context, cl client.Client, ds *appsv1.DaemonSet, node, hash string) {
+	labels := map[string]string{appsv1.ControllerRevisionHashLabelKey: hash}
+	for k, v := range ds.Spec.Selector.MatchLabels {
+		labels[k] = v
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            ds.Name + "-" + node,
+			Namespace:       ds.Namespace,
+			Labels:          labels,
+			OwnerReferences: []metav1.OwnerReference{daemonSetOwnerReference(ds)},
+		},
+		Spec: corev1.PodSpec{NodeName: node},
+		Status: corev1.PodStatus{Conditions: []corev1.PodCondition{{
+			Type:               corev1.PodReady,
+			Status:             corev1.ConditionTrue,
+			LastTransitionTime: metav1.NewTime(time.Now().Add(-time.Hour)),
+		}}},
+	}
+	require.NoError(t, cl.Create(ctx, pod))
+}
+
+func getDaemonPod(ctx context.Context, cl client.Client, node string) error {
+	return cl.Get(ctx, types.NamespacedName{Namespace: internal.BpfmanNamespace, Name: internal.BpfmanDsName + "-" + node},
+		&corev1.Pod{})
+}
+
+func daemonSetOwnerReference(ds *appsv1.DaemonSet) metav1.OwnerReference {
+	return metav1.OwnerReference{
+		APIVersion: "apps/v1",
+		Kind:       "DaemonSet",
+		Name:       ds.Name,
+		UID:        ds.UID,
+		Controller: ptr.To(true),
+	}
+}
+
+func createAppState(t *testing.T, ctx context.Context, cl client.Client, name, node string, programID uint32) {
+	state := &v1alpha1.ClusterBpfApplicationState{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{internal.K8sHostLabel: node},
+		},
+		Status: v1alpha1.ClBpfApplicationStateStatus{
+			Node: node,
+			Programs: []v1alpha1.ClBpfApplicationProgramState{{
+				BpfProgramStateCommon: v1alpha1.BpfProgramStateCommon{Name: "prog", ProgramId: ptr.To(programID)},
+			}},
+			Conditions: []metav1.Condition{v1alpha1.BpfAppStateCondSuccess.Condition()},
+		},
+	}
+	require.NoError(t, cl.Create(ctx, state))
+}
@@ -0,0 +1,339 @@
+/*
+Copyright 2025 The bpfman Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bpfmanoperator
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	bpfmaniov1alpha1 "github.com/bpfman/bpfman-operator/apis/v1alpha1"
+	internal "github.com/bpfman/bpfman-operator/internal"
+	bpfmanHelpers "github.com/bpfman/bpfman-operator/pkg/helpers"
+)
+
+const (
+	// applicationMetricsTimeout bounds the time spent listing
+	// BpfApplicationState objects during a single scrape.
+	applicationMetricsTimeout = 10 * time.Second
+
+	clusterBpfApplicationKind = "ClusterBpfApplication"
+	bpfApplicationKind        = "BpfApplication"
+)
+
+var (
+	appMetricLabels = []string{"kind", "namespace", "name"}
+
+	appNodesSelectedDesc = prometheus.NewDesc(
+		"bpfman_application_nodes_selected",
+		"Number of nodes selected by the application's nodeSelector.",
+		appMetricLabels, nil)
+	appNodesSucceededDesc = prometheus.NewDesc(
+		"bpfman_application_nodes_succeeded",
+		"Number of selected nodes on which the application is fully loaded and attached.",
+		appMetricLabels, nil)
+	appNodesFailedDesc = prometheus.NewDesc(
+		"bpfman_application_nodes_failed",
+		"Number of nodes on which the application reported an error.",
+		appMetricLabels, nil)
+	appLinksDesc = prometheus.NewDesc(
+		"bpfman_application_links",
+		"Number of links across all nodes for the application, by link status.",
+		append(appMetricLabels, "status"), nil)
+	appLastTransitionDesc = prometheus.NewDesc(
+		"bpfman_application_seconds_since_last_transition",
+		"Seconds since the most recent condition transition on any node for the application.",
+		appMetricLabels, nil)
+)
+
+// applicationSummary holds the cluster-wide aggregate of all the
+// BpfApplicationState objects belonging to one application.
+type applicationSummary struct {
+	kind           string
+	namespace      string
+	name           string
+	nodesSelected  int
+	nodesSucceeded int
+	nodesFailed    int
+	links          map[bpfmaniov1alpha1.LinkStatus]int
+	lastTransition time.Time
+}
+
+type applicationKey struct {
+	kind      string
+	namespace string
+	name      string
+}
+
+// ApplicationMetricsCollector is a prometheus.Collector that publishes
+// cluster-wide metrics for each ClusterBpfApplication and BpfApplication,
+// derived from the per-node state objects written by the bpfman agents.
+// Metrics are computed at scrape time so that deleted applications do not
+// leave stale series behind.
+type ApplicationMetricsCollector struct {
+	client.Reader
+	Logger logr.Logger
+	now    func() time.Time
+}
+
+// NewApplicationMetricsCollector returns a collector that reads
+// BpfApplicationState objects using the given reader. The reader is
+// normally the manager's cached client.
+func NewApplicationMetricsCollector(reader client.Reader) *ApplicationMetricsCollector {
+	return &ApplicationMetricsCollector{
+		Reader: reader,
+		Logger: ctrl.Log.WithName("application-metrics"),
+		now:    time.Now,
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (c *ApplicationMetricsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- appNodesSelectedDesc
+	ch <- appNodesSucceededDesc
+	ch <- appNodesFailedDesc
+	ch <- appLinksDesc
+	ch <- appLastTransitionDesc
+}
+
+// Collect implements prometheus.Collector.
+func (c *ApplicationMetricsCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), applicationMetricsTimeout)
+	defer cancel()
+
+	for _, summary := range c.summarize(ctx) {
+		labels := []string{summary.kind, summary.namespace, summary.name}
+		ch <- prometheus.MustNewConstMetric(appNodesSelectedDesc, prometheus.GaugeValue,
+			float64(summary.nodesSelected), labels...)
+		ch <- prometheus.MustNewConstMetric(appNodesSucceededDesc, prometheus.GaugeValue,
+			float64(summary.nodesSucceeded), labels...)
+		ch <- prometheus.MustNewConstMetric(appNodesFailedDesc, prometheus.GaugeValue,
+			float64(summary.nodesFailed), labels...)
+		for _, status := range []bpfmaniov1alpha1.LinkStatus{
+			bpfmaniov1alpha1.ApAttachAttached,
+			bpfmaniov1alpha1.ApAttachNotAttached,
+			bpfmaniov1alpha1.ApAttachError,
+			bpfmaniov1alpha1.ApDetachError,
+			bpfmaniov1alpha1.ApAttachModeError,
+		} {
+			ch <- prometheus.MustNewConstMetric(appLinksDesc, prometheus.GaugeValue,
+				float64(summary.links[status]), append(labels, string(status))...)
+		}
+		if !summary.lastTransition.IsZero() {
+			ch <- prometheus.MustNewConstMetric(appLastTransitionDesc, prometheus.GaugeValue,
+				c.now().Sub(summary.lastTransition).Seconds(), labels...)
+		}
+	}
+}
+
+// summarize lists all cluster and namespace scoped BpfApplicationState
+// objects and aggregates them per owning application. Errors are logged and
+// the affected scope is skipped so that a partial result is still exported.
+func (c *ApplicationMetricsCollector) summarize(ctx context.Context) []*applicationSummary {
+	summaries := map[applicationKey]*applicationSummary{}
+	order := []applicationKey{}
+
+	getSummary := func(key applicationKey) *applicationSummary {
+		s, ok := summaries[key]
+		if !ok {
+			s = &applicationSummary{
+				kind:      key.kind,
+				namespace: key.namespace,
+				name:      key.name,
+				links:     map[bpfmaniov1alpha1.LinkStatus]int{},
+			}
+			summaries[key] = s
+			order = append(order, key)
+		}
+		return s
+	}
+
+	clStates := &bpfmaniov1alpha1.ClusterBpfApplicationStateList{}
+	if err := c.List(ctx, clStates); err != nil {
+		c.Logger.Error(err, "failed to list ClusterBpfApplicationState objects")
+	} else {
+		for i := range clStates.Items {
+			state := &clStates.Items[i]
+			owner, ok := state.Labels[internal.BpfAppStateOwner]
+			if !ok {
+				continue
+			}
+			s := getSummary(applicationKey{kind: clusterBpfApplicationKind, name: owner})
+			s.addNode(state.Status.AppLoadStatus, state.Status.Conditions)
+			for _, program := range state.Status.Programs {
+				countClLinks(&program, s.links)
+			}
+		}
+	}
+
+	nsStates := &bpfmaniov1alpha1.BpfApplicationStateList{}
+	if err := c.List(ctx, nsStates); err != nil {
+		c.Logger.Error(err, "failed to list BpfApplicationState objects")
+	} else {
+		for i := range nsStates.Items {
+			state := &nsStates.Items[i]
+			owner, ok := state.Labels[internal.BpfAppStateOwner]
+			if !ok {
+				continue
+			}
+			s := getSummary(applicationKey{kind: bpfApplicationKind, namespace: state.Namespace, name: owner})
+			s.addNode(state.Status.AppLoadStatus, state.Status.Conditions)
+			for _, program := range state.Status.Programs {
+				countNsLinks(&program, s.links)
+			}
+		}
+	}
+
+	result := make([]*applicationSummary, 0, len(order))
+	for _, key := range order {
+		result = append(result, summaries[key])
+	}
+	return result
+}
+
+// addNode folds the state reported by one node into the summary.
+func (s *applicationSummary) addNode(loadStatus bpfmaniov1alpha1.AppLoadStatus, conditions []metav1.Condition) {
+	for _, cond := range conditions {
+		if cond.LastTransitionTime.After(s.lastTransition) {
+			s.lastTransition = cond.LastTransitionTime.Time
+		}
+	}
+
+	if loadStatus == bpfmaniov1alpha1.NotSelected {
+		return
+	}
+	s.nodesSelected++
+
+	if len(conditions) == 0 {
+		// The agent has not reported yet.
+		return
+	}
+	if conditions[0].Type == string(bpfmaniov1alpha1.BpfAppStateCondSuccess) {
+		s.nodesSucceeded++
+	} else if bpfmanHelpers.IsBpfAppStateConditionFailure(conditions) {
+		s.nodesFailed++
+	}
+}
+
+// countClLinks adds the link status of every attach point of a cluster
+// scoped program to counts.
+func countClLinks(program *bpfmaniov1alpha1.ClBpfApplicationProgramState, counts map[bpfmaniov1alpha1.LinkStatus]int) {
+	switch program.Type {
+	case bpfmaniov1alpha1.ProgTypeXDP:
+		if program.XDP != nil {
+			for _, link := range program.XDP.Links {
+				counts[link.LinkStatus]++
+			}
+		}
+	case bpfmaniov1alpha1.ProgTypeTC:
+		if program.TC != nil {
+			for _, link := range program.TC.Links {
+				counts[link.LinkStatus]++
+			}
+		}
+	case bpfmaniov1alpha1.ProgTypeTCX:
+		if program.TCX != nil {
+			for _, link := range program.TCX.Links {
+				counts[link.LinkStatus]++
+			}
+		}
+	case bpfmaniov1alpha1.ProgTypeFentry:
+		if program.FEntry != nil {
+			for _, link := range program.FEntry.Links {
+				counts[link.LinkStatus]++
+			}
+		}
+	case bpfmaniov1alpha1.ProgTypeFexit:
+		if program.FExit != nil {
+			for _, link := range program.FExit.Links {
+				counts[link.LinkStatus]++
+			}
+		}
+	case bpfmaniov1alpha1.ProgTypeKprobe:
+		if program.KProbe != nil {
+			for _, link := range program.KProbe.Links {
+				counts[link.LinkStatus]++
+			}
+		}
+	case bpfmaniov1alpha1.ProgTypeKretprobe:
+		if program.KRetProbe != nil {
+			for _, link := range program.KRetProbe.Links {
+				counts[link.LinkStatus]++
+			}
+		}
+	case bpfmaniov1alpha1.ProgTypeUprobe:
+		if program.UProbe != nil {
+			for _, link := range program.UProbe.Links {
+				counts[link.LinkStatus]++
+			}
+		}
+	case bpfmaniov1alpha1.ProgTypeUretprobe:
+		if program.URetProbe != nil {
+			for _, link := range program.URetProbe.Links {
+				counts[link.LinkStatus]++
+			}
+		}
+	case bpfmaniov1alpha1.ProgTypeTracepoint:
+		if program.TracePoint != nil {
+			for _, link := range program.TracePoint.Links {
+				counts[link.LinkStatus]++
+			}
+		}
+	}
+}
+
+// countNsLinks adds the link status of every attach point of a namespace
+// scoped program to counts.
+func countNsLinks(program *bpfmaniov1alpha1.BpfApplicationProgramState, counts map[bpfmaniov1alpha1.LinkStatus]int) {
+	switch program.Type {
+	case bpfmaniov1alpha1.ProgTypeXDP:
+		if program.XDP != nil {
+			for _, link := range program.XDP.Links {
+				counts[link.LinkStatus]++
+			}
+		}
+	case bpfmaniov1alpha1.ProgTypeTC:
+		if program.TC != nil {
+			for _, link := range program.TC.Links {
+				counts[link.LinkStatus]++
+			}
+		}
+	case bpfmaniov1alpha1.ProgTypeTCX:
+		if program.TCX != nil {
+			for _, link := range program.TCX.Links {
+				counts[link.LinkStatus]++
+			}
+		}
+	case bpfmaniov1alpha1.ProgTypeUprobe:
+		if program.UProbe != nil {
+			for _, link := range program.UProbe.Links {
+				counts[link.LinkStatus]++
+			}
+		}
+	case bpfmaniov1alpha1.ProgTypeUretprobe:
+		if program.URetProbe != nil {
+			for _, link := range program.URetProbe.Links {
+				counts[link.LinkStatus]++
+			}
+		}
+	}
+}
@@ -0,0 +1,146 @@
+/*
+Copyright 2025 The bpfman Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bpfmanoperator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	bpfmaniov1alpha1 "github.com/bpfman/bpfman-operator/apis/v1alpha1"
+	internal "github.com/bpfman/bpfman-operator/internal"
+)
+
+func newClAppState(name, appName, node string, loadStatus bpfmaniov1alpha1.AppLoadStatus,
+	cond bpfmaniov1alpha1.BpfApplicationStateConditionType, transition time.Time,
+	links ...bpfmaniov1alpha1.LinkStatus) *bpfmaniov1alpha1.ClusterBpfApplicationState {
+	xdpLinks := []bpfmaniov1alpha1.ClXdpAttachInfoState{}
+	for _, status := range links {
+		xdpLinks = append(xdpLinks, bpfmaniov1alpha1.ClXdpAttachInfoState{
+			AttachInfoStateCommon: bpfmaniov1alpha1.AttachInfoStateCommon{LinkStatus: status},
+		})
+	}
+
+	condition := cond.Condition()
+	condition.LastTransitionTime = metav1.NewTime(transition)
+
+	return &bpfmaniov1alpha1.ClusterBpfApplicationState{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{internal.BpfAppStateOwner: appName, internal.K8sHostLabel: node},
+		},
+		Status: bpfmaniov1alpha1.ClBpfApplicationStateStatus{
+			Node:          node,
+			AppLoadStatus: loadStatus,
+			Conditions:    []metav1.Condition{condition},
+			Programs: []bpfmaniov1alpha1.ClBpfApplicationProgramState{
+				{
+					Type: bpfmaniov1alpha1.ProgTypeXDP,
+					XDP:  &bpfmaniov1alpha1.ClXdpProgramInfoState{Links: xdpLinks},
+				},
+			},
+		},
+	}
+}
+
+// gaugeValue returns the value of the gauge in family whose labels match
+// all of the given labels.
+func gaugeValue(t *testing.T, families []*dto.MetricFamily, name string, labels map[string]string) float64 {
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+	metrics:
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if want, ok := labels[label.GetName()]; ok && want != label.GetValue() {
+					continue metrics
+				}
+			}
+			return metric.GetGauge().GetValue()
+		}
+	}
+	t.Fatalf("metric %s with labels %v not found", name, labels)
+	return 0
+}
+
+func TestApplicationMetricsCollector(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	objs := []runtime.Object{
+		newClAppState("app-a-1", "app-a", "node-1", bpfmaniov1alpha1.AppLoadSuccess,
+			bpfmaniov1alpha1.BpfAppStateCondSuccess, now.Add(-2*time.Minute),
+			bpfmaniov1alpha1.ApAttachAttached, bpfmaniov1alpha1.ApAttachAttached),
+		newClAppState("app-a-2", "app-a", "node-2", bpfmaniov1alpha1.AppLoadError,
+			bpfmaniov1alpha1.BpfAppStateCondError, now.Add(-30*time.Second),
+			bpfmaniov1alpha1.ApAttachAttached, bpfmaniov1alpha1.ApAttachError),
+		newClAppState("app-a-3", "app-a", "node-3", bpfmaniov1alpha1.NotSelected,
+			bpfmaniov1alpha1.BpfAppStateCondSuccess, now.Add(-time.Hour)),
+		&bpfmaniov1alpha1.BpfApplicationState{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "app-b-1",
+				Namespace: "ns-b",
+				Labels:    map[string]string{internal.BpfAppStateOwner: "app-b", internal.K8sHostLabel: "node-1"},
+			},
+			Status: bpfmaniov1alpha1.BpfApplicationStateStatus{
+				Node:          "node-1",
+				AppLoadStatus: bpfmaniov1alpha1.AppLoadNotLoaded,
+			},
+		},
+	}
+
+	s := scheme.Scheme
+	s.AddKnownTypes(bpfmaniov1alpha1.SchemeGroupVersion, &bpfmaniov1alpha1.ClusterBpfApplicationState{})
+	s.AddKnownTypes(bpfmaniov1alpha1.SchemeGroupVersion, &bpfmaniov1alpha1.ClusterBpfApplicationStateList{})
+	s.AddKnownTypes(bpfmaniov1alpha1.SchemeGroupVersion, &bpfmaniov1alpha1.BpfApplicationState{})
+	s.AddKnownTypes(bpfmaniov1alpha1.SchemeGroupVersion, &bpfmaniov1alpha1.BpfApplicationStateList{})
+
+	cl := fake.NewClientBuilder().WithRuntimeObjects(objs...).Build()
+
+	collector := NewApplicationMetricsCollector(cl)
+	collector.now = func() time.Time { return now }
+
+	registry := prometheus.NewRegistry()
+	require.NoError(t, registry.Register(collector))
+
+	families, err := registry.Gather()
+	require.NoError(t, err)
+
+	appA := map[string]string{"kind": clusterBpfApplicationKind, "namespace": "", "name": "app-a"}
+	require.Equal(t, 2.0, gaugeValue(t, families, "bpfman_application_nodes_selected", appA))
+	require.Equal(t, 1.0, gaugeValue(t, families, "bpfman_application_nodes_succeeded", appA))
+	require.Equal(t, 1.0, gaugeValue(t, families, "bpfman_application_nodes_failed", appA))
+	require.Equal(t, 3.0, gaugeValue(t, families, "bpfman_application_links",
+		map[string]string{"name": "app-a", "status": string(bpfmaniov1alpha1.ApAttachAttached)}))
+	require.Equal(t, 1.0, gaugeValue(t, families, "bpfman_application_links",
+		map[string]string{"name": "app-a", "status": string(bpfmaniov1alpha1.ApAttachError)}))
+	require.Equal(t, 30.0, gaugeValue(t, families, "bpfman_application_seconds_since_last_transition", appA))
+
+	// An application whose agent has not reported a condition yet is
+	// selected but neither succeeded nor failed.
+	appB := map[string]string{"kind": bpfApplicationKind, "namespace": "ns-b", "name": "app-b"}
+	require.Equal(t, 1.0, gaugeValue(t, families, "bpfman_application_nodes_selected", appB))
+	require.Equal(t, 0.0, gaugeValue(t, families, "bpfman_application_nodes_succeeded", appB))
+	require.Equal(t, 0.0, gaugeValue(t, families, "bpfman_application_nodes_failed", appB))
+}
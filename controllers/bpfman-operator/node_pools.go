@@ -0,0 +1,324 @@
+/*
+Copyright 2025 The bpfman Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bpfmanoperator
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/bpfman/bpfman-operator/apis/v1alpha1"
+	"github.com/bpfman/bpfman-operator/internal"
+	"github.com/bpfman/bpfman-operator/internal/bpfmanconfig"
+)
+
+// maxNodePoolAffinityTerms bounds the number of node selector terms needed
+// to keep the default DaemonSet off the nodes of every pool, which grows
+// with the product of the sizes of the pool node selectors.
+const maxNodePoolAffinityTerms = 64
+
+// nodePoolDsName returns the name of the bpfman DaemonSet of pool.
+func nodePoolDsName(pool *v1alpha1.NodePool) string {
+	return internal.BpfmanDsName + "-" + pool.Name
+}
+
+// nodePoolCmName returns the name of the bpfman ConfigMap of pool.
+func nodePoolCmName(pool *v1alpha1.NodePool) string {
+	return internal.BpfmanCmName + "-" + pool.Name
+}
+
+// nodePoolDaemon returns the daemon settings of pool.
+func nodePoolDaemon(config *v1alpha1.Config, pool *v1alpha1.NodePool) *v1alpha1.DaemonSpec {
+	daemon := config.Spec.Daemon.DeepCopy()
+	if pool.Image != "" {
+		daemon.Image = pool.Image
+	}
+	if pool.LogLevel != "" {
+		daemon.LogLevel = pool.LogLevel
+	}
+	return daemon
+}
+
+// renderNodePoolTOML returns the content of bpfman.toml on the nodes of pool:
+// the configuration of config with the configuration of pool applied on top.
+func renderNodePoolTOML(config *v1alpha1.Config, pool *v1alpha1.NodePool) (string, error) {
+	bpfmanTOML, err := renderBpfmanTOML(config)
+	if err != nil || pool.Configuration == "" {
+		return bpfmanTOML, err
+	}
+
+	doc, err := bpfmanconfig.Parse(bpfmanTOML)
+	if err != nil {
+		return "", &invalidConfigurationError{err: fmt.Errorf("configuration: %w", err)}
+	}
+	override, err := bpfmanconfig.Parse(pool.Configuration)
+	if err != nil {
+		return "", &invalidConfigurationError{err: fmt.Errorf("node pool %s: configuration: %w", pool.Name, err)}
+	}
+	bpfmanconfig.Merge(doc, override)
+	if err := bpfmanconfig.Validate(doc); err != nil {
+		return "", &invalidConfigurationError{err: fmt.Errorf("node pool %s: %w", pool.Name, err)}
+	}
+	return bpfmanconfig.Render(doc)
+}
+
+// validateNodePools checks that the node pools of config can't select the
+// same node, and renders their configuration.
+func validateNodePools(config *v1alpha1.Config) error {
+	pools := config.Spec.NodePools
+	for i := range pools {
+		for j := i + 1; j < len(pools); j++ {
+			if !nodeSelectorsConflict(pools[i].NodeSelector, pools[j].NodeSelector) {
+				return &invalidConfigurationError{err: fmt.Errorf(
+					"node pools %s and %s may select the same nodes: their node selectors must set a common label to different values",
+					pools[i].Name, pools[j].Name)}
+			}
+		}
+		if _, err := renderNodePoolTOML(config, &pools[i]); err != nil {
+			return err
+		}
+	}
+
+	if terms := len(excludeNodePoolsTerms(pools)); terms > maxNodePoolAffinityTerms {
+		return &invalidConfigurationError{err: fmt.Errorf(
+			"node pools need %d node affinity terms to be excluded from the default DaemonSet, at most %d are supported",
+			terms, maxNodePoolAffinityTerms)}
+	}
+	return nil
+}
+
+// nodeSelectorsConflict returns whether no node can match both a and b.
+func nodeSelectorsConflict(a, b map[string]string) bool {
+	for key, value := range a {
+		if other, ok := b[key]; ok && other != value {
+			return true
+		}
+	}
+	return false
+}
+
+// excludeNodePoolsTerms returns the node selector terms matching the nodes
+// that don't belong to any of pools. A node is outside of a pool if any
+// label of the pool node selector doesn't match, so the terms are the
+// combinations of one mismatching label per pool.
+func excludeNodePoolsTerms(pools []v1alpha1.NodePool) []corev1.NodeSelectorTerm {
+	if len(pools) == 0 {
+		return nil
+	}
+
+	terms := [][]corev1.NodeSelectorRequirement{nil}
+	for _, pool := range pools {
+		var next [][]corev1.NodeSelectorRequirement
+		for _, term := range terms {
+			for _, key := range slices.Sorted(maps.Keys(pool.NodeSelector)) {
+				requirement := corev1.NodeSelectorRequirement{
+					Key:      key,
+					Operator: corev1.NodeSelectorOpNotIn,
+					Values:   []string{pool.NodeSelector[key]},
+				}
+				next = append(next, append(slices.Clone(term), requirement))
+			}
+		}
+		terms = next
+	}
+
+	nodeSelectorTerms := make([]corev1.NodeSelectorTerm, len(terms))
+	for i, term := range terms {
+		nodeSelectorTerms[i] = corev1.NodeSelectorTerm{MatchExpressions: term}
+	}
+	return nodeSelectorTerms
+}
+
+// excludeNodePools keeps the pods of podSpec off the nodes of pools.
+func excludeNodePools(podSpec *corev1.PodSpec, pools []v1alpha1.NodePool) {
+	terms := excludeNodePoolsTerms(pools)
+	if len(terms) == 0 {
+		return
+	}
+
+	if podSpec.Affinity == nil {
+		podSpec.Affinity = &corev1.Affinity{}
+	}
+	if podSpec.Affinity.NodeAffinity == nil {
+		podSpec.Affinity.NodeAffinity = &corev1.NodeAffinity{}
+	}
+	podSpec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution = &corev1.NodeSelector{
+		NodeSelectorTerms: terms,
+	}
+}
+
+// configureNodePoolDs configures ds, the bpfman DaemonSet of pool, which has
+// already been configured by configureBpfmanDs.
+func configureNodePoolDs(ds *appsv1.DaemonSet, config *v1alpha1.Config, pool *v1alpha1.NodePool) {
+	daemon := nodePoolDaemon(config, pool)
+
+	ds.Name = nodePoolDsName(pool)
+	if ds.Labels == nil {
+		ds.Labels = map[string]string{}
+	}
+	ds.Labels[internal.NodePoolLabel] = pool.Name
+	if ds.Spec.Selector == nil {
+		ds.Spec.Selector = &metav1.LabelSelector{}
+	}
+	if ds.Spec.Selector.MatchLabels == nil {
+		ds.Spec.Selector.MatchLabels = map[string]string{}
+	}
+	ds.Spec.Selector.MatchLabels[internal.NodePoolLabel] = pool.Name
+	if ds.Spec.Template.Labels == nil {
+		ds.Spec.Template.Labels = map[string]string{}
+	}
+	ds.Spec.Template.Labels[internal.NodePoolLabel] = pool.Name
+
+	podSpec := &ds.Spec.Template.Spec
+	configurePodScheduling(podSpec, pool.Tolerations, pool.NodeSelector, "")
+	setContainerResources(podSpec, internal.BpfmanContainerName, pool.Resources)
+
+	// The configuration is validated before the DaemonSet is reconciled,
+	// fall back to the configuration of the Config otherwise.
+	bpfmanTOML, err := renderNodePoolTOML(config, pool)
+	if err != nil {
+		bpfmanTOML = ds.Spec.Template.Annotations[fmt.Sprintf("%s.%s", internal.APIPrefix, internal.BpfmanTOML)]
+	}
+	ds.Spec.Template.Annotations[fmt.Sprintf("%s.%s", internal.APIPrefix, internal.BpfmanTOML)] = bpfmanTOML
+	ds.Spec.Template.Annotations[fmt.Sprintf("%s.%s", internal.APIPrefix, internal.BpfmanLogLevel)] =
+		bpfmanLogFilter(daemon)
+
+	for i := range podSpec.Volumes {
+		if cm := podSpec.Volumes[i].ConfigMap; cm != nil && cm.Name == internal.BpfmanCmName {
+			cm.Name = nodePoolCmName(pool)
+		}
+	}
+	for i := range podSpec.Containers {
+		container := &podSpec.Containers[i]
+		for j := range container.Env {
+			if from := container.Env[j].ValueFrom; from != nil && from.ConfigMapKeyRef != nil &&
+				from.ConfigMapKeyRef.Name == internal.BpfmanCmName {
+				from.ConfigMapKeyRef.Name = nodePoolCmName(pool)
+			}
+		}
+		if container.Name != internal.BpfmanContainerName {
+			continue
+		}
+		container.Image = daemon.Image
+	}
+}
+
+// reconcileNodePools creates or updates the ConfigMap and DaemonSet of each
+// node pool, and deletes those of the pools that were removed.
+func (r *BpfmanConfigReconciler) reconcileNodePools(ctx context.Context, bpfmanConfig *v1alpha1.Config) error {
+	for i := range bpfmanConfig.Spec.NodePools {
+		pool := &bpfmanConfig.Spec.NodePools[i]
+
+		bpfmanTOML, err := renderNodePoolTOML(bpfmanConfig, pool)
+		if err != nil {
+			return err
+		}
+		cm := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      nodePoolCmName(pool),
+				Namespace: bpfmanConfig.Spec.Namespace,
+				Labels:    map[string]string{internal.NodePoolLabel: pool.Name},
+			},
+			Data: map[string]string{
+				internal.BpfmanTOML:          bpfmanTOML,
+				internal.BpfmanAgentLogLevel: bpfmanConfig.Spec.Agent.LogLevel,
+				internal.BpfmanLogLevel:      bpfmanLogFilter(nodePoolDaemon(bpfmanConfig, pool)),
+			},
+		}
+		err = assureResource(ctx, r, bpfmanConfig, cm, func(existing, desired *corev1.ConfigMap) bool {
+			return !equality.Semantic.DeepEqual(existing.Data, desired.Data) ||
+				!equality.Semantic.DeepEqual(existing.Labels, desired.Labels)
+		})
+		if err != nil {
+			return fmt.Errorf("node pool %s: %w", pool.Name, err)
+		}
+
+		ds := &appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: internal.BpfmanDsName}}
+		ds, err = load(ds, r.BpfmanStandardDS, ds.Name)
+		if err != nil {
+			return err
+		}
+		configureBpfmanDs(ds, bpfmanConfig)
+		configureNodePoolDs(ds, bpfmanConfig, pool)
+		err = assureResource(ctx, r, bpfmanConfig, ds, func(existing, desired *appsv1.DaemonSet) bool {
+			copyImagePullPolicy(&existing.Spec.Template.Spec, &desired.Spec.Template.Spec)
+			return !equality.Semantic.DeepEqual(existing.Spec, desired.Spec)
+		})
+		if err != nil {
+			return fmt.Errorf("node pool %s: %w", pool.Name, err)
+		}
+	}
+
+	return r.deleteStaleNodePools(ctx, bpfmanConfig)
+}
+
+// deleteStaleNodePools deletes the DaemonSets and ConfigMaps of the node
+// pools that are no longer in bpfmanConfig.
+func (r *BpfmanConfigReconciler) deleteStaleNodePools(ctx context.Context, bpfmanConfig *v1alpha1.Config) error {
+	inSpec := func(obj client.Object) bool {
+		return slices.ContainsFunc(bpfmanConfig.Spec.NodePools, func(pool v1alpha1.NodePool) bool {
+			return pool.Name == obj.GetLabels()[internal.NodePoolLabel]
+		})
+	}
+
+	var stale []client.Object
+	dsList := &appsv1.DaemonSetList{}
+	if err := r.List(ctx, dsList, client.InNamespace(bpfmanConfig.Spec.Namespace),
+		client.HasLabels{internal.NodePoolLabel}); err != nil {
+		return fmt.Errorf("list node pool DaemonSets: %w", err)
+	}
+	for i := range dsList.Items {
+		stale = append(stale, &dsList.Items[i])
+	}
+	cmList := &corev1.ConfigMapList{}
+	if err := r.List(ctx, cmList, client.InNamespace(bpfmanConfig.Spec.Namespace),
+		client.HasLabels{internal.NodePoolLabel}); err != nil {
+		return fmt.Errorf("list node pool ConfigMaps: %w", err)
+	}
+	for i := range cmList.Items {
+		stale = append(stale, &cmList.Items[i])
+	}
+
+	for _, obj := range stale {
+		if inSpec(obj) || !metav1.IsControlledBy(obj, bpfmanConfig) {
+			continue
+		}
+		r.Logger.Info("Deleting object of removed node pool",
+			"namespace", obj.GetNamespace(), "name", obj.GetName(), "pool", obj.GetLabels()[internal.NodePoolLabel])
+		if err := r.Delete(ctx, obj); err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("delete %s: %w", obj.GetName(), err)
+		}
+	}
+	return nil
+}
+
+// nodePoolPredicate only processes events for the objects of node pools.
+func nodePoolPredicate() predicate.Predicate {
+	return predicate.NewPredicateFuncs(func(obj client.Object) bool {
+		_, ok := obj.GetLabels()[internal.NodePoolLabel]
+		return ok
+	})
+}
@@ -0,0 +1,161 @@
+/*
+Copyright 2025 The bpfman Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bpfmanoperator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/bpfman/bpfman-operator/apis/v1alpha1"
+	"github.com/bpfman/bpfman-operator/internal"
+)
+
+func TestValidateNodePools(t *testing.T) {
+	config := &v1alpha1.Config{Spec: v1alpha1.ConfigSpec{
+		Configuration: internal.DefaultConfiguration,
+		NodePools: []v1alpha1.NodePool{
+			{Name: "gpu", NodeSelector: map[string]string{"pool": "gpu"}},
+			{Name: "edge", NodeSelector: map[string]string{"pool": "edge", "zone": "a"}},
+		},
+	}}
+	require.NoError(t, validateNodePools(config))
+
+	// Pools that don't set a common label to different values may overlap.
+	config.Spec.NodePools[1].NodeSelector = map[string]string{"zone": "a"}
+	err := validateNodePools(config)
+	require.ErrorContains(t, err, "node pools gpu and edge may select the same nodes")
+
+	// The configuration of each pool is validated.
+	config.Spec.NodePools[1].NodeSelector = map[string]string{"pool": "edge"}
+	config.Spec.NodePools[1].Configuration = "[database]\nmax_retries = -1\n"
+	err = validateNodePools(config)
+	require.ErrorContains(t, err, "node pool edge: database.max_retries: must not be negative")
+}
+
+func TestExcludeNodePoolsTerms(t *testing.T) {
+	terms := excludeNodePoolsTerms([]v1alpha1.NodePool{
+		{Name: "gpu", NodeSelector: map[string]string{"pool": "gpu"}},
+		{Name: "edge", NodeSelector: map[string]string{"pool": "edge", "zone": "a"}},
+	})
+
+	notIn := func(key, value string) corev1.NodeSelectorRequirement {
+		return corev1.NodeSelectorRequirement{Key: key, Operator: corev1.NodeSelectorOpNotIn, Values: []string{value}}
+	}
+	require.Equal(t, []corev1.NodeSelectorTerm{
+		{MatchExpressions: []corev1.NodeSelectorRequirement{notIn("pool", "gpu"), notIn("pool", "edge")}},
+		{MatchExpressions: []corev1.NodeSelectorRequirement{notIn("pool", "gpu"), notIn("zone", "a")}},
+	}, terms)
+	require.Empty(t, excludeNodePoolsTerms(nil))
+}
+
+func TestConfigNodePools(t *testing.T) {
+	r, _, req, ctx, cl := setupTestEnvironment(false, false)
+
+	config := getConfig(t, ctx, cl)
+	config.Spec.Configuration = internal.DefaultConfiguration
+	config.Spec.NodePools = []v1alpha1.NodePool{
+		{
+			Name:          "gpu",
+			NodeSelector:  map[string]string{"pool": "gpu"},
+			Image:         "quay.io/bpfman/bpfman:gpu",
+			LogLevel:      "debug",
+			Configuration: "[database]\nmax_retries = 5\n",
+			Resources: &corev1.ResourceRequirements{
+				Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("512Mi")},
+			},
+			Tolerations: []corev1.Toleration{{Key: "nvidia.com/gpu", Operator: corev1.TolerationOpExists}},
+		},
+		{Name: "edge", NodeSelector: map[string]string{"pool": "edge"}},
+	}
+	require.NoError(t, cl.Update(ctx, config))
+
+	// The first reconcile adds the finalizer.
+	_, err := r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	_, err = r.Reconcile(ctx, req)
+	require.NoError(t, err)
+
+	// The default DaemonSet stays off the nodes of the pools.
+	ds := &appsv1.DaemonSet{}
+	require.NoError(t, cl.Get(ctx, types.NamespacedName{Namespace: config.Spec.Namespace, Name: internal.BpfmanDsName}, ds))
+	require.Equal(t, excludeNodePoolsTerms(config.Spec.NodePools),
+		ds.Spec.Template.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms)
+
+	// The pool DaemonSet runs the pool settings on the pool nodes.
+	ds = &appsv1.DaemonSet{}
+	require.NoError(t, cl.Get(ctx, types.NamespacedName{Namespace: config.Spec.Namespace, Name: "bpfman-daemon-gpu"}, ds))
+	podSpec := ds.Spec.Template.Spec
+	require.Equal(t, "gpu", ds.Spec.Selector.MatchLabels[internal.NodePoolLabel])
+	require.Equal(t, "gpu", ds.Spec.Template.Labels[internal.NodePoolLabel])
+	require.Equal(t, map[string]string{"pool": "gpu"}, podSpec.NodeSelector)
+	require.Nil(t, podSpec.Affinity)
+	require.Contains(t, podSpec.Tolerations, config.Spec.NodePools[0].Tolerations[0])
+	for _, volume := range podSpec.Volumes {
+		if volume.ConfigMap != nil {
+			require.Equal(t, "bpfman-config-gpu", volume.ConfigMap.Name)
+		}
+	}
+	for _, container := range podSpec.Containers {
+		if container.Name != internal.BpfmanContainerName {
+			continue
+		}
+		require.Equal(t, "quay.io/bpfman/bpfman:gpu", container.Image)
+		require.Equal(t, *config.Spec.NodePools[0].Resources, container.Resources)
+		for _, env := range container.Env {
+			if env.ValueFrom != nil && env.ValueFrom.ConfigMapKeyRef != nil {
+				require.Equal(t, "bpfman-config-gpu", env.ValueFrom.ConfigMapKeyRef.Name)
+			}
+		}
+	}
+
+	cm := &corev1.ConfigMap{}
+	require.NoError(t, cl.Get(ctx, types.NamespacedName{Namespace: config.Spec.Namespace, Name: "bpfman-config-gpu"}, cm))
+	require.Contains(t, cm.Data[internal.BpfmanTOML], "max_retries = 5\nmillisec_delay = 10000\n")
+	require.Equal(t, "debug", cm.Data[internal.BpfmanLogLevel])
+
+	// The DaemonSet and ConfigMap of a removed pool are deleted.
+	config = getConfig(t, ctx, cl)
+	config.Spec.NodePools = config.Spec.NodePools[1:]
+	require.NoError(t, cl.Update(ctx, config))
+	_, err = r.Reconcile(ctx, req)
+	require.NoError(t, err)
+
+	err = cl.Get(ctx, types.NamespacedName{Namespace: config.Spec.Namespace, Name: "bpfman-daemon-gpu"}, &appsv1.DaemonSet{})
+	require.True(t, errors.IsNotFound(err))
+	err = cl.Get(ctx, types.NamespacedName{Namespace: config.Spec.Namespace, Name: "bpfman-config-gpu"}, &corev1.ConfigMap{})
+	require.True(t, errors.IsNotFound(err))
+	require.NoError(t, cl.Get(ctx, types.NamespacedName{Namespace: config.Spec.Namespace, Name: "bpfman-daemon-edge"},
+		&appsv1.DaemonSet{}))
+
+	// Overlapping pools are rejected.
+	config = getConfig(t, ctx, cl)
+	config.Spec.NodePools = append(config.Spec.NodePools,
+		v1alpha1.NodePool{Name: "zone-a", NodeSelector: map[string]string{"zone": "a"}})
+	require.NoError(t, cl.Update(ctx, config))
+	_, err = r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	config = getConfig(t, ctx, cl)
+	degraded := requireConfigCondition(t, config, v1alpha1.ConfigConditionDegraded, metav1.ConditionTrue)
+	require.Equal(t, configReasonInvalidConfig, degraded.Reason)
+}
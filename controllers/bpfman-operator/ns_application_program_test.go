@@ -134,6 +134,7 @@ func appNsProgramReconcile(t *testing.T, multiCondition bool) {
 	s.AddKnownTypes(bpfmaniov1alpha1.SchemeGroupVersion, App)
 	s.AddKnownTypes(bpfmaniov1alpha1.SchemeGroupVersion, &bpfmaniov1alpha1.BpfApplicationState{})
 	s.AddKnownTypes(bpfmaniov1alpha1.SchemeGroupVersion, &bpfmaniov1alpha1.BpfApplicationStateList{})
+	s.AddKnownTypes(bpfmaniov1alpha1.SchemeGroupVersion, &bpfmaniov1alpha1.BpfPriorityClassList{})
 
 	// Create a fake client to mock API calls.
 	cl := fake.NewClientBuilder().WithStatusSubresource(App).WithRuntimeObjects(objs...).Build()
@@ -221,3 +222,178 @@ func TestAppNsProgramReconcile(t *testing.T) {
 func TestAppNsUpdateStatus(t *testing.T) {
 	appNsProgramReconcile(t, true)
 }
+
+func TestAppNsPriorityClassRejected(t *testing.T) {
+	var (
+		ctx       = context.TODO()
+		namespace = "team-a"
+		fakeNode  = testutils.NewNode("fake-control-plane")
+	)
+
+	class := &bpfmaniov1alpha1.BpfPriorityClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "platform-firewall"},
+		Spec: bpfmaniov1alpha1.BpfPriorityClassSpec{
+			MinPriority: 0,
+			MaxPriority: 99,
+		},
+	}
+	app := &bpfmaniov1alpha1.BpfApplication{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "fakeAppProgram",
+			Namespace:  namespace,
+			Finalizers: []string{internal.BpfmanOperatorFinalizer},
+		},
+		Spec: bpfmaniov1alpha1.BpfApplicationSpec{
+			Programs: []bpfmaniov1alpha1.BpfApplicationProgram{{
+				Name: "xdp-test",
+				Type: bpfmaniov1alpha1.ProgTypeXDP,
+				XDP: &bpfmaniov1alpha1.XdpProgramInfo{
+					Links: []bpfmaniov1alpha1.XdpAttachInfo{{
+						InterfaceSelector: bpfmaniov1alpha1.InterfaceSelector{Interfaces: []string{"eth0"}},
+						PriorityClassName: class.Name,
+					}},
+				},
+			}},
+		},
+	}
+
+	s := scheme.Scheme
+	s.AddKnownTypes(bpfmaniov1alpha1.SchemeGroupVersion,
+		&bpfmaniov1alpha1.BpfApplication{},
+		&bpfmaniov1alpha1.BpfApplicationList{},
+		&bpfmaniov1alpha1.BpfApplicationState{},
+		&bpfmaniov1alpha1.BpfApplicationStateList{},
+		&bpfmaniov1alpha1.BpfPriorityClass{},
+		&bpfmaniov1alpha1.BpfPriorityClassList{},
+	)
+	cl := fake.NewClientBuilder().WithScheme(s).WithStatusSubresource(app).
+		WithRuntimeObjects(fakeNode, app, class).Build()
+	r := &BpfNsApplicationReconciler{
+		NamespaceApplicationReconciler: NamespaceApplicationReconciler{
+			ReconcilerCommon: ReconcilerCommon[bpfmaniov1alpha1.BpfApplicationState, bpfmaniov1alpha1.BpfApplicationStateList]{
+				Client: cl,
+				Scheme: s,
+			},
+		},
+	}
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: app.Name, Namespace: namespace}}
+
+	// The namespace is not allowed to use the class.
+	_, err := r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	require.NoError(t, cl.Get(ctx, req.NamespacedName, app))
+	require.Len(t, app.Status.Conditions, 1)
+	require.Equal(t, string(bpfmaniov1alpha1.BpfAppCondPriorityClassRejected), app.Status.Conditions[0].Type)
+	require.Equal(t, "program xdp-test: namespace team-a is not allowed to use BpfPriorityClass platform-firewall",
+		app.Status.Conditions[0].Message)
+
+	// Changing the class enqueues the application.
+	require.Equal(t, []reconcile.Request{req}, r.priorityClassApplications(ctx, class))
+
+	// Once allowed, the application is reconciled as usual.
+	class.Spec.AllowedNamespaces = []string{namespace}
+	require.NoError(t, cl.Update(ctx, class))
+	_, err = r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	require.NoError(t, cl.Get(ctx, req.NamespacedName, app))
+	require.Len(t, app.Status.Conditions, 1)
+	require.Equal(t, string(bpfmaniov1alpha1.BpfAppCondPending), app.Status.Conditions[0].Type)
+}
+
+func TestAppNsRawPriorityRejected(t *testing.T) {
+	var (
+		ctx       = context.TODO()
+		namespace = "team-a"
+		fakeNode  = testutils.NewNode("fake-control-plane")
+	)
+
+	class := &bpfmaniov1alpha1.BpfPriorityClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "platform-firewall"},
+		Spec: bpfmaniov1alpha1.BpfPriorityClassSpec{
+			MinPriority: 0,
+			MaxPriority: 99,
+		},
+	}
+	app := &bpfmaniov1alpha1.BpfApplication{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "fakeAppProgram",
+			Namespace:  namespace,
+			Finalizers: []string{internal.BpfmanOperatorFinalizer},
+		},
+		Spec: bpfmaniov1alpha1.BpfApplicationSpec{
+			Programs: []bpfmaniov1alpha1.BpfApplicationProgram{{
+				Name: "tcx-test",
+				Type: bpfmaniov1alpha1.ProgTypeTCX,
+				TCX: &bpfmaniov1alpha1.TcxProgramInfo{
+					Links: []bpfmaniov1alpha1.TcxAttachInfo{{
+						InterfaceSelector: bpfmaniov1alpha1.InterfaceSelector{Interfaces: []string{"eth0"}},
+						Direction:         bpfmaniov1alpha1.TCIngress,
+						Priority:          ptr.To(int32(5)),
+					}},
+				},
+			}},
+		},
+	}
+
+	s := scheme.Scheme
+	s.AddKnownTypes(bpfmaniov1alpha1.SchemeGroupVersion,
+		&bpfmaniov1alpha1.BpfApplication{},
+		&bpfmaniov1alpha1.BpfApplicationList{},
+		&bpfmaniov1alpha1.BpfApplicationState{},
+		&bpfmaniov1alpha1.BpfApplicationStateList{},
+		&bpfmaniov1alpha1.BpfPriorityClass{},
+		&bpfmaniov1alpha1.BpfPriorityClassList{},
+	)
+	cl := fake.NewClientBuilder().WithScheme(s).WithStatusSubresource(app).
+		WithRuntimeObjects(fakeNode, app, class).Build()
+	r := &BpfNsApplicationReconciler{
+		NamespaceApplicationReconciler: NamespaceApplicationReconciler{
+			ReconcilerCommon: ReconcilerCommon[bpfmaniov1alpha1.BpfApplicationState, bpfmaniov1alpha1.BpfApplicationStateList]{
+				Client: cl,
+				Scheme: s,
+			},
+		},
+	}
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: app.Name, Namespace: namespace}}
+
+	// The raw priority is reserved by a class the namespace can't use.
+	_, err := r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	require.NoError(t, cl.Get(ctx, req.NamespacedName, app))
+	require.Len(t, app.Status.Conditions, 1)
+	require.Equal(t, string(bpfmaniov1alpha1.BpfAppCondPriorityClassRejected), app.Status.Conditions[0].Type)
+	require.Equal(t, "program tcx-test: priority 5 is reserved by BpfPriorityClass platform-firewall, "+
+		"which namespace team-a is not allowed to use", app.Status.Conditions[0].Message)
+
+	// Changing any class enqueues the application.
+	require.Equal(t, []reconcile.Request{req}, r.priorityClassApplications(ctx, class))
+
+	// A priority outside of the range of the class is accepted.
+	app.Spec.Programs[0].TCX.Links[0].Priority = ptr.To(int32(100))
+	require.NoError(t, cl.Update(ctx, app))
+	_, err = r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	require.NoError(t, cl.Get(ctx, req.NamespacedName, app))
+	require.Len(t, app.Status.Conditions, 1)
+	require.Equal(t, string(bpfmaniov1alpha1.BpfAppCondPending), app.Status.Conditions[0].Type)
+
+	// The default priority of a link without one is checked too.
+	defaultClass := &bpfmaniov1alpha1.BpfPriorityClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "platform-default"},
+		Spec: bpfmaniov1alpha1.BpfPriorityClassSpec{
+			MinPriority: 900,
+			MaxPriority: 1000,
+		},
+	}
+	require.NoError(t, cl.Create(ctx, defaultClass))
+	app.Spec.Programs[0].TCX.Links[0].Priority = nil
+	require.NoError(t, cl.Update(ctx, app))
+	require.Equal(t, []reconcile.Request{req}, r.priorityClassApplications(ctx, defaultClass))
+	_, err = r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	require.NoError(t, cl.Get(ctx, req.NamespacedName, app))
+	require.Len(t, app.Status.Conditions, 1)
+	require.Equal(t, string(bpfmaniov1alpha1.BpfAppCondPriorityClassRejected), app.Status.Conditions[0].Type)
+	require.Equal(t, "program tcx-test: default priority 1000 is reserved by BpfPriorityClass platform-default, "+
+		"which namespace team-a is not allowed to use", app.Status.Conditions[0].Message)
+}
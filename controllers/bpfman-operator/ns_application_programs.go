@@ -26,10 +26,12 @@ import (
 
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 
 	bpfmaniov1alpha1 "github.com/bpfman/bpfman-operator/apis/v1alpha1"
 	internal "github.com/bpfman/bpfman-operator/internal"
+	bpfmanHelpers "github.com/bpfman/bpfman-operator/pkg/helpers"
 )
 
 //+kubebuilder:rbac:groups=bpfman.io,resources=bpfapplications,verbs=get;list;watch;create;update;patch;delete
@@ -38,6 +40,7 @@ import (
 //+kubebuilder:rbac:groups=bpfman.io,namespace=bpfman,resources=bpfapplications,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=bpfman.io,namespace=bpfman,resources=bpfapplications/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=bpfman.io,namespace=bpfman,resources=bpfapplications/finalizers,verbs=update
+//+kubebuilder:rbac:groups=bpfman.io,resources=bpfpriorityclasses,verbs=get;list;watch
 
 // BpfNsApplicationReconciler reconciles a BpfNsApplication object
 type BpfNsApplicationReconciler struct {
@@ -64,6 +67,12 @@ func (r *BpfNsApplicationReconciler) SetupWithManager(mgr ctrl.Manager) error {
 			&handler.EnqueueRequestForObject{},
 			builder.WithPredicates(statusChangedPredicateNamespace()),
 		).
+		// Watch BpfPriorityClasses since they determine whether the
+		// BpfApplications that reference them are allowed.
+		Watches(
+			&bpfmaniov1alpha1.BpfPriorityClass{},
+			handler.EnqueueRequestsFromMapFunc(r.priorityClassApplications),
+		).
 		Complete(r)
 }
 
@@ -112,9 +121,111 @@ func (r *BpfNsApplicationReconciler) Reconcile(ctx context.Context, req ctrl.Req
 		}
 	}
 
+	if bpfApp.DeletionTimestamp.IsZero() {
+		rejected, err := r.checkPriorityClasses(ctx, bpfApp)
+		if err != nil {
+			r.Logger.Error(err, "failed checking BpfPriorityClasses",
+				"Namespace", bpfApp.Namespace, "Name", bpfApp.Name)
+			return ctrl.Result{RequeueAfter: retryDurationOperator}, nil
+		}
+		if rejected != "" {
+			r.Logger.Info("BpfApplication rejected", "Namespace", bpfApp.Namespace, "Name", bpfApp.Name,
+				"Reason", rejected)
+			return r.updateStatus(ctx, bpfApp.Namespace, bpfApp.Name,
+				bpfmaniov1alpha1.BpfAppCondPriorityClassRejected, rejected)
+		}
+	}
+
 	return reconcileBpfApplication(ctx, r, bpfApp)
 }
 
+// checkPriorityClasses returns why the links of app can't use the
+// BpfPriorityClasses they reference, or the raw priorities they choose, or an
+// empty string if they can.
+func (r *BpfNsApplicationReconciler) checkPriorityClasses(ctx context.Context,
+	app *bpfmaniov1alpha1.BpfApplication) (string, error) {
+	var classes *bpfmaniov1alpha1.BpfPriorityClassList
+	for _, link := range priorityLinks(app) {
+		if link.className == "" {
+			if classes == nil {
+				classes = &bpfmaniov1alpha1.BpfPriorityClassList{}
+				if err := r.List(ctx, classes); err != nil {
+					return "", err
+				}
+			}
+			if err := bpfmanHelpers.CheckRawPriority(classes.Items, app.Namespace, link.priority); err != nil {
+				return fmt.Sprintf("program %s: %v", link.program, err), nil
+			}
+			continue
+		}
+		class := &bpfmaniov1alpha1.BpfPriorityClass{}
+		if err := r.Get(ctx, types.NamespacedName{Name: link.className}, class); err != nil {
+			if errors.IsNotFound(err) {
+				return fmt.Sprintf("program %s: BpfPriorityClass %s not found", link.program, link.className), nil
+			}
+			return "", err
+		}
+		if _, err := bpfmanHelpers.GetClassPriority(class, app.Namespace, link.priority); err != nil {
+			return fmt.Sprintf("program %s: %v", link.program, err), nil
+		}
+	}
+	return "", nil
+}
+
+// priorityClassApplications returns the requests for the BpfApplications that
+// reference the BpfPriorityClass obj, or with links that don't use a class,
+// whose raw or default priority may fall in the range of obj.
+func (r *BpfNsApplicationReconciler) priorityClassApplications(ctx context.Context, obj client.Object) []ctrl.Request {
+	apps := &bpfmaniov1alpha1.BpfApplicationList{}
+	if err := r.List(ctx, apps); err != nil {
+		r.Logger.Error(err, "failed listing BpfApplications", "BpfPriorityClass", obj.GetName())
+		return nil
+	}
+	var requests []ctrl.Request
+	for i := range apps.Items {
+		for _, link := range priorityLinks(&apps.Items[i]) {
+			if link.className == obj.GetName() || link.className == "" {
+				requests = append(requests, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(&apps.Items[i])})
+				break
+			}
+		}
+	}
+	return requests
+}
+
+type priorityClassLink struct {
+	program   string
+	className string
+	priority  *int32
+}
+
+// priorityLinks returns the priority settings of the XDP, TC and TCX links of
+// app.
+func priorityLinks(app *bpfmaniov1alpha1.BpfApplication) []priorityClassLink {
+	var links []priorityClassLink
+	add := func(program, className string, priority *int32) {
+		links = append(links, priorityClassLink{program: program, className: className, priority: priority})
+	}
+	for _, program := range app.Spec.Programs {
+		if program.XDP != nil {
+			for _, link := range program.XDP.Links {
+				add(program.Name, link.PriorityClassName, link.Priority)
+			}
+		}
+		if program.TC != nil {
+			for _, link := range program.TC.Links {
+				add(program.Name, link.PriorityClassName, link.Priority)
+			}
+		}
+		if program.TCX != nil {
+			for _, link := range program.TCX.Links {
+				add(program.Name, link.PriorityClassName, link.Priority)
+			}
+		}
+	}
+	return links
+}
+
 //lint:ignore U1000 Linter claims function unused, but generics confusing linter
 func (r *BpfNsApplicationReconciler) updateStatus(
 	ctx context.Context,
@@ -0,0 +1,127 @@
+/*
+Copyright 2025 The bpfman Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bpfmanoperator
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	"github.com/bpfman/bpfman-operator/apis/v1alpha1"
+)
+
+// overrideName identifies override in errors.
+func overrideName(override *v1alpha1.ComponentOverride) string {
+	if override.Namespace == "" {
+		return fmt.Sprintf("override %s %s", override.Kind, override.Name)
+	}
+	return fmt.Sprintf("override %s %s/%s", override.Kind, override.Namespace, override.Name)
+}
+
+// decodeOverridePatch returns the patch of override as JSON.
+func decodeOverridePatch(override *v1alpha1.ComponentOverride) ([]byte, error) {
+	patch, err := yaml.YAMLToJSON([]byte(override.Patch.Patch))
+	if err != nil {
+		return nil, fmt.Errorf("decode patch: %w", err)
+	}
+
+	switch override.Patch.Type {
+	case v1alpha1.ComponentPatchJSON:
+		if _, err := jsonpatch.DecodePatch(patch); err != nil {
+			return nil, fmt.Errorf("decode JSON patch: %w", err)
+		}
+	case v1alpha1.ComponentPatchStrategicMerge:
+		var fields map[string]any
+		if err := json.Unmarshal(patch, &fields); err != nil {
+			return nil, fmt.Errorf("decode strategic merge patch: must be an object: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("unknown patch type %q", override.Patch.Type)
+	}
+	return patch, nil
+}
+
+// validateOverridePatches checks that the patches of overrides can be
+// decoded, so that a malformed patch is reported before any component is
+// reconciled.
+func validateOverridePatches(overrides []v1alpha1.ComponentOverride) error {
+	for i := range overrides {
+		if overrides[i].Patch == nil {
+			continue
+		}
+		if _, err := decodeOverridePatch(&overrides[i]); err != nil {
+			return &invalidConfigurationError{err: fmt.Errorf("%s: %w", overrideName(&overrides[i]), err)}
+		}
+	}
+	return nil
+}
+
+// applyOverridePatch applies the patch of override to resource in place. The
+// patch can't change the name or namespace of resource.
+func applyOverridePatch(resource client.Object, override *v1alpha1.ComponentOverride) error {
+	patched, err := patchObject(resource, override)
+	if err != nil {
+		return &invalidConfigurationError{err: fmt.Errorf("%s: %w", overrideName(override), err)}
+	}
+
+	name, namespace := resource.GetName(), resource.GetNamespace()
+	// Reset resource so that the fields removed by the patch are cleared.
+	value := reflect.ValueOf(resource).Elem()
+	value.Set(reflect.Zero(value.Type()))
+	if err := json.Unmarshal(patched, resource); err != nil {
+		return &invalidConfigurationError{err: fmt.Errorf("%s: decode patched object: %w", overrideName(override), err)}
+	}
+	if resource.GetName() != name || resource.GetNamespace() != namespace {
+		return &invalidConfigurationError{err: fmt.Errorf("%s: the patch can't change the name or namespace",
+			overrideName(override))}
+	}
+	return nil
+}
+
+func patchObject(resource client.Object, override *v1alpha1.ComponentOverride) ([]byte, error) {
+	patch, err := decodeOverridePatch(override)
+	if err != nil {
+		return nil, err
+	}
+	original, err := json.Marshal(resource)
+	if err != nil {
+		return nil, fmt.Errorf("encode object: %w", err)
+	}
+
+	if override.Patch.Type == v1alpha1.ComponentPatchJSON {
+		jsonPatch, err := jsonpatch.DecodePatch(patch)
+		if err != nil {
+			return nil, fmt.Errorf("decode JSON patch: %w", err)
+		}
+		patched, err := jsonPatch.Apply(original)
+		if err != nil {
+			return nil, fmt.Errorf("apply JSON patch: %w", err)
+		}
+		return patched, nil
+	}
+
+	patched, err := strategicpatch.StrategicMergePatch(original, patch, resource)
+	if err != nil {
+		return nil, fmt.Errorf("apply strategic merge patch: %w", err)
+	}
+	return patched, nil
+}
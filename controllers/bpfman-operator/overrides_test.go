@@ -0,0 +1,181 @@
+/*
+Copyright 2025 The bpfman Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bpfmanoperator
+
+import (
+	"os"
+	"testing"
+
+	"github.com/google/cel-go/cel"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/yaml"
+
+	"github.com/bpfman/bpfman-operator/apis/v1alpha1"
+	"github.com/bpfman/bpfman-operator/internal"
+)
+
+func TestOverridePatches(t *testing.T) {
+	r, _, req, ctx, cl := setupTestEnvironment(false, false)
+
+	config := getConfig(t, ctx, cl)
+	config.Spec.Overrides = []v1alpha1.ComponentOverride{
+		{
+			APIVersion: "apps/v1",
+			Kind:       "DaemonSet",
+			Namespace:  config.Spec.Namespace,
+			Name:       internal.BpfmanDsName,
+			Patch: &v1alpha1.ComponentPatch{
+				Type: v1alpha1.ComponentPatchStrategicMerge,
+				Patch: `spec:
+  template:
+    spec:
+      containers:
+        - name: bpfman
+          resources:
+            limits:
+              memory: 256Mi
+`,
+			},
+		},
+		{
+			APIVersion: "v1",
+			Kind:       "ConfigMap",
+			Namespace:  config.Spec.Namespace,
+			Name:       internal.BpfmanCmName,
+			Patch: &v1alpha1.ComponentPatch{
+				Type:  v1alpha1.ComponentPatchJSON,
+				Patch: `[{"op": "remove", "path": "/data/bpfman.agent.log.level"}]`,
+			},
+		},
+	}
+	require.NoError(t, cl.Update(ctx, config))
+
+	// The first reconcile adds the finalizer.
+	_, err := r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	_, err = r.Reconcile(ctx, req)
+	require.NoError(t, err)
+
+	// The patches are applied on top of the rendered objects, which are
+	// still managed by the operator.
+	ds := &appsv1.DaemonSet{}
+	require.NoError(t, cl.Get(ctx, types.NamespacedName{Namespace: config.Spec.Namespace, Name: internal.BpfmanDsName}, ds))
+	require.True(t, metav1.IsControlledBy(ds, config))
+	for _, c := range ds.Spec.Template.Spec.Containers {
+		if c.Name == internal.BpfmanContainerName {
+			require.Equal(t, resource.MustParse("256Mi"), c.Resources.Limits[corev1.ResourceMemory])
+			// Fields the patch doesn't set are kept.
+			require.NotEmpty(t, c.VolumeMounts)
+		}
+	}
+	cm := &corev1.ConfigMap{}
+	require.NoError(t, cl.Get(ctx, types.NamespacedName{Namespace: config.Spec.Namespace, Name: internal.BpfmanCmName}, cm))
+	require.NotContains(t, cm.Data, internal.BpfmanAgentLogLevel)
+	require.Contains(t, cm.Data, internal.BpfmanLogLevel)
+
+	// A patch that doesn't apply is reported in the Config status.
+	config = getConfig(t, ctx, cl)
+	config.Spec.Overrides[1].Patch.Patch = `[{"op": "replace", "path": "/data/missing", "value": "x"}]`
+	require.NoError(t, cl.Update(ctx, config))
+	_, err = r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	config = getConfig(t, ctx, cl)
+	degraded := requireConfigCondition(t, config, v1alpha1.ConfigConditionDegraded, metav1.ConditionTrue)
+	require.Equal(t, configReasonInvalidConfig, degraded.Reason)
+	require.Contains(t, degraded.Message, "override ConfigMap bpfman/bpfman-config: apply JSON patch")
+
+	// So is a malformed patch, before any component is reconciled.
+	config.Spec.Overrides[1].Patch.Patch = `{"op": "remove"}`
+	require.NoError(t, cl.Update(ctx, config))
+	_, err = r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	config = getConfig(t, ctx, cl)
+	degraded = requireConfigCondition(t, config, v1alpha1.ConfigConditionDegraded, metav1.ConditionTrue)
+	require.Contains(t, degraded.Message, "override ConfigMap bpfman/bpfman-config: decode JSON patch")
+
+	// The identity of a component can't be patched.
+	config.Spec.Overrides[1].Patch = &v1alpha1.ComponentPatch{
+		Type:  v1alpha1.ComponentPatchStrategicMerge,
+		Patch: `{"metadata": {"name": "other"}}`,
+	}
+	require.NoError(t, cl.Update(ctx, config))
+	_, err = r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	config = getConfig(t, ctx, cl)
+	degraded = requireConfigCondition(t, config, v1alpha1.ConfigConditionDegraded, metav1.ConditionTrue)
+	require.Contains(t, degraded.Message, "the patch can't change the name or namespace")
+}
+
+// TestComponentOverrideValidation evaluates the validation rules of the
+// overrides in the Config CRD the way the API server does.
+func TestComponentOverrideValidation(t *testing.T) {
+	data, err := os.ReadFile("../../config/crd/bases/bpfman.io_configs.yaml")
+	require.NoError(t, err)
+	crd := &apiextensionsv1.CustomResourceDefinition{}
+	require.NoError(t, yaml.Unmarshal(data, crd))
+	overrides := crd.Spec.Versions[0].Schema.OpenAPIV3Schema.Properties["spec"].Properties["overrides"]
+	rules := overrides.Items.Schema.XValidations
+	require.NotEmpty(t, rules)
+
+	env, err := cel.NewEnv(cel.Variable("self", cel.DynType))
+	require.NoError(t, err)
+	validate := func(override string) []string {
+		self := map[string]any{}
+		require.NoError(t, yaml.Unmarshal([]byte(override), &self))
+		var messages []string
+		for _, rule := range rules {
+			ast, issues := env.Compile(rule.Rule)
+			require.NoError(t, issues.Err())
+			prg, err := env.Program(ast)
+			require.NoError(t, err)
+			out, _, err := prg.Eval(map[string]any{"self": self})
+			require.NoError(t, err, rule.Rule)
+			if out.Value() != true {
+				messages = append(messages, rule.Message)
+			}
+		}
+		return messages
+	}
+
+	const component = "{apiVersion: v1, kind: ConfigMap, namespace: bpfman, name: bpfman-config"
+	const patch = "patch: {type: StrategicMerge, patch: '{}'}"
+	tests := []struct {
+		name     string
+		override string
+		want     []string
+	}{
+		{name: "patch only", override: component + ", " + patch + "}"},
+		{name: "managed with patch", override: component + ", unmanaged: false, " + patch + "}"},
+		{name: "unmanaged", override: component + ", unmanaged: true}"},
+		{
+			name:     "unmanaged with patch",
+			override: component + ", unmanaged: true, " + patch + "}",
+			want:     []string{"an unmanaged component can't be patched"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, validate(tt.override))
+		})
+	}
+}
@@ -0,0 +1,495 @@
+/*
+Copyright 2025 The bpfman Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bpfmanoperator
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"slices"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/bpfman/bpfman-operator/apis/v1alpha1"
+	"github.com/bpfman/bpfman-operator/internal"
+)
+
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;delete
+
+// uninstallRequeue is how often the removal of bpfman is checked while it
+// waits for the applications to be deleted or the nodes to be cleaned up.
+const uninstallRequeue = 10 * time.Second
+
+const (
+	configReasonApplicationsExist    = "ApplicationsExist"
+	configReasonDrainingApplications = "DrainingApplications"
+	configReasonCleaningNodes        = "CleaningNodes"
+	configReasonStoppingBpfman       = "StoppingBpfman"
+	configReasonCleanupFailed        = "CleanupFailed"
+)
+
+const (
+	cleanupJobPrefix         = "bpfman-cleanup-"
+	databaseCleanupJobPrefix = "bpfman-db-cleanup-"
+	cleanupJobLabel          = "app.kubernetes.io/name"
+	cleanupJobLabelValue     = "bpfman-cleanup"
+	// cleanupPhaseLabel tells the Jobs that unload the programs of a node
+	// from those that remove its database once bpfman is stopped.
+	cleanupPhaseLabel      = "bpfman.io/cleanup-phase"
+	cleanupPhasePrograms   = "programs"
+	cleanupPhaseDatabase   = "database"
+	cleanupJobBackoffLimit = 3
+	// maxListedApplications is how many remaining applications are named
+	// in the Uninstalling condition.
+	maxListedApplications = 5
+)
+
+// cleanupNodesEnabled returns whether the nodes are cleaned up when config is
+// deleted.
+func cleanupNodesEnabled(config *v1alpha1.Config) bool {
+	return config.Spec.Uninstall.CleanupNodes == nil || *config.Spec.Uninstall.CleanupNodes
+}
+
+// handleDeletion removes bpfman from the nodes before letting Kubernetes
+// garbage collect the resources owned by the Config. Depending on the
+// uninstall policy, the applications are deleted or the deletion waits
+// until they are. Then a cleanup Job unloads the programs on every node
+// running bpfman, the bpfman DaemonSets are deleted, and once their pods are
+// gone another Job removes the bpfman database of each node, before the
+// finalizer is removed. The progress is reported by the Uninstalling
+// condition.
+func (r *BpfmanConfigReconciler) handleDeletion(ctx context.Context, config *v1alpha1.Config) (ctrl.Result, error) {
+	r.Logger.Info("Config deletion requested, removing bpfman from the nodes", "name", config.Name)
+
+	remaining, err := r.drainApplications(ctx, config)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if len(remaining) > 0 {
+		reason := configReasonApplicationsExist
+		message := "Delete the remaining applications, or set spec.uninstall.applicationPolicy to Drain: "
+		if config.Spec.Uninstall.ApplicationPolicy == v1alpha1.UninstallDrain {
+			reason = configReasonDrainingApplications
+			message = "Waiting for the applications to be deleted: "
+		}
+		if err := r.setUninstallingCondition(ctx, config, reason, message+listApplications(remaining)); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: uninstallRequeue}, nil
+	}
+
+	if cleanupNodesEnabled(config) {
+		// The programs are unloaded through bpfman, which must still run.
+		pending, failed, err := r.cleanupNodes(ctx, config)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if wait, res, err := r.reportCleanup(ctx, config, pending, failed, "Cleaning up nodes "); wait {
+			return res, err
+		}
+	}
+
+	// Remove bpfman before the resources it depends on are garbage
+	// collected. The DaemonSets are deleted in the foreground, so that
+	// they remain until their pods are gone.
+	stopping, err := r.deleteDaemonSets(ctx, config)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if cleanupNodesEnabled(config) {
+		if len(stopping) > 0 {
+			message := "Waiting for the bpfman pods to stop: " + strings.Join(stopping, ", ")
+			if err := r.setUninstallingCondition(ctx, config, configReasonStoppingBpfman, message); err != nil {
+				return ctrl.Result{}, err
+			}
+			return ctrl.Result{RequeueAfter: uninstallRequeue}, nil
+		}
+
+		pending, failed, err := r.cleanupDatabases(ctx, config)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if wait, res, err := r.reportCleanup(ctx, config, pending, failed,
+			"Removing the bpfman database from nodes "); wait {
+			return res, err
+		}
+	}
+
+	controllerutil.RemoveFinalizer(config, internal.BpfmanConfigFinalizer)
+	if err := r.Update(ctx, config); err != nil {
+		r.Logger.Error(err, "Failed to remove finalizer from Config during deletion", "name", config.Name)
+		return ctrl.Result{}, err
+	}
+
+	r.Logger.Info("Finalizer removed from Config, deletion will proceed", "name", config.Name)
+	return ctrl.Result{}, nil
+}
+
+// reportCleanup reports the cleanup Jobs still running on the pending nodes
+// or failed on the failed ones in the Uninstalling condition, prefixing the
+// pending nodes with message. It returns whether the uninstall waits for
+// them, and the result and error to return if it does.
+func (r *BpfmanConfigReconciler) reportCleanup(ctx context.Context, config *v1alpha1.Config,
+	pending, failed []string, message string) (bool, ctrl.Result, error) {
+	if len(failed) > 0 {
+		// Deleting the failed Jobs retries them, and disabling
+		// spec.uninstall.cleanupNodes skips the cleanup, both of which
+		// trigger a new reconcile.
+		message := fmt.Sprintf("Cleanup failed on nodes %s; delete the failed Jobs to retry, or set "+
+			"spec.uninstall.cleanupNodes to false to skip the cleanup", strings.Join(failed, ", "))
+		return true, ctrl.Result{}, r.setUninstallingCondition(ctx, config, configReasonCleanupFailed, message)
+	}
+	if len(pending) > 0 {
+		err := r.setUninstallingCondition(ctx, config, configReasonCleaningNodes, message+strings.Join(pending, ", "))
+		if err != nil {
+			return true, ctrl.Result{}, err
+		}
+		return true, ctrl.Result{RequeueAfter: uninstallRequeue}, nil
+	}
+	return false, ctrl.Result{}, nil
+}
+
+// deleteDaemonSets deletes the bpfman DaemonSets of config in the
+// foreground. It returns those that still exist.
+func (r *BpfmanConfigReconciler) deleteDaemonSets(ctx context.Context, config *v1alpha1.Config) ([]string, error) {
+	var remaining []string
+	for _, name := range bpfmanDaemonSetNames(config) {
+		ds := &appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Namespace: config.Spec.Namespace, Name: name}}
+		err := r.Delete(ctx, ds, client.PropagationPolicy(metav1.DeletePropagationForeground))
+		if err != nil && !errors.IsNotFound(err) {
+			return nil, fmt.Errorf("delete DaemonSet %s: %w", name, err)
+		}
+		if err := r.Get(ctx, client.ObjectKeyFromObject(ds), ds); err == nil {
+			remaining = append(remaining, name)
+		} else if !errors.IsNotFound(err) {
+			return nil, err
+		}
+	}
+	return remaining, nil
+}
+
+// drainApplications returns the BpfApplications and ClusterBpfApplications
+// that remain, deleting them first if the uninstall policy of config is
+// Drain.
+func (r *BpfmanConfigReconciler) drainApplications(ctx context.Context, config *v1alpha1.Config) ([]string, error) {
+	drain := config.Spec.Uninstall.ApplicationPolicy == v1alpha1.UninstallDrain
+	var remaining []string
+
+	clApps := &v1alpha1.ClusterBpfApplicationList{}
+	if err := r.List(ctx, clApps); err != nil {
+		return nil, fmt.Errorf("list ClusterBpfApplications: %w", err)
+	}
+	for i := range clApps.Items {
+		app := &clApps.Items[i]
+		if drain && app.DeletionTimestamp.IsZero() {
+			r.Logger.Info("Deleting ClusterBpfApplication", "name", app.Name)
+			if err := r.Delete(ctx, app); err != nil && !errors.IsNotFound(err) {
+				return nil, fmt.Errorf("delete ClusterBpfApplication %s: %w", app.Name, err)
+			}
+		}
+		remaining = append(remaining, "ClusterBpfApplication "+app.Name)
+	}
+
+	apps := &v1alpha1.BpfApplicationList{}
+	if err := r.List(ctx, apps); err != nil {
+		return nil, fmt.Errorf("list BpfApplications: %w", err)
+	}
+	for i := range apps.Items {
+		app := &apps.Items[i]
+		if drain && app.DeletionTimestamp.IsZero() {
+			r.Logger.Info("Deleting BpfApplication", "namespace", app.Namespace, "name", app.Name)
+			if err := r.Delete(ctx, app); err != nil && !errors.IsNotFound(err) {
+				return nil, fmt.Errorf("delete BpfApplication %s/%s: %w", app.Namespace, app.Name, err)
+			}
+		}
+		remaining = append(remaining, fmt.Sprintf("BpfApplication %s/%s", app.Namespace, app.Name))
+	}
+	return remaining, nil
+}
+
+func listApplications(apps []string) string {
+	if len(apps) <= maxListedApplications {
+		return strings.Join(apps, ", ")
+	}
+	return fmt.Sprintf("%s and %d more", strings.Join(apps[:maxListedApplications], ", "),
+		len(apps)-maxListedApplications)
+}
+
+// cleanupNodes runs a cleanup Job on every node running a bpfman pod of
+// config. It returns the nodes whose Job is still running and those whose
+// Job failed.
+func (r *BpfmanConfigReconciler) cleanupNodes(ctx context.Context, config *v1alpha1.Config) ([]string, []string, error) {
+	var pending, failed []string
+	for _, name := range bpfmanDaemonSetNames(config) {
+		ds := &appsv1.DaemonSet{}
+		if err := r.Get(ctx, types.NamespacedName{Namespace: config.Spec.Namespace, Name: name}, ds); err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return nil, nil, err
+		}
+		pods, err := r.daemonSetPods(ctx, ds)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for _, pod := range pods {
+			node := pod.Spec.NodeName
+			if node == "" || slices.Contains(pending, node) || slices.Contains(failed, node) ||
+				!r.nodeExists(ctx, node) {
+				continue
+			}
+			job, err := r.assureCleanupJob(ctx, config, cleanupJob(ds, node))
+			if err != nil {
+				return nil, nil, fmt.Errorf("cleanup Job for node %s: %w", node, err)
+			}
+			pending, failed = classifyCleanupJob(job, node, pending, failed)
+		}
+	}
+	slices.Sort(pending)
+	slices.Sort(failed)
+	return pending, failed, nil
+}
+
+// cleanupDatabases runs a Job removing the bpfman database on every node
+// cleaned up by cleanupNodes, once bpfman is stopped. It returns the nodes
+// whose Job is still running and those whose Job failed.
+func (r *BpfmanConfigReconciler) cleanupDatabases(ctx context.Context,
+	config *v1alpha1.Config) ([]string, []string, error) {
+	jobs := &batchv1.JobList{}
+	if err := r.List(ctx, jobs, client.InNamespace(config.Spec.Namespace), client.MatchingLabels{
+		cleanupJobLabel:   cleanupJobLabelValue,
+		cleanupPhaseLabel: cleanupPhasePrograms,
+	}); err != nil {
+		return nil, nil, fmt.Errorf("list cleanup Jobs: %w", err)
+	}
+
+	var pending, failed []string
+	for i := range jobs.Items {
+		node := jobs.Items[i].Spec.Template.Spec.NodeName
+		if node == "" || !r.nodeExists(ctx, node) {
+			continue
+		}
+		job, err := r.assureCleanupJob(ctx, config, databaseCleanupJob(&jobs.Items[i]))
+		if err != nil {
+			return nil, nil, fmt.Errorf("database cleanup Job for node %s: %w", node, err)
+		}
+		pending, failed = classifyCleanupJob(job, node, pending, failed)
+	}
+	slices.Sort(pending)
+	slices.Sort(failed)
+	return pending, failed, nil
+}
+
+// classifyCleanupJob adds node to pending or failed depending on the status
+// of its cleanup Job.
+func classifyCleanupJob(job *batchv1.Job, node string, pending, failed []string) ([]string, []string) {
+	switch {
+	case jobCondition(job, batchv1.JobFailed):
+		failed = append(failed, node)
+	case !jobCondition(job, batchv1.JobComplete) && job.Status.Succeeded == 0:
+		pending = append(pending, node)
+	}
+	return pending, failed
+}
+
+// assureCleanupJob returns the existing Job named like job, creating job if
+// there is none.
+func (r *BpfmanConfigReconciler) assureCleanupJob(ctx context.Context, config *v1alpha1.Config,
+	job *batchv1.Job) (*batchv1.Job, error) {
+	existing := &batchv1.Job{}
+	err := r.Get(ctx, client.ObjectKeyFromObject(job), existing)
+	if err == nil || !errors.IsNotFound(err) {
+		return existing, err
+	}
+
+	if err := ctrl.SetControllerReference(config, job, r.Scheme); err != nil {
+		return nil, err
+	}
+	r.Logger.Info("Creating cleanup Job", "node", job.Spec.Template.Spec.NodeName, "name", job.Name)
+	if err := r.Create(ctx, job); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// cleanupJobName returns the name of the cleanup Job of node with prefix,
+// which is shortened with a hash of the node name when it is too long.
+func cleanupJobName(prefix, node string) string {
+	name := prefix + node
+	if len(name) <= 63 {
+		return name
+	}
+	h := fnv.New32a()
+	h.Write([]byte(node))
+	suffix := fmt.Sprintf("-%08x", h.Sum32())
+	return strings.TrimRight(name[:63-len(suffix)], ".-") + suffix
+}
+
+// cleanupJob returns the Job that unloads the programs bpfman loaded on node
+// and removes the pins it left, while bpfman still runs. It runs the agent
+// image of ds, with the host paths ds mounts for the bpfman socket, runtime
+// directory and bpffs, and for the audit log the unloads are recorded in.
+func cleanupJob(ds *appsv1.DaemonSet, node string) *batchv1.Job {
+	podSpec := &ds.Spec.Template.Spec
+	container := corev1.Container{
+		Name:    "cleanup",
+		Command: []string{"/bpfman-agent", "--cleanup-node"},
+		Env: []corev1.EnvVar{{
+			Name: "KUBE_NODE_NAME",
+			ValueFrom: &corev1.EnvVarSource{
+				FieldRef: &corev1.ObjectFieldSelector{APIVersion: "v1", FieldPath: "spec.nodeName"},
+			},
+		}},
+		SecurityContext: &corev1.SecurityContext{
+			Privileged: ptr.To(true),
+		},
+		TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+	}
+	for _, c := range podSpec.Containers {
+		if c.Name == internal.BpfmanAgentContainerName {
+			container.Image = c.Image
+			container.ImagePullPolicy = c.ImagePullPolicy
+		}
+	}
+
+	var volumes []corev1.Volume
+	for _, mount := range []struct{ volume, path string }{
+		{"runtime", "/run/bpfman"},
+		{"default-bpf-fs", "/sys/fs/bpf"},
+		{"bpfman-sock", "/run/bpfman-sock"},
+		{"bpfman-agent-audit", "/var/log/bpfman-agent"},
+	} {
+		for _, v := range podSpec.Volumes {
+			if v.Name == mount.volume {
+				volumes = append(volumes, v)
+				container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+					Name:             mount.volume,
+					MountPath:        mount.path,
+					MountPropagation: ptr.To(corev1.MountPropagationHostToContainer),
+				})
+			}
+		}
+	}
+
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      cleanupJobName(cleanupJobPrefix, node),
+			Namespace: ds.Namespace,
+			Labels: map[string]string{
+				cleanupJobLabel:   cleanupJobLabelValue,
+				cleanupPhaseLabel: cleanupPhasePrograms,
+			},
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: ptr.To(int32(cleanupJobBackoffLimit)),
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					NodeName:           node,
+					RestartPolicy:      corev1.RestartPolicyNever,
+					ServiceAccountName: podSpec.ServiceAccountName,
+					ImagePullSecrets:   podSpec.ImagePullSecrets,
+					// The Job must run wherever bpfman ran.
+					Tolerations: []corev1.Toleration{{Operator: corev1.TolerationOpExists}},
+					Containers:  []corev1.Container{container},
+					Volumes:     volumes,
+				},
+			},
+		},
+	}
+}
+
+// databaseCleanupJob returns the Job that removes the bpfman database of the
+// node cleaned up by programsJob, once bpfman is stopped. It only mounts the
+// bpfman runtime directory.
+func databaseCleanupJob(programsJob *batchv1.Job) *batchv1.Job {
+	podSpec := programsJob.Spec.Template.Spec.DeepCopy()
+	podSpec.Volumes = slices.DeleteFunc(podSpec.Volumes, func(v corev1.Volume) bool { return v.Name != "runtime" })
+	container := &podSpec.Containers[0]
+	container.Command = []string{"/bpfman-agent", "--cleanup-node-database"}
+	container.VolumeMounts = slices.DeleteFunc(container.VolumeMounts, func(m corev1.VolumeMount) bool {
+		return m.Name != "runtime"
+	})
+
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      cleanupJobName(databaseCleanupJobPrefix, podSpec.NodeName),
+			Namespace: programsJob.Namespace,
+			Labels: map[string]string{
+				cleanupJobLabel:   cleanupJobLabelValue,
+				cleanupPhaseLabel: cleanupPhaseDatabase,
+			},
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: ptr.To(int32(cleanupJobBackoffLimit)),
+			Template:     corev1.PodTemplateSpec{Spec: *podSpec},
+		},
+	}
+}
+
+func jobCondition(job *batchv1.Job, conditionType batchv1.JobConditionType) bool {
+	for _, condition := range job.Status.Conditions {
+		if condition.Type == conditionType && condition.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
+// setUninstallingCondition reports the progress of the removal of bpfman in
+// the Config status.
+func (r *BpfmanConfigReconciler) setUninstallingCondition(ctx context.Context, config *v1alpha1.Config,
+	reason, message string) error {
+	status := config.Status.DeepCopy()
+	meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+		Type:               string(v1alpha1.ConfigConditionUninstalling),
+		Status:             metav1.ConditionTrue,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: config.Generation,
+	})
+	if equality.Semantic.DeepEqual(&config.Status, status) {
+		return nil
+	}
+	config.Status = *status
+	if err := r.Status().Update(ctx, config); err != nil {
+		return fmt.Errorf("update Config status: %w", err)
+	}
+	return nil
+}
+
+// cleanupJobPredicate selects the cleanup Jobs.
+func cleanupJobPredicate() predicate.Predicate {
+	return predicate.NewPredicateFuncs(func(obj client.Object) bool {
+		return obj.GetLabels()[cleanupJobLabel] == cleanupJobLabelValue
+	})
+}
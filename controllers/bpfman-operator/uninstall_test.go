@@ -0,0 +1,177 @@
+/*
+Copyright 2025 The bpfman Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bpfmanoperator
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/bpfman/bpfman-operator/apis/v1alpha1"
+	"github.com/bpfman/bpfman-operator/internal"
+)
+
+func TestConfigUninstall(t *testing.T) {
+	r, _, req, ctx, cl := setupTestEnvironment(false, false)
+
+	// The first reconcile adds the finalizer.
+	_, err := r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	_, err = r.Reconcile(ctx, req)
+	require.NoError(t, err)
+
+	config := getConfig(t, ctx, cl)
+	ds := &appsv1.DaemonSet{}
+	require.NoError(t, cl.Get(ctx, types.NamespacedName{Namespace: config.Spec.Namespace, Name: internal.BpfmanDsName}, ds))
+	require.NoError(t, cl.Create(ctx, &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}}))
+	createDaemonPod(t, ctx, cl, ds, "node-1", "rev1")
+	app := &v1alpha1.ClusterBpfApplication{ObjectMeta: metav1.ObjectMeta{Name: "app"}}
+	require.NoError(t, cl.Create(ctx, app))
+
+	// The deletion is refused while applications exist.
+	require.NoError(t, cl.Delete(ctx, config))
+	res, err := r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	require.Equal(t, uninstallRequeue, res.RequeueAfter)
+	config = getConfig(t, ctx, cl)
+	uninstalling := requireConfigCondition(t, config, v1alpha1.ConfigConditionUninstalling, metav1.ConditionTrue)
+	require.Equal(t, configReasonApplicationsExist, uninstalling.Reason)
+	require.Contains(t, uninstalling.Message, "ClusterBpfApplication app")
+	require.NoError(t, cl.Get(ctx, types.NamespacedName{Name: "app"}, app))
+
+	// With the Drain policy, the applications are deleted.
+	config.Spec.Uninstall.ApplicationPolicy = v1alpha1.UninstallDrain
+	require.NoError(t, cl.Update(ctx, config))
+	_, err = r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	require.True(t, errors.IsNotFound(cl.Get(ctx, types.NamespacedName{Name: "app"}, app)))
+	config = getConfig(t, ctx, cl)
+	uninstalling = requireConfigCondition(t, config, v1alpha1.ConfigConditionUninstalling, metav1.ConditionTrue)
+	require.Equal(t, configReasonDrainingApplications, uninstalling.Reason)
+
+	// Then a cleanup Job runs on every node running bpfman.
+	_, err = r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	config = getConfig(t, ctx, cl)
+	uninstalling = requireConfigCondition(t, config, v1alpha1.ConfigConditionUninstalling, metav1.ConditionTrue)
+	require.Equal(t, configReasonCleaningNodes, uninstalling.Reason)
+	require.Equal(t, "Cleaning up nodes node-1", uninstalling.Message)
+
+	job := &batchv1.Job{}
+	require.NoError(t, cl.Get(ctx, types.NamespacedName{Namespace: config.Spec.Namespace, Name: "bpfman-cleanup-node-1"}, job))
+	require.True(t, metav1.IsControlledBy(job, config))
+	podSpec := job.Spec.Template.Spec
+	require.Equal(t, "node-1", podSpec.NodeName)
+	require.Equal(t, []string{"/bpfman-agent", "--cleanup-node"}, podSpec.Containers[0].Command)
+	require.Equal(t, config.Spec.Agent.Image, podSpec.Containers[0].Image)
+	require.Len(t, podSpec.Volumes, 4)
+	require.Equal(t, "/sys/fs/bpf", podSpec.Volumes[1].HostPath.Path)
+	// The programs are unloaded through the socket of bpfman, which still
+	// runs, and recorded in the audit log of the node.
+	require.Equal(t, "bpfman-sock", podSpec.Volumes[2].Name)
+	require.Equal(t, "/var/log/bpfman-agent", podSpec.Volumes[3].HostPath.Path)
+	require.Equal(t, "KUBE_NODE_NAME", podSpec.Containers[0].Env[0].Name)
+	require.NoError(t, cl.Get(ctx, client.ObjectKeyFromObject(ds), ds))
+
+	// A failed Job stops the uninstall.
+	job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobFailed, Status: corev1.ConditionTrue}}
+	require.NoError(t, cl.Status().Update(ctx, job))
+	res, err = r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	require.Zero(t, res.RequeueAfter)
+	config = getConfig(t, ctx, cl)
+	uninstalling = requireConfigCondition(t, config, v1alpha1.ConfigConditionUninstalling, metav1.ConditionTrue)
+	require.Equal(t, configReasonCleanupFailed, uninstalling.Reason)
+	require.Contains(t, uninstalling.Message, "node-1")
+
+	// Once the programs are unloaded, the DaemonSets are deleted, and a Job
+	// removes the bpfman database once bpfman is stopped.
+	completeJob(t, ctx, cl, job)
+	_, err = r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	require.True(t, errors.IsNotFound(cl.Get(ctx, client.ObjectKeyFromObject(ds), ds)))
+	config = getConfig(t, ctx, cl)
+	uninstalling = requireConfigCondition(t, config, v1alpha1.ConfigConditionUninstalling, metav1.ConditionTrue)
+	require.Equal(t, configReasonCleaningNodes, uninstalling.Reason)
+	require.Equal(t, "Removing the bpfman database from nodes node-1", uninstalling.Message)
+
+	dbJob := &batchv1.Job{}
+	require.NoError(t, cl.Get(ctx, types.NamespacedName{Namespace: config.Spec.Namespace, Name: "bpfman-db-cleanup-node-1"}, dbJob))
+	require.True(t, metav1.IsControlledBy(dbJob, config))
+	podSpec = dbJob.Spec.Template.Spec
+	require.Equal(t, "node-1", podSpec.NodeName)
+	require.Equal(t, []string{"/bpfman-agent", "--cleanup-node-database"}, podSpec.Containers[0].Command)
+	require.Len(t, podSpec.Volumes, 1)
+	require.Equal(t, "runtime", podSpec.Volumes[0].Name)
+	require.Len(t, podSpec.Containers[0].VolumeMounts, 1)
+
+	// Then the Config is deleted.
+	completeJob(t, ctx, cl, dbJob)
+	_, err = r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	require.True(t, errors.IsNotFound(cl.Get(ctx, types.NamespacedName{Name: config.Name}, config)))
+}
+
+func completeJob(t *testing.T, ctx context.Context, cl client.Client, job *batchv1.Job) {
+	job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}}
+	job.Status.Succeeded = 1
+	require.NoError(t, cl.Status().Update(ctx, job))
+}
+
+func TestConfigUninstallWithoutCleanup(t *testing.T) {
+	r, _, req, ctx, cl := setupTestEnvironment(false, false)
+
+	_, err := r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	_, err = r.Reconcile(ctx, req)
+	require.NoError(t, err)
+
+	config := getConfig(t, ctx, cl)
+	config.Spec.Uninstall.CleanupNodes = ptr.To(false)
+	require.NoError(t, cl.Update(ctx, config))
+	ds := &appsv1.DaemonSet{}
+	require.NoError(t, cl.Get(ctx, types.NamespacedName{Namespace: config.Spec.Namespace, Name: internal.BpfmanDsName}, ds))
+	createDaemonPod(t, ctx, cl, ds, "node-1", "rev1")
+
+	require.NoError(t, cl.Delete(ctx, config))
+	_, err = r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	require.True(t, errors.IsNotFound(cl.Get(ctx, types.NamespacedName{Name: config.Name}, config)))
+	jobs := &batchv1.JobList{}
+	require.NoError(t, cl.List(ctx, jobs))
+	require.Empty(t, jobs.Items)
+}
+
+func TestCleanupJobName(t *testing.T) {
+	require.Equal(t, "bpfman-cleanup-worker-0.example.com", cleanupJobName(cleanupJobPrefix, "worker-0.example.com"))
+
+	long := strings.Repeat("node", 30) + ".example.com"
+	name := cleanupJobName(cleanupJobPrefix, long)
+	require.LessOrEqual(t, len(name), 63)
+	require.True(t, strings.HasPrefix(name, "bpfman-cleanup-nodenode"))
+	require.NotEqual(t, name, cleanupJobName(cleanupJobPrefix, long+"x"))
+}
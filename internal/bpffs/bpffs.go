@@ -15,7 +15,7 @@ limitations under the License.
 */
 
 // Package bpffs provides functions to check and mount the BPF
-// filesystem.
+// filesystem, and to clean up what bpfman pinned in it.
 package bpffs
 
 import (
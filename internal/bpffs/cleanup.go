@@ -0,0 +1,204 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bpffs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	gobpfman "github.com/bpfman/bpfman/clients/gobpfman/v1"
+	"github.com/cilium/ebpf"
+)
+
+// DefaultRuntimeDir is the directory holding the state of bpfman on a
+// node: the bpffs bpfman pins its programs, links and maps in, and its
+// database.
+const DefaultRuntimeDir = "/run/bpfman"
+
+type objectKind int
+
+const (
+	objectOther objectKind = iota
+	objectProgram
+	objectMap
+)
+
+// pinnedObject describes the BPF object pinned at a path.
+type pinnedObject struct {
+	kind   objectKind
+	id     uint32
+	mapIDs []uint32
+}
+
+// inspectPin returns the object pinned at path. Links and objects that
+// can't be opened are reported as objectOther. It is a variable so that
+// tests don't need BPF privileges.
+var inspectPin = func(path string) (pinnedObject, error) {
+	if prog, err := ebpf.LoadPinnedProgram(path, nil); err == nil {
+		defer prog.Close()
+		info, err := prog.Info()
+		if err != nil {
+			return pinnedObject{}, fmt.Errorf("program info of %s: %w", path, err)
+		}
+		obj := pinnedObject{kind: objectProgram}
+		if id, ok := info.ID(); ok {
+			obj.id = uint32(id)
+		}
+		if ids, ok := info.MapIDs(); ok {
+			for _, id := range ids {
+				obj.mapIDs = append(obj.mapIDs, uint32(id))
+			}
+		}
+		return obj, nil
+	}
+	if m, err := ebpf.LoadPinnedMap(path, &ebpf.LoadPinOptions{ReadOnly: true}); err == nil {
+		defer m.Close()
+		info, err := m.Info()
+		if err != nil {
+			return pinnedObject{}, fmt.Errorf("map info of %s: %w", path, err)
+		}
+		obj := pinnedObject{kind: objectMap}
+		if id, ok := info.ID(); ok {
+			obj.id = uint32(id)
+		}
+		return obj, nil
+	}
+	return pinnedObject{kind: objectOther}, nil
+}
+
+// Cleanup removes the programs and pins bpfman left on a node once it is
+// uninstalled, while the bpfman daemon still runs. Every program client
+// lists is first unloaded with unload, which also detaches its links and lets
+// the caller audit the call.
+// Then the pins of these programs and of the maps they use are removed from
+// the bpffs mounted at mountPoint, and whatever is left pinned in the bpffs
+// of bpfman under runtimeDir is removed too. Pins under mountPoint that
+// don't belong to bpfman programs are kept. It returns the IDs of the
+// unloaded programs and the removed paths.
+func Cleanup(ctx context.Context, client gobpfman.BpfmanClient, unload func(ctx context.Context, id uint32) error,
+	runtimeDir, mountPoint string) (unloaded []uint32, removed []string, err error) {
+	bpfmanFS := filepath.Join(runtimeDir, "fs")
+
+	// Programs bpfman no longer lists may still be pinned in its bpffs.
+	programs := map[uint32]bool{}
+	maps := map[uint32]bool{}
+	err = walkPins(bpfmanFS, func(path string, obj pinnedObject) error {
+		if obj.kind == objectProgram {
+			programs[obj.id] = true
+			for _, id := range obj.mapIDs {
+				maps[id] = true
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	bpfmanProgramsOnly := true
+	list, err := client.List(ctx, &gobpfman.ListRequest{BpfmanProgramsOnly: &bpfmanProgramsOnly})
+	if err != nil {
+		return nil, nil, fmt.Errorf("list bpfman programs: %w", err)
+	}
+	for _, result := range list.Results {
+		info := result.GetKernelInfo()
+		if info == nil {
+			continue
+		}
+		programs[info.Id] = true
+		for _, id := range info.MapIds {
+			maps[id] = true
+		}
+		if err := unload(ctx, info.Id); err != nil {
+			return unloaded, nil, fmt.Errorf("unload program %d: %w", info.Id, err)
+		}
+		unloaded = append(unloaded, info.Id)
+	}
+
+	// The objects pinned under mountPoint are still loaded, so their IDs
+	// can be matched.
+	err = walkPins(mountPoint, func(path string, obj pinnedObject) error {
+		if (obj.kind == objectProgram && programs[obj.id]) || (obj.kind == objectMap && maps[obj.id]) {
+			if err := os.Remove(path); err != nil {
+				return fmt.Errorf("remove pin: %w", err)
+			}
+			removed = append(removed, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return unloaded, removed, err
+	}
+
+	entries, err := os.ReadDir(bpfmanFS)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return unloaded, removed, fmt.Errorf("read %s: %w", bpfmanFS, err)
+	}
+	for _, entry := range entries {
+		// Keep the mount point itself.
+		path := filepath.Join(bpfmanFS, entry.Name())
+		if err := os.RemoveAll(path); err != nil {
+			return unloaded, removed, fmt.Errorf("remove pin: %w", err)
+		}
+		removed = append(removed, path)
+	}
+	return unloaded, removed, nil
+}
+
+// RemoveDatabase removes the bpfman database under runtimeDir. It must only
+// run once the bpfman daemon is stopped, which would otherwise keep using
+// it. It returns the removed path, or an empty string if there was no
+// database.
+func RemoveDatabase(runtimeDir string) (string, error) {
+	db := filepath.Join(runtimeDir, "db")
+	if _, err := os.Stat(db); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return "", nil
+		}
+		return "", fmt.Errorf("stat bpfman database: %w", err)
+	}
+	if err := os.RemoveAll(db); err != nil {
+		return "", fmt.Errorf("remove bpfman database: %w", err)
+	}
+	return db, nil
+}
+
+// walkPins calls fn for every object pinned under root, which may not
+// exist.
+func walkPins(root string, fn func(path string, obj pinnedObject) error) error {
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		obj, err := inspectPin(path)
+		if err != nil {
+			return err
+		}
+		return fn(path, obj)
+	})
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("walk %s: %w", root, err)
+	}
+	return nil
+}
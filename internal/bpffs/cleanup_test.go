@@ -0,0 +1,171 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bpffs
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	gobpfman "github.com/bpfman/bpfman/clients/gobpfman/v1"
+	"google.golang.org/grpc"
+)
+
+// fakeBpfmanClient lists programs and records the ones unloaded.
+type fakeBpfmanClient struct {
+	gobpfman.BpfmanClient
+	programs []*gobpfman.KernelProgramInfo
+	unloaded []uint32
+}
+
+func (c *fakeBpfmanClient) List(ctx context.Context, in *gobpfman.ListRequest,
+	opts ...grpc.CallOption) (*gobpfman.ListResponse, error) {
+	response := &gobpfman.ListResponse{}
+	for _, info := range c.programs {
+		if slices.Contains(c.unloaded, info.Id) {
+			continue
+		}
+		response.Results = append(response.Results, &gobpfman.ListResponse_ListResult{KernelInfo: info})
+	}
+	return response, nil
+}
+
+func (c *fakeBpfmanClient) unload(ctx context.Context, id uint32) error {
+	c.unloaded = append(c.unloaded, id)
+	return nil
+}
+
+func TestCleanup(t *testing.T) {
+	dir := t.TempDir()
+	runtimeDir := filepath.Join(dir, "run", "bpfman")
+	mountPoint := filepath.Join(dir, "sys", "fs", "bpf")
+
+	// Objects by the name of their pins.
+	objects := map[string]pinnedObject{
+		"prog_12":     {kind: objectProgram, id: 12, mapIDs: []uint32{3, 4}},
+		"link_1":      {kind: objectOther},
+		"xdp_stats":   {kind: objectMap, id: 3},
+		"prog_pinned": {kind: objectProgram, id: 12},
+		"cilium_ct":   {kind: objectMap, id: 9},
+		"other_prog":  {kind: objectProgram, id: 13},
+		"tc_counters": {kind: objectMap, id: 7},
+	}
+	saved := inspectPin
+	inspectPin = func(path string) (pinnedObject, error) {
+		return objects[filepath.Base(path)], nil
+	}
+	t.Cleanup(func() { inspectPin = saved })
+
+	pins := []string{
+		filepath.Join(runtimeDir, "fs", "prog_12"),
+		filepath.Join(runtimeDir, "fs", "links", "12", "link_1"),
+		filepath.Join(runtimeDir, "db", "conf"),
+		filepath.Join(mountPoint, "xdp_stats"),
+		filepath.Join(mountPoint, "app", "prog_pinned"),
+		filepath.Join(mountPoint, "cilium_ct"),
+		filepath.Join(mountPoint, "other_prog"),
+		filepath.Join(mountPoint, "tc_counters"),
+	}
+	for _, pin := range pins {
+		if err := os.MkdirAll(filepath.Dir(pin), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(pin, nil, 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Program 15 is only known to bpfman, and uses a map pinned under
+	// mountPoint.
+	client := &fakeBpfmanClient{programs: []*gobpfman.KernelProgramInfo{
+		{Id: 12, MapIds: []uint32{3, 4}},
+		{Id: 15, MapIds: []uint32{7}},
+	}}
+	ctx := context.Background()
+	unloaded, removed, err := Cleanup(ctx, client, client.unload, runtimeDir, mountPoint)
+	if err != nil {
+		t.Fatalf("Cleanup() error = %v", err)
+	}
+	if !slices.Equal(unloaded, []uint32{12, 15}) || !slices.Equal(client.unloaded, unloaded) {
+		t.Errorf("Cleanup() unloaded %v, bpfman unloaded %v, want [12 15]", unloaded, client.unloaded)
+	}
+	want := []string{
+		filepath.Join(mountPoint, "app", "prog_pinned"),
+		filepath.Join(mountPoint, "tc_counters"),
+		filepath.Join(mountPoint, "xdp_stats"),
+		filepath.Join(runtimeDir, "fs", "links"),
+		filepath.Join(runtimeDir, "fs", "prog_12"),
+	}
+	if !slices.Equal(removed, want) {
+		t.Errorf("Cleanup() removed %v, want %v", removed, want)
+	}
+
+	// The pins of other programs and maps are kept, and so are the bpffs
+	// and the database of bpfman, which still runs.
+	for _, path := range []string{
+		filepath.Join(mountPoint, "cilium_ct"),
+		filepath.Join(mountPoint, "other_prog"),
+		filepath.Join(runtimeDir, "fs"),
+		filepath.Join(runtimeDir, "db", "conf"),
+	} {
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("%s was removed: %v", path, err)
+		}
+	}
+
+	// Nothing is left to remove.
+	unloaded, removed, err = Cleanup(ctx, client, client.unload, runtimeDir, mountPoint)
+	if err != nil || len(unloaded) > 0 || len(removed) > 0 {
+		t.Errorf("second Cleanup() = %v, %v, %v, want nothing unloaded or removed", unloaded, removed, err)
+	}
+}
+
+func TestCleanupMissingRuntimeDir(t *testing.T) {
+	dir := t.TempDir()
+	client := &fakeBpfmanClient{}
+	unloaded, removed, err := Cleanup(context.Background(), client, client.unload,
+		filepath.Join(dir, "missing"), filepath.Join(dir, "missing-bpffs"))
+	if err != nil || len(unloaded) > 0 || len(removed) > 0 {
+		t.Errorf("Cleanup() = %v, %v, %v, want nothing unloaded or removed", unloaded, removed, err)
+	}
+}
+
+func TestRemoveDatabase(t *testing.T) {
+	runtimeDir := t.TempDir()
+	db := filepath.Join(runtimeDir, "db")
+	if err := os.MkdirAll(db, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(db, "conf"), nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	removed, err := RemoveDatabase(runtimeDir)
+	if err != nil || removed != db {
+		t.Errorf("RemoveDatabase() = %q, %v, want %q", removed, err, db)
+	}
+	if _, err := os.Stat(db); !os.IsNotExist(err) {
+		t.Errorf("%s was not removed: %v", db, err)
+	}
+
+	removed, err = RemoveDatabase(runtimeDir)
+	if err != nil || removed != "" {
+		t.Errorf("second RemoveDatabase() = %q, %v, want nothing removed", removed, err)
+	}
+}
@@ -0,0 +1,197 @@
+/*
+Copyright 2025 The bpfman Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bpfmanconfig
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// validator checks the value of a bpfman.toml setting.
+type validator func(v any) error
+
+func isNonNegativeInteger(v any) error {
+	i, ok := v.(int64)
+	if !ok {
+		return fmt.Errorf("must be an integer")
+	}
+	if i < 0 {
+		return fmt.Errorf("must not be negative")
+	}
+	return nil
+}
+
+func isBool(v any) error {
+	if _, ok := v.(bool); !ok {
+		return fmt.Errorf("must be a boolean")
+	}
+	return nil
+}
+
+func isString(v any) error {
+	if _, ok := v.(string); !ok {
+		return fmt.Errorf("must be a string")
+	}
+	return nil
+}
+
+func isOneOf(values ...string) validator {
+	return func(v any) error {
+		s, ok := v.(string)
+		if !ok || !slices.Contains(values, s) {
+			return fmt.Errorf("must be one of %s", strings.Join(values, ", "))
+		}
+		return nil
+	}
+}
+
+// schema lists the settings of each bpfman.toml table.
+var schema = map[string]map[string]validator{
+	"database": {
+		"max_retries":    isNonNegativeInteger,
+		"millisec_delay": isNonNegativeInteger,
+	},
+	"signing": {
+		"allow_unsigned": isBool,
+		"verify_enabled": isBool,
+	},
+	"registry": {
+		"xdp_dispatcher_image": isString,
+		"tc_dispatcher_image":  isString,
+	},
+}
+
+// interfaceSchema lists the settings of each [interfaces.<name>] table.
+var interfaceSchema = map[string]validator{
+	"xdp_mode": isOneOf("skb", "drv", "hw"),
+}
+
+// Validate checks that the settings of doc known to bpfman have values of the
+// expected types. Settings unknown to bpfman are not errors, since the raw
+// configuration may set what this package doesn't know about, and are
+// returned by UnknownSettings instead.
+func Validate(doc Document) error {
+	var errs []error
+	for _, table := range sortedKeys(doc) {
+		if table == "interfaces" {
+			interfaces, ok := doc[table].(Document)
+			if !ok {
+				errs = append(errs, fmt.Errorf("interfaces: must be a table"))
+				continue
+			}
+			for _, iface := range sortedKeys(interfaces) {
+				ifaceSettings, ok := interfaces[iface].(Document)
+				if !ok {
+					errs = append(errs, fmt.Errorf("interfaces.%s: must be a table", iface))
+					continue
+				}
+				errs = append(errs, validateTable("interfaces."+iface, ifaceSettings, interfaceSchema)...)
+			}
+			continue
+		}
+
+		tableSchema, ok := schema[table]
+		if !ok {
+			continue
+		}
+		settings, ok := doc[table].(Document)
+		if !ok {
+			errs = append(errs, fmt.Errorf("%s: must be a table", table))
+			continue
+		}
+		errs = append(errs, validateTable(table, settings, tableSchema)...)
+	}
+	return errors.Join(errs...)
+}
+
+func validateTable(name string, settings Document, tableSchema map[string]validator) []error {
+	var errs []error
+	for _, key := range sortedKeys(settings) {
+		validate, ok := tableSchema[key]
+		if !ok {
+			continue
+		}
+		if err := validate(settings[key]); err != nil {
+			errs = append(errs, fmt.Errorf("%s.%s: %w", name, key, err))
+		}
+	}
+	return errs
+}
+
+// UnknownSettings returns the settings and tables of doc that are not known
+// to bpfman. bpfman ignores them, so they are usually misspelt keys.
+func UnknownSettings(doc Document) []string {
+	var unknown []string
+	for _, table := range sortedKeys(doc) {
+		settings, isTable := doc[table].(Document)
+		if table == "interfaces" {
+			for _, iface := range sortedKeys(settings) {
+				if ifaceSettings, ok := settings[iface].(Document); ok {
+					unknown = append(unknown, unknownKeys("interfaces."+iface, ifaceSettings, interfaceSchema)...)
+				}
+			}
+			continue
+		}
+
+		tableSchema, ok := schema[table]
+		if !ok {
+			unknown = append(unknown, table)
+			continue
+		}
+		if isTable {
+			unknown = append(unknown, unknownKeys(table, settings, tableSchema)...)
+		}
+	}
+	return unknown
+}
+
+func unknownKeys(name string, settings Document, tableSchema map[string]validator) []string {
+	var unknown []string
+	for _, key := range sortedKeys(settings) {
+		if _, ok := tableSchema[key]; !ok {
+			unknown = append(unknown, name+"."+key)
+		}
+	}
+	return unknown
+}
+
+// Merge sets every value of override in doc, recursing into tables.
+func Merge(doc, override Document) {
+	for key, value := range override {
+		if table, ok := value.(Document); ok {
+			existing, ok := doc[key].(Document)
+			if !ok {
+				existing = Document{}
+				doc[key] = existing
+			}
+			Merge(existing, table)
+			continue
+		}
+		doc[key] = value
+	}
+}
+
+func sortedKeys(doc Document) []string {
+	keys := make([]string, 0, len(doc))
+	for k := range doc {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2025 The bpfman Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package bpfmanconfig parses, validates and renders the bpfman daemon
+// configuration file, bpfman.toml.
+package bpfmanconfig
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/pelletier/go-toml/v2"
+)
+
+// Document is a parsed TOML document. Tables are nested Documents, and
+// values are string, int64, float64, bool, the date-time types of go-toml, or
+// []any, whose elements are Documents for arrays of tables.
+type Document map[string]any
+
+// Parse parses the TOML document src.
+func Parse(src string) (Document, error) {
+	var table map[string]any
+	if err := toml.Unmarshal([]byte(src), &table); err != nil {
+		var decodeErr *toml.DecodeError
+		if errors.As(err, &decodeErr) {
+			row, column := decodeErr.Position()
+			return nil, fmt.Errorf("line %d, column %d: %w", row, column, err)
+		}
+		return nil, err
+	}
+	return toDocument(table), nil
+}
+
+// toDocument converts the tables decoded by go-toml to Documents.
+func toDocument(table map[string]any) Document {
+	doc := make(Document, len(table))
+	for key, value := range table {
+		doc[key] = toDocumentValue(value)
+	}
+	return doc
+}
+
+func toDocumentValue(value any) any {
+	switch value := value.(type) {
+	case map[string]any:
+		return toDocument(value)
+	case []any:
+		values := make([]any, len(value))
+		for i, v := range value {
+			values[i] = toDocumentValue(v)
+		}
+		return values
+	default:
+		return value
+	}
+}
+
+// Render returns doc as TOML. Keys are sorted, so equal documents are
+// rendered identically.
+func Render(doc Document) (string, error) {
+	var b bytes.Buffer
+	if err := toml.NewEncoder(&b).Encode(doc); err != nil {
+		return "", fmt.Errorf("failed to render bpfman configuration: %w", err)
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,208 @@
+/*
+Copyright 2025 The bpfman Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bpfmanconfig_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bpfman/bpfman-operator/internal/bpfmanconfig"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		src     string
+		want    bpfmanconfig.Document
+		wantErr string
+	}{
+		{
+			name: "default configuration",
+			src: `[database]
+max_retries = 30
+millisec_delay = 10000
+[signing]
+allow_unsigned = true
+verify_enabled = true
+`,
+			want: bpfmanconfig.Document{
+				"database": bpfmanconfig.Document{"max_retries": int64(30), "millisec_delay": int64(10000)},
+				"signing":  bpfmanconfig.Document{"allow_unsigned": true, "verify_enabled": true},
+			},
+		},
+		{
+			name: "values, comments and dotted keys",
+			src: `# bpfman configuration
+[interfaces.eth0]
+xdp_mode = "drv" # native
+[registry]
+'xdp_dispatcher_image' = 'quay.io/bpfman/xdp-dispatcher:latest'
+a.b = [1, 0x10, 1_000,
+  2.5]
+c = { d = "e\tf" }
+`,
+			want: bpfmanconfig.Document{
+				"interfaces": bpfmanconfig.Document{"eth0": bpfmanconfig.Document{"xdp_mode": "drv"}},
+				"registry": bpfmanconfig.Document{
+					"xdp_dispatcher_image": "quay.io/bpfman/xdp-dispatcher:latest",
+					"a":                    bpfmanconfig.Document{"b": []any{int64(1), int64(16), int64(1000), 2.5}},
+					"c":                    bpfmanconfig.Document{"d": "e\tf"},
+				},
+			},
+		},
+		{
+			name: "multi-line strings, arrays of tables and date-times",
+			src: `[registry]
+xdp_dispatcher_image = """
+quay.io/bpfman/xdp-dispatcher:latest"""
+[[plugins]]
+name = "a"
+[[plugins]]
+name = "b"
+[schedule]
+start = 1979-05-27T07:32:00Z
+`,
+			want: bpfmanconfig.Document{
+				"registry": bpfmanconfig.Document{"xdp_dispatcher_image": "quay.io/bpfman/xdp-dispatcher:latest"},
+				"plugins":  []any{bpfmanconfig.Document{"name": "a"}, bpfmanconfig.Document{"name": "b"}},
+				"schedule": bpfmanconfig.Document{"start": time.Date(1979, time.May, 27, 7, 32, 0, 0, time.UTC)},
+			},
+		},
+		{
+			name: "empty document",
+			src:  "\n# nothing\n",
+			want: bpfmanconfig.Document{},
+		},
+		{
+			name:    "unterminated table header",
+			src:     "[database\nmax_retries = 1\n",
+			wantErr: "line 1,",
+		},
+		{
+			name:    "missing value",
+			src:     "[database]\nmax_retries =\n",
+			wantErr: "line 2,",
+		},
+		{
+			name:    "duplicate key",
+			src:     "[database]\nmax_retries = 1\nmax_retries = 2\n",
+			wantErr: "key max_retries is already defined",
+		},
+		{
+			name:    "duplicate table",
+			src:     "[database]\n[signing]\n[database]\n",
+			wantErr: "table database already exists",
+		},
+		{
+			name:    "unquoted string",
+			src:     "[registry]\nxdp_dispatcher_image = quay.io/bpfman\n",
+			wantErr: "line 2,",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			doc, err := bpfmanconfig.Parse(tc.src)
+			if tc.wantErr != "" {
+				require.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.want, doc)
+		})
+	}
+}
+
+func TestRenderRoundTrip(t *testing.T) {
+	doc := bpfmanconfig.Document{
+		"database": bpfmanconfig.Document{"max_retries": int64(30), "millisec_delay": int64(10000)},
+		"registry": bpfmanconfig.Document{"xdp_dispatcher_image": "quay.io/\"bpfman\"\n"},
+		"interfaces": bpfmanconfig.Document{
+			"eth0":    bpfmanconfig.Document{"xdp_mode": "skb"},
+			"br-ex.1": bpfmanconfig.Document{"xdp_mode": "hw"},
+		},
+		"ratio": 1.0,
+	}
+
+	rendered, err := bpfmanconfig.Render(doc)
+	require.NoError(t, err)
+	require.Equal(t, `ratio = 1.0
+
+[database]
+max_retries = 30
+millisec_delay = 10000
+
+[interfaces]
+[interfaces.'br-ex.1']
+xdp_mode = 'hw'
+
+[interfaces.eth0]
+xdp_mode = 'skb'
+
+[registry]
+xdp_dispatcher_image = "quay.io/\"bpfman\"\n"
+`, rendered)
+
+	parsed, err := bpfmanconfig.Parse(rendered)
+	require.NoError(t, err)
+	require.Equal(t, doc, parsed)
+}
+
+func TestValidate(t *testing.T) {
+	doc, err := bpfmanconfig.Parse(`[database]
+max_retries = -1
+millisec_delay = "10"
+[signing]
+allow_unsigned = true
+verfy_enabled = true
+[interfaces.eth0]
+xdp_mode = "native"
+[dtabase]
+max_retries = 1
+`)
+	require.NoError(t, err)
+
+	err = bpfmanconfig.Validate(doc)
+	require.ErrorContains(t, err, "database.max_retries: must not be negative")
+	require.ErrorContains(t, err, "database.millisec_delay: must be an integer")
+	require.ErrorContains(t, err, "interfaces.eth0.xdp_mode: must be one of skb, drv, hw")
+	require.NotContains(t, err.Error(), "verfy_enabled")
+	require.NotContains(t, err.Error(), "dtabase")
+
+	// Unknown settings are not errors, but they are reported.
+	require.Equal(t, []string{"dtabase", "signing.verfy_enabled"}, bpfmanconfig.UnknownSettings(doc))
+
+	require.NoError(t, bpfmanconfig.Validate(bpfmanconfig.Document{
+		"signing": bpfmanconfig.Document{"allow_unsigned": false},
+	}))
+}
+
+func TestMerge(t *testing.T) {
+	doc := bpfmanconfig.Document{
+		"database": bpfmanconfig.Document{"max_retries": int64(30), "millisec_delay": int64(10000)},
+	}
+	bpfmanconfig.Merge(doc, bpfmanconfig.Document{
+		"database": bpfmanconfig.Document{"max_retries": int64(5)},
+		"signing":  bpfmanconfig.Document{"allow_unsigned": false},
+	})
+	require.Equal(t, bpfmanconfig.Document{
+		"database": bpfmanconfig.Document{"max_retries": int64(5), "millisec_delay": int64(10000)},
+		"signing":  bpfmanconfig.Document{"allow_unsigned": false},
+	}, doc)
+}
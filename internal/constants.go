@@ -21,8 +21,16 @@ import "fmt"
 const (
 	K8sHostLabel                              = "kubernetes.io/hostname"
 	DiscoveredLabel                           = "bpfman.io/discoveredProgram"
+	NodePoolLabel                             = "bpfman.io/node-pool"
 	UuidMetadataKey                           = "bpfman.io/uuid"
 	ProgramNameKey                            = "bpfman.io/ProgramName"
+	AppKindMetadataKey                        = "bpfman.io/AppKind"
+	AppNamespaceMetadataKey                   = "bpfman.io/AppNamespace"
+	AppNameMetadataKey                        = "bpfman.io/AppName"
+	PodNamespaceMetadataKey                   = "bpfman.io/PodNamespace"
+	PodNameMetadataKey                        = "bpfman.io/PodName"
+	PodUIDMetadataKey                         = "bpfman.io/PodUID"
+	ContainerNameMetadataKey                  = "bpfman.io/ContainerName"
 	BpfmanNamespace                           = "bpfman"
 	BpfmanOperatorName                        = "bpfman-operator"
 	BpfmanDsName                              = "bpfman-daemon"
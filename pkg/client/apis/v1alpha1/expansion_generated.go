@@ -34,6 +34,14 @@ type BpfApplicationStateListerExpansion interface{}
 // BpfApplicationStateNamespaceLister.
 type BpfApplicationStateNamespaceListerExpansion interface{}
 
+// BpfPriorityClassListerExpansion allows custom methods to be added to
+// BpfPriorityClassLister.
+type BpfPriorityClassListerExpansion interface{}
+
+// BpfProgramInventoryListerExpansion allows custom methods to be added to
+// BpfProgramInventoryLister.
+type BpfProgramInventoryListerExpansion interface{}
+
 // ClusterBpfApplicationListerExpansion allows custom methods to be added to
 // ClusterBpfApplicationLister.
 type ClusterBpfApplicationListerExpansion interface{}
@@ -30,6 +30,8 @@ type BpfmanV1alpha1Interface interface {
 	RESTClient() rest.Interface
 	BpfApplicationsGetter
 	BpfApplicationStatesGetter
+	BpfPriorityClassesGetter
+	BpfProgramInventoriesGetter
 	ClusterBpfApplicationsGetter
 	ClusterBpfApplicationStatesGetter
 	ConfigsGetter
@@ -48,6 +50,14 @@ func (c *BpfmanV1alpha1Client) BpfApplicationStates(namespace string) BpfApplica
 	return newBpfApplicationStates(c, namespace)
 }
 
+func (c *BpfmanV1alpha1Client) BpfPriorityClasses() BpfPriorityClassInterface {
+	return newBpfPriorityClasses(c)
+}
+
+func (c *BpfmanV1alpha1Client) BpfProgramInventories() BpfProgramInventoryInterface {
+	return newBpfProgramInventories(c)
+}
+
 func (c *BpfmanV1alpha1Client) ClusterBpfApplications() ClusterBpfApplicationInterface {
 	return newClusterBpfApplications(c)
 }
@@ -0,0 +1,68 @@
+/*
+Copyright 2025 The bpfman Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	context "context"
+
+	apisv1alpha1 "github.com/bpfman/bpfman-operator/apis/v1alpha1"
+	scheme "github.com/bpfman/bpfman-operator/pkg/client/clientset/scheme"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	gentype "k8s.io/client-go/gentype"
+)
+
+// BpfPriorityClassesGetter has a method to return a BpfPriorityClassInterface.
+// A group's client should implement this interface.
+type BpfPriorityClassesGetter interface {
+	BpfPriorityClasses() BpfPriorityClassInterface
+}
+
+// BpfPriorityClassInterface has methods to work with BpfPriorityClass resources.
+type BpfPriorityClassInterface interface {
+	Create(ctx context.Context, bpfPriorityClass *apisv1alpha1.BpfPriorityClass, opts v1.CreateOptions) (*apisv1alpha1.BpfPriorityClass, error)
+	Update(ctx context.Context, bpfPriorityClass *apisv1alpha1.BpfPriorityClass, opts v1.UpdateOptions) (*apisv1alpha1.BpfPriorityClass, error)
+	Delete(ctx context.Context, name string, opts v1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error
+	Get(ctx context.Context, name string, opts v1.GetOptions) (*apisv1alpha1.BpfPriorityClass, error)
+	List(ctx context.Context, opts v1.ListOptions) (*apisv1alpha1.BpfPriorityClassList, error)
+	Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *apisv1alpha1.BpfPriorityClass, err error)
+	BpfPriorityClassExpansion
+}
+
+// bpfPriorityClasses implements BpfPriorityClassInterface
+type bpfPriorityClasses struct {
+	*gentype.ClientWithList[*apisv1alpha1.BpfPriorityClass, *apisv1alpha1.BpfPriorityClassList]
+}
+
+// newBpfPriorityClasses returns a BpfPriorityClasses
+func newBpfPriorityClasses(c *BpfmanV1alpha1Client) *bpfPriorityClasses {
+	return &bpfPriorityClasses{
+		gentype.NewClientWithList[*apisv1alpha1.BpfPriorityClass, *apisv1alpha1.BpfPriorityClassList](
+			"bpfpriorityclasses",
+			c.RESTClient(),
+			scheme.ParameterCodec,
+			"",
+			func() *apisv1alpha1.BpfPriorityClass { return &apisv1alpha1.BpfPriorityClass{} },
+			func() *apisv1alpha1.BpfPriorityClassList { return &apisv1alpha1.BpfPriorityClassList{} },
+		),
+	}
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2025 The bpfman Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	context "context"
+
+	apisv1alpha1 "github.com/bpfman/bpfman-operator/apis/v1alpha1"
+	scheme "github.com/bpfman/bpfman-operator/pkg/client/clientset/scheme"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	gentype "k8s.io/client-go/gentype"
+)
+
+// BpfProgramInventoriesGetter has a method to return a BpfProgramInventoryInterface.
+// A group's client should implement this interface.
+type BpfProgramInventoriesGetter interface {
+	BpfProgramInventories() BpfProgramInventoryInterface
+}
+
+// BpfProgramInventoryInterface has methods to work with BpfProgramInventory resources.
+type BpfProgramInventoryInterface interface {
+	Create(ctx context.Context, bpfProgramInventory *apisv1alpha1.BpfProgramInventory, opts v1.CreateOptions) (*apisv1alpha1.BpfProgramInventory, error)
+	Update(ctx context.Context, bpfProgramInventory *apisv1alpha1.BpfProgramInventory, opts v1.UpdateOptions) (*apisv1alpha1.BpfProgramInventory, error)
+	// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+	UpdateStatus(ctx context.Context, bpfProgramInventory *apisv1alpha1.BpfProgramInventory, opts v1.UpdateOptions) (*apisv1alpha1.BpfProgramInventory, error)
+	Delete(ctx context.Context, name string, opts v1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error
+	Get(ctx context.Context, name string, opts v1.GetOptions) (*apisv1alpha1.BpfProgramInventory, error)
+	List(ctx context.Context, opts v1.ListOptions) (*apisv1alpha1.BpfProgramInventoryList, error)
+	Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *apisv1alpha1.BpfProgramInventory, err error)
+	BpfProgramInventoryExpansion
+}
+
+// bpfProgramInventories implements BpfProgramInventoryInterface
+type bpfProgramInventories struct {
+	*gentype.ClientWithList[*apisv1alpha1.BpfProgramInventory, *apisv1alpha1.BpfProgramInventoryList]
+}
+
+// newBpfProgramInventories returns a BpfProgramInventories
+func newBpfProgramInventories(c *BpfmanV1alpha1Client) *bpfProgramInventories {
+	return &bpfProgramInventories{
+		gentype.NewClientWithList[*apisv1alpha1.BpfProgramInventory, *apisv1alpha1.BpfProgramInventoryList](
+			"bpfprograminventories",
+			c.RESTClient(),
+			scheme.ParameterCodec,
+			"",
+			func() *apisv1alpha1.BpfProgramInventory { return &apisv1alpha1.BpfProgramInventory{} },
+			func() *apisv1alpha1.BpfProgramInventoryList { return &apisv1alpha1.BpfProgramInventoryList{} },
+		),
+	}
+}
@@ -36,6 +36,14 @@ func (c *FakeBpfmanV1alpha1) BpfApplicationStates(namespace string) v1alpha1.Bpf
 	return newFakeBpfApplicationStates(c, namespace)
 }
 
+func (c *FakeBpfmanV1alpha1) BpfPriorityClasses() v1alpha1.BpfPriorityClassInterface {
+	return newFakeBpfPriorityClasses(c)
+}
+
+func (c *FakeBpfmanV1alpha1) BpfProgramInventories() v1alpha1.BpfProgramInventoryInterface {
+	return newFakeBpfProgramInventories(c)
+}
+
 func (c *FakeBpfmanV1alpha1) ClusterBpfApplications() v1alpha1.ClusterBpfApplicationInterface {
 	return newFakeClusterBpfApplications(c)
 }
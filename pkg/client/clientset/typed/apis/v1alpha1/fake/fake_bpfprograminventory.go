@@ -0,0 +1,52 @@
+/*
+Copyright 2025 The bpfman Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	v1alpha1 "github.com/bpfman/bpfman-operator/apis/v1alpha1"
+	apisv1alpha1 "github.com/bpfman/bpfman-operator/pkg/client/clientset/typed/apis/v1alpha1"
+	gentype "k8s.io/client-go/gentype"
+)
+
+// fakeBpfProgramInventories implements BpfProgramInventoryInterface
+type fakeBpfProgramInventories struct {
+	*gentype.FakeClientWithList[*v1alpha1.BpfProgramInventory, *v1alpha1.BpfProgramInventoryList]
+	Fake *FakeBpfmanV1alpha1
+}
+
+func newFakeBpfProgramInventories(fake *FakeBpfmanV1alpha1) apisv1alpha1.BpfProgramInventoryInterface {
+	return &fakeBpfProgramInventories{
+		gentype.NewFakeClientWithList[*v1alpha1.BpfProgramInventory, *v1alpha1.BpfProgramInventoryList](
+			fake.Fake,
+			"",
+			v1alpha1.SchemeGroupVersion.WithResource("bpfprograminventories"),
+			v1alpha1.SchemeGroupVersion.WithKind("BpfProgramInventory"),
+			func() *v1alpha1.BpfProgramInventory { return &v1alpha1.BpfProgramInventory{} },
+			func() *v1alpha1.BpfProgramInventoryList { return &v1alpha1.BpfProgramInventoryList{} },
+			func(dst, src *v1alpha1.BpfProgramInventoryList) { dst.ListMeta = src.ListMeta },
+			func(list *v1alpha1.BpfProgramInventoryList) []*v1alpha1.BpfProgramInventory {
+				return gentype.ToPointerSlice(list.Items)
+			},
+			func(list *v1alpha1.BpfProgramInventoryList, items []*v1alpha1.BpfProgramInventory) {
+				list.Items = gentype.FromPointerSlice(items)
+			},
+		),
+		fake,
+	}
+}
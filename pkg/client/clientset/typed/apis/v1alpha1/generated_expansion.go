@@ -22,6 +22,10 @@ type BpfApplicationExpansion interface{}
 
 type BpfApplicationStateExpansion interface{}
 
+type BpfPriorityClassExpansion interface{}
+
+type BpfProgramInventoryExpansion interface{}
+
 type ClusterBpfApplicationExpansion interface{}
 
 type ClusterBpfApplicationStateExpansion interface{}
@@ -28,6 +28,10 @@ type Interface interface {
 	BpfApplications() BpfApplicationInformer
 	// BpfApplicationStates returns a BpfApplicationStateInformer.
 	BpfApplicationStates() BpfApplicationStateInformer
+	// BpfPriorityClasses returns a BpfPriorityClassInformer.
+	BpfPriorityClasses() BpfPriorityClassInformer
+	// BpfProgramInventories returns a BpfProgramInventoryInformer.
+	BpfProgramInventories() BpfProgramInventoryInformer
 	// ClusterBpfApplications returns a ClusterBpfApplicationInformer.
 	ClusterBpfApplications() ClusterBpfApplicationInformer
 	// ClusterBpfApplicationStates returns a ClusterBpfApplicationStateInformer.
@@ -57,6 +61,16 @@ func (v *version) BpfApplicationStates() BpfApplicationStateInformer {
 	return &bpfApplicationStateInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// BpfPriorityClasses returns a BpfPriorityClassInformer.
+func (v *version) BpfPriorityClasses() BpfPriorityClassInformer {
+	return &bpfPriorityClassInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
+// BpfProgramInventories returns a BpfProgramInventoryInformer.
+func (v *version) BpfProgramInventories() BpfProgramInventoryInformer {
+	return &bpfProgramInventoryInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
 // ClusterBpfApplications returns a ClusterBpfApplicationInformer.
 func (v *version) ClusterBpfApplications() ClusterBpfApplicationInformer {
 	return &clusterBpfApplicationInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
@@ -57,6 +57,10 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Bpfman().V1alpha1().BpfApplications().Informer()}, nil
 	case v1alpha1.SchemeGroupVersion.WithResource("bpfapplicationstates"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Bpfman().V1alpha1().BpfApplicationStates().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("bpfpriorityclasses"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Bpfman().V1alpha1().BpfPriorityClasses().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("bpfprograminventories"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Bpfman().V1alpha1().BpfProgramInventories().Informer()}, nil
 	case v1alpha1.SchemeGroupVersion.WithResource("clusterbpfapplications"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Bpfman().V1alpha1().ClusterBpfApplications().Informer()}, nil
 	case v1alpha1.SchemeGroupVersion.WithResource("clusterbpfapplicationstates"):
@@ -30,3 +30,16 @@ func GetContainerPIDsFromPod(ctx context.Context, podName string, containerNames
 
 	return client.GetContainerInfoFromPod(ctx, podName, containerNames)
 }
+
+// GetPodSandboxNetns retrieves the network namespaces of the sandboxes
+// of the pods with the specified UIDs, keyed by pod UID, without
+// inspecting their containers.
+func GetPodSandboxNetns(ctx context.Context, podUIDs []string) (map[string]PodSandboxNetnsInfo, error) {
+	client, err := NewClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	return client.GetPodSandboxNetns(ctx, podUIDs)
+}
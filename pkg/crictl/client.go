@@ -235,6 +235,76 @@ func (c *Client) GetContainerInfoFromPod(ctx context.Context, podName string, co
 	)
 }
 
+// podSandboxStatus returns the status of a pod sandbox and its
+// processed verbose info.
+func (c *Client) podSandboxStatus(ctx context.Context, sandboxID string) (*runtime.PodSandboxStatus, map[string]interface{}, error) {
+	resp, err := c.runtimeClient.PodSandboxStatus(ctx, &runtime.PodSandboxStatusRequest{
+		PodSandboxId: sandboxID,
+		Verbose:      true,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("getting pod sandbox status for %s: %w", sandboxID, err)
+	}
+
+	infoMap := make(map[string]interface{})
+	for key, value := range resp.Info {
+		infoMap[key] = value
+	}
+
+	return resp.Status, processInfoField(infoMap), nil
+}
+
+// GetPodSandboxNetns retrieves the network namespaces of the ready
+// sandboxes of the pods with the specified UIDs, keyed by pod UID.
+// Pods without a ready sandbox are omitted.
+func (c *Client) GetPodSandboxNetns(ctx context.Context, podUIDs []string) (map[string]PodSandboxNetnsInfo, error) {
+	resp, err := c.runtimeClient.ListPodSandbox(ctx, &runtime.ListPodSandboxRequest{
+		Filter: &runtime.PodSandboxFilter{
+			State: &runtime.PodSandboxStateValue{State: runtime.PodSandboxState_SANDBOX_READY},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing pod sandboxes: %w", err)
+	}
+
+	pods := make([]PodInfo, 0, len(resp.Items))
+	for _, pod := range resp.Items {
+		pods = append(pods, PodInfo{
+			ID: pod.Id,
+			Metadata: PodMetadata{
+				Name:      pod.Metadata.Name,
+				UID:       pod.Metadata.Uid,
+				Namespace: pod.Metadata.Namespace,
+				Attempt:   pod.Metadata.Attempt,
+			},
+			CreatedAt: pod.CreatedAt,
+		})
+	}
+
+	result := make(map[string]PodSandboxNetnsInfo)
+	for uid, pod := range findNewestSandboxes(pods, podUIDs) {
+		status, info, err := c.podSandboxStatus(ctx, pod.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		netnsInfo := PodSandboxNetnsInfo{
+			PodName:     pod.Metadata.Name,
+			Namespace:   pod.Metadata.Namespace,
+			UID:         uid,
+			SandboxID:   pod.ID,
+			NetnsPath:   extractNetnsPathFromInfo(info),
+			HostNetwork: status.GetLinux().GetNamespaces().GetOptions().GetNetwork() == runtime.NamespaceMode_NODE,
+		}
+		if pid, err := extractPIDFromInspectInfo(info); err == nil {
+			netnsInfo.PID = pid
+		}
+		result[uid] = netnsInfo
+	}
+
+	return result, nil
+}
+
 // Public methods for CLI usage.
 
 // ListPods lists pods, optionally filtering by name.
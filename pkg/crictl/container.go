@@ -90,6 +90,7 @@ func buildContainerPIDInfo(podName string, namespace string, container Container
 		ContainerID:   container.ID,
 		PID:           pid,
 		Namespace:     namespace,
+		PodSandboxID:  container.PodID,
 	}
 }
 
@@ -0,0 +1,55 @@
+/*
+Copyright 2025 The bpfman Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package crictl
+
+// findNewestSandboxes returns the newest sandbox of each pod UID in
+// podUIDs, keyed by UID. Pods without a sandbox are omitted.
+func findNewestSandboxes(pods []PodInfo, podUIDs []string) map[string]PodInfo {
+	wanted := make(map[string]bool, len(podUIDs))
+	for _, uid := range podUIDs {
+		wanted[uid] = true
+	}
+
+	sandboxes := make(map[string]PodInfo)
+	for _, pod := range pods {
+		if !wanted[pod.Metadata.UID] {
+			continue
+		}
+		if current, ok := sandboxes[pod.Metadata.UID]; ok && current.CreatedAt >= pod.CreatedAt {
+			continue
+		}
+		sandboxes[pod.Metadata.UID] = pod
+	}
+	return sandboxes
+}
+
+// extractNetnsPathFromInfo extracts the path of the network namespace
+// from the runtime spec in the sandbox status info, or returns "" when
+// the runtime does not report one.
+func extractNetnsPathFromInfo(info map[string]interface{}) string {
+	spec, _ := info["runtimeSpec"].(map[string]interface{})
+	linux, _ := spec["linux"].(map[string]interface{})
+	namespaces, _ := linux["namespaces"].([]interface{})
+	for _, namespace := range namespaces {
+		ns, _ := namespace.(map[string]interface{})
+		if ns["type"] == "network" {
+			path, _ := ns["path"].(string)
+			return path
+		}
+	}
+	return ""
+}
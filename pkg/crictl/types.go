@@ -28,6 +28,7 @@ type ContainerPIDInfo struct {
 	ContainerID   string `json:"containerID"`
 	PID           int32  `json:"pid"`
 	Namespace     string `json:"namespace"`
+	PodSandboxID  string `json:"podSandboxID"`
 }
 
 // PodInfo represents a pod in the CRI runtime.
@@ -151,3 +152,21 @@ func processInfoField(info map[string]interface{}) map[string]interface{} {
 
 	return processedInfo
 }
+
+// PodSandboxNetnsInfo represents the network namespace of a pod
+// sandbox.
+type PodSandboxNetnsInfo struct {
+	PodName   string `json:"podName"`
+	Namespace string `json:"namespace"`
+	UID       string `json:"uid"`
+	SandboxID string `json:"sandboxID"`
+	// PID is the process ID of the sandbox, or 0 when the runtime
+	// does not report it.
+	PID int32 `json:"pid,omitempty"`
+	// NetnsPath is the path of the network namespace in the runtime
+	// spec of the sandbox, if any.
+	NetnsPath string `json:"netnsPath,omitempty"`
+	// HostNetwork is true when the sandbox uses the network
+	// namespace of the node.
+	HostNetwork bool `json:"hostNetwork"`
+}
@@ -18,6 +18,7 @@ package helpers
 
 import (
 	"fmt"
+	"slices"
 
 	bpfmaniov1alpha1 "github.com/bpfman/bpfman-operator/apis/v1alpha1"
 	bpfmanclientset "github.com/bpfman/bpfman-operator/pkg/client/clientset"
@@ -168,6 +169,7 @@ func IsBpfAppStateConditionFailure(conditions []metav1.Condition) bool {
 
 	return conditions[0].Type == string(bpfmaniov1alpha1.BpfAppStateCondError) ||
 		conditions[0].Type == string(bpfmaniov1alpha1.BpfAppStateCondProgramListChangedError) ||
+		conditions[0].Type == string(bpfmaniov1alpha1.BpfAppStateCondVerifierRejected) ||
 		conditions[0].Type == string(bpfmaniov1alpha1.BpfAppStateCondUnloadError)
 }
 
@@ -195,3 +197,56 @@ func GetPriority(priority *int32) int32 {
 	}
 	return *priority
 }
+
+// PriorityClassAllowsNamespace returns true if the applications of namespace
+// can use class. An empty namespace stands for a ClusterBpfApplication, which
+// can use every class.
+func PriorityClassAllowsNamespace(class *bpfmaniov1alpha1.BpfPriorityClass, namespace string) bool {
+	return namespace == "" || slices.Contains(class.Spec.AllowedNamespaces, namespace)
+}
+
+// GetClassPriority returns the priority of a link of an application in
+// namespace that references class. If priority is nil, it returns the default
+// priority of the class. It fails if namespace can't use the class or if
+// priority is outside of its range.
+func GetClassPriority(class *bpfmaniov1alpha1.BpfPriorityClass, namespace string, priority *int32) (int32, error) {
+	if !PriorityClassAllowsNamespace(class, namespace) {
+		return 0, fmt.Errorf("namespace %s is not allowed to use BpfPriorityClass %s", namespace, class.Name)
+	}
+	if priority == nil {
+		if class.Spec.DefaultPriority != nil {
+			return *class.Spec.DefaultPriority, nil
+		}
+		return class.Spec.MinPriority, nil
+	}
+	if *priority < class.Spec.MinPriority || *priority > class.Spec.MaxPriority {
+		return 0, fmt.Errorf("priority %d is outside of the range %d-%d of BpfPriorityClass %s",
+			*priority, class.Spec.MinPriority, class.Spec.MaxPriority, class.Name)
+	}
+	return *priority, nil
+}
+
+// CheckRawPriority returns an error if the priority of a link of an
+// application in namespace that doesn't use a BpfPriorityClass is in the
+// range of one of classes that namespace can't use. The priority is checked
+// once defaulted as by GetPriority, so that links without a priority can't
+// get a reserved one either. An empty namespace stands for a
+// ClusterBpfApplication, which can use every priority.
+func CheckRawPriority(classes []bpfmaniov1alpha1.BpfPriorityClass, namespace string, priority *int32) error {
+	effective := GetPriority(priority)
+	for i := range classes {
+		class := &classes[i]
+		if PriorityClassAllowsNamespace(class, namespace) {
+			continue
+		}
+		if effective >= class.Spec.MinPriority && effective <= class.Spec.MaxPriority {
+			what := "priority"
+			if priority == nil {
+				what = "default priority"
+			}
+			return fmt.Errorf("%s %d is reserved by BpfPriorityClass %s, which namespace %s is not allowed to use",
+				what, effective, class.Name, namespace)
+		}
+	}
+	return nil
+}
@@ -48,10 +48,12 @@ import (
 // metrics-proxy implementation.
 
 type agentMetricTestResult struct {
-	Name    string `json:"name"`
-	Status  string `json:"status"`
-	Message string `json:"message,omitempty"`
-	Metrics int    `json:"metrics,omitempty"`
+	Name        string `json:"name"`
+	Status      string `json:"status"`
+	Message     string `json:"message,omitempty"`
+	Metrics     int    `json:"metrics,omitempty"`
+	LatencyMs   int64  `json:"latencyMs"`
+	Remediation string `json:"remediation,omitempty"`
 }
 
 type agentMetricsTestResults struct {
@@ -204,20 +206,23 @@ func testMetricsProxySelfTest(ctx context.Context, t *testing.T, pod corev1.Pod,
 
 	t.Logf("Self-test summary: %s", results.Summary)
 	for _, result := range results.Results {
-		if result.Status == "passed" {
+		switch result.Status {
+		case "passed":
 			if result.Metrics > 0 {
-				t.Logf("%s: PASSED (%d metrics)", result.Name, result.Metrics)
+				t.Logf("%s: PASSED (%d metrics, %dms)", result.Name, result.Metrics, result.LatencyMs)
 			} else {
-				t.Logf("%s: PASSED", result.Name)
+				t.Logf("%s: PASSED (%dms)", result.Name, result.LatencyMs)
 			}
-		} else {
-			t.Logf("%s: FAILED - %s", result.Name, result.Message)
+		case "skipped":
+			t.Logf("%s: SKIPPED - %s", result.Name, result.Message)
+		default:
+			t.Logf("%s: FAILED - %s (remediation: %s)", result.Name, result.Message, result.Remediation)
 		}
 	}
 
 	require.Equal(t, "passed", results.Summary, "Self-test should pass overall")
 	require.NoError(t, err, "pod exec should succeed when self-test passes")
-	require.Len(t, results.Results, 3, "Should have 3 test results")
+	require.Len(t, results.Results, 7, "Should have 7 test results")
 
 	testsByName := make(map[string]agentMetricTestResult)
 	for _, result := range results.Results {
@@ -241,6 +246,17 @@ func testMetricsProxySelfTest(ctx context.Context, t *testing.T, pod corev1.Pod,
 	require.Equal(t, "passed", agentResult.Status, "Agent metrics endpoint should pass")
 	require.Greater(t, agentResult.Metrics, 0, "Should have agent metrics data")
 
+	// Diagnostic checks should pass; the TLS chain check is skipped
+	// on clusters without a service CA.
+	for _, name := range []string{"socket_permissions", "token_authorization", "metric_families"} {
+		result, exists := testsByName[name]
+		require.True(t, exists, "Should have %s result", name)
+		require.Equal(t, "passed", result.Status, "%s should pass", name)
+	}
+	tlsResult, exists := testsByName["tls_chain"]
+	require.True(t, exists, "Should have tls_chain result")
+	require.Contains(t, []string{"passed", "skipped"}, tlsResult.Status, "TLS chain check should not fail")
+
 	t.Logf("All self-tests passed successfully on pod %s", pod.Name)
 }
 